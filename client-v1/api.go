@@ -2,10 +2,18 @@ package client
 
 import (
 	"encoding/json"
+
+	"github.com/akamai/AkamaiOPEN-edgegrid-golang/jsonhooks-v1"
 )
 
 // Resource is the "base" type for all API resources
+//
+// JSON fields that the resource does not declare are retained when the
+// resource is unmarshaled, and sent back when it is marshaled.
+//
+// See: jsonhooks-v1/jsonhooks.UnknownFieldsHolder
 type Resource struct {
+	jsonhooks.UnknownFields
 	Complete chan bool `json:"-"`
 }
 
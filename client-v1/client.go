@@ -130,14 +130,18 @@ func NewMultiPartFormDataRequest(config edgegrid.Config, uriPath, filePath strin
 
 // Do performs a given HTTP Request, signed with the Akamai OPEN Edgegrid
 // Authorization header. An edgegrid.Response or an error is returned.
+//
+// Do is safe for concurrent use. Redirects are signed with config by a copy
+// of Client, so Client itself is not modified.
 func Do(config edgegrid.Config, req *http.Request) (*http.Response, error) {
-	Client.CheckRedirect = func(req *http.Request, via []*http.Request) error {
+	httpClient := *Client
+	httpClient.CheckRedirect = func(req *http.Request, via []*http.Request) error {
 		req = edgegrid.AddRequestHeader(config, req)
 		return nil
 	}
 
 	req = edgegrid.AddRequestHeader(config, req)
-	res, err := Client.Do(req)
+	res, err := httpClient.Do(req)
 	if err != nil {
 		return nil, err
 	}
@@ -2,7 +2,9 @@ package client
 
 import (
 	"net/http"
+	"net/http/httptest"
 	"strings"
+	"sync"
 	"testing"
 
 	"github.com/akamai/AkamaiOPEN-edgegrid-golang/edgegrid"
@@ -81,3 +83,41 @@ func verifyResponseConfig(t *testing.T, config edgegrid.Config, req *http.Reques
 
 	assert.True(t, strings.Contains(json["headers"].(map[string]interface{})["Authorization"].(string), "local-config"))
 }
+
+func TestDo(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/redirect" {
+			http.Redirect(w, r, "/headers", http.StatusFound)
+			return
+		}
+		assert.True(t, strings.HasPrefix(r.Header.Get("Authorization"), "EG1-HMAC-SHA256"))
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer server.Close()
+
+	config := edgegrid.Config{
+		Host:         server.URL,
+		AccessToken:  "local-config",
+		ClientSecret: "local-config",
+		ClientToken:  "local-config",
+		MaxBody:      1024,
+	}
+
+	var wg sync.WaitGroup
+	for i := 0; i < 4; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			req, err := http.NewRequest("GET", server.URL+"/redirect", nil)
+			assert.NoError(t, err)
+
+			res, err := Do(config, req)
+			assert.NoError(t, err)
+			assert.Equal(t, http.StatusOK, res.StatusCode)
+			res.Body.Close()
+		}()
+	}
+	wg.Wait()
+
+	assert.Nil(t, Client.CheckRedirect)
+}
@@ -5,6 +5,7 @@ import (
 	"fmt"
 	"github.com/akamai/AkamaiOPEN-edgegrid-golang/client-v1"
 	edge "github.com/akamai/AkamaiOPEN-edgegrid-golang/edgegrid"
+	"github.com/akamai/AkamaiOPEN-edgegrid-golang/jsonhooks-v1"
 	"io/ioutil"
 	"log"
 	"reflect"
@@ -48,6 +49,7 @@ type ZoneQueryString struct {
 }
 
 type ZoneCreate struct {
+	jsonhooks.UnknownFields
 	Zone                  string   `json:"zone"`
 	Type                  string   `json:"type"`
 	Masters               []string `json:"masters,omitempty"`
@@ -636,7 +638,10 @@ func filterZoneCreate(zone *ZoneCreate) map[string]interface{} {
 	zoneElems := reflect.ValueOf(zone).Elem()
 	for i := 0; i < zoneElems.NumField(); i++ {
 		varName := zoneElems.Type().Field(i).Name
-		varLower := zoneStructMap[varName]
+		varLower, ok := zoneStructMap[varName]
+		if !ok {
+			continue
+		}
 		varValue := zoneElems.Field(i).Interface()
 		switch varName {
 		case "Target":
@@ -664,6 +669,13 @@ func filterZoneCreate(zone *ZoneCreate) map[string]interface{} {
 		}
 	}
 
+	// Send back any fields the server returned that ZoneCreate doesn't declare
+	for key, value := range zone.GetUnknownFields() {
+		if _, ok := filteredZone[key]; !ok {
+			filteredZone[key] = value
+		}
+	}
+
 	return filteredZone
 
 }
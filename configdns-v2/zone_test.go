@@ -26,6 +26,25 @@ func TestZone_JSON(t *testing.T) {
 	assert.Equal(t, zone.SignAndServe, false)
 }
 
+func TestZone_UnknownFields(t *testing.T) {
+	responseBody := []byte(`{
+    "zone": "example.com",
+    "type": "PRIMARY",
+    "comment": "This is a test zone",
+    "signAndServe": false,
+    "outboundZoneTransfer": {"enabled": true}
+}`)
+
+	zone := &ZoneCreate{}
+	err := jsonhooks.Unmarshal(responseBody, zone)
+	assert.NoError(t, err)
+
+	zoneMap := filterZoneCreate(zone)
+	assert.Equal(t, "example.com", zoneMap["zone"])
+	assert.JSONEq(t, `{"enabled": true}`, fmt.Sprintf("%s", zoneMap["outboundZoneTransfer"]))
+	assert.NotContains(t, zoneMap, "")
+}
+
 func TestGetZoneNames(t *testing.T) {
 
 	dnsTestZone := "testzone.com"
@@ -3,6 +3,7 @@ package configgtm
 import (
 	"fmt"
 	"github.com/akamai/AkamaiOPEN-edgegrid-golang/client-v1"
+	"github.com/akamai/AkamaiOPEN-edgegrid-golang/jsonhooks-v1"
 	"net/http"
 	"strings"
 )
@@ -13,7 +14,10 @@ import (
 //
 
 // The Domain data structure represents a GTM domain
+//
+// Fields not declared here are retained on read and sent back on update.
 type Domain struct {
+	jsonhooks.UnknownFields
 	Name                         string          `json:"name"`
 	Type                         string          `json:"type"`
 	AsMaps                       []*AsMap        `json:"asMaps,omitempty"`
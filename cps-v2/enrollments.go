@@ -1,11 +1,11 @@
 package cps
 
 import (
-	"encoding/json"
 	"fmt"
 	"time"
 
 	client "github.com/akamai/AkamaiOPEN-edgegrid-golang/client-v1"
+	"github.com/akamai/AkamaiOPEN-edgegrid-golang/jsonhooks-v1"
 )
 
 // Enrollments represents an enrollment
@@ -196,7 +196,7 @@ func (enrollment *Enrollment) Exists(enrollments []Enrollment) bool {
 // CreateEnrollment wraps enrollment.Create to accept json
 func CreateEnrollment(data []byte, params CreateEnrollmentQueryParams) (*CreateEnrollmentResponse, error) {
 	var enrollment Enrollment
-	if err := json.Unmarshal(data, &enrollment); err != nil {
+	if err := jsonhooks.Unmarshal(data, &enrollment); err != nil {
 		return nil, err
 	}
 
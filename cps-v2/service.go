@@ -2,12 +2,12 @@ package cps
 
 import (
 	"bytes"
-	"encoding/json"
 	"log"
 	"net/http"
 
 	client "github.com/akamai/AkamaiOPEN-edgegrid-golang/client-v1"
 	"github.com/akamai/AkamaiOPEN-edgegrid-golang/edgegrid"
+	"github.com/akamai/AkamaiOPEN-edgegrid-golang/jsonhooks-v1"
 )
 
 var (
@@ -22,11 +22,11 @@ func Init(config edgegrid.Config) {
 }
 
 func newRequest(method, urlStr string, body interface{}) (*http.Request, error) {
-	buf := new(bytes.Buffer)
-	err := json.NewEncoder(buf).Encode(body)
+	jsonBody, err := jsonhooks.Marshal(body)
 	if err != nil {
 		return nil, err
 	}
+	buf := bytes.NewBuffer(jsonBody)
 
 	log.Printf("[DEBUG] newRequest, buf: %s", string(buf.Bytes()))
 
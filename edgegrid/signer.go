@@ -12,6 +12,7 @@ import (
 	"os"
 	"sort"
 	"strings"
+	"sync"
 	"time"
 	"unicode"
 
@@ -21,15 +22,21 @@ import (
 
 const defaultSection = "DEFAULT"
 
+// logSetup serializes setting up logging when requests are signed concurrently
+var logSetup sync.Mutex
+
 // AddRequestHeader sets the Authorization header to use Akamai Open API
 func AddRequestHeader(config Config, req *http.Request) *http.Request {
 
+	logSetup.Lock()
 	if EdgegridLog == nil {
 		SetupLogging()
 		if config.Debug {
 			EdgegridLog.SetLevel(logrus.DebugLevel)
 		}
 	}
+	logSetup.Unlock()
+
 	timestamp := makeEdgeTimeStamp()
 	EdgegridLog.Debugf("Timestamp: '%s'", timestamp)
 	nonce := createNonce()
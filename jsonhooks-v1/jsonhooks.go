@@ -1,5 +1,8 @@
 // Package jsonhooks adds hooks that are automatically called before JSON marshaling (PreMarshalJSON) and
 // after JSON unmarshaling (PostUnmarshalJSON). It does not do so recursively.
+//
+// Types implementing UnknownFieldsHolder additionally retain JSON members that have no matching
+// struct field, and have them written back when marshaled. This is done recursively.
 package jsonhooks
 
 import (
@@ -8,6 +11,7 @@ import (
 )
 
 // Marshal wraps encoding/json.Marshal, calls v.PreMarshalJSON() if it exists
+// and writes back any retained unknown fields
 func Marshal(v interface{}) ([]byte, error) {
 	if ImplementsPreJSONMarshaler(v) {
 		err := v.(PreJSONMarshaler).PreMarshalJSON()
@@ -16,16 +20,24 @@ func Marshal(v interface{}) ([]byte, error) {
 		}
 	}
 
-	return json.Marshal(v)
+	data, err := json.Marshal(v)
+	if err != nil {
+		return nil, err
+	}
+
+	return marshalUnknownFields(data, v)
 }
 
-// Unmarshal wraps encoding/json.Unmarshal, calls v.PostUnmarshalJSON() if it exists
+// Unmarshal wraps encoding/json.Unmarshal, retains unknown fields and calls
+// v.PostUnmarshalJSON() if it exists
 func Unmarshal(data []byte, v interface{}) error {
 	err := json.Unmarshal(data, v)
 	if err != nil {
 		return err
 	}
 
+	captureUnknownFields(data, reflect.ValueOf(v))
+
 	if ImplementsPostJSONUnmarshaler(v) {
 		err := v.(PostJSONUnmarshaler).PostUnmarshalJSON()
 		if err != nil {
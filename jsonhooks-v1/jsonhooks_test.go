@@ -208,3 +208,105 @@ func TestPostJSONUnmarshal(t *testing.T) {
 	assert.NotEqual(t, expected, withoutHooks)
 	assert.Equal(t, expected, withHooks)
 }
+
+type WithUnknownFields struct {
+	UnknownFields
+	Name     string                        `json:"name"`
+	Child    *WithUnknownFields            `json:"child,omitempty"`
+	Children []*WithUnknownFields          `json:"children,omitempty"`
+	ByName   map[string]*WithUnknownFields `json:"byName,omitempty"`
+}
+
+func TestImplementsUnknownFieldsHolder(t *testing.T) {
+	assert.False(t, ImplementsUnknownFieldsHolder(&MixedTypes{}))
+	assert.True(t, ImplementsUnknownFieldsHolder(&WithUnknownFields{}))
+}
+
+func TestUnmarshalUnknownFields(t *testing.T) {
+	data := []byte(`{
+		"name": "parent",
+		"newSetting": {"enabled": true},
+		"child": {"name": "child", "newChildSetting": 1},
+		"children": [{"name": "first"}, {"name": "second", "extra": "value"}],
+		"byName": {"third": {"name": "third", "more": [1, 2]}}
+	}`)
+
+	actual := &WithUnknownFields{}
+	err := Unmarshal(data, actual)
+
+	assert.NoError(t, err)
+	assert.Equal(t, "parent", actual.Name)
+	assert.Len(t, actual.GetUnknownFields(), 1)
+	assert.JSONEq(t, `{"enabled": true}`, string(actual.GetUnknownFields()["newSetting"]))
+	assert.JSONEq(t, `1`, string(actual.Child.GetUnknownFields()["newChildSetting"]))
+	assert.Nil(t, actual.Children[0].GetUnknownFields())
+	assert.JSONEq(t, `"value"`, string(actual.Children[1].GetUnknownFields()["extra"]))
+	assert.JSONEq(t, `[1, 2]`, string(actual.ByName["third"].GetUnknownFields()["more"]))
+}
+
+func TestUnmarshalUnknownFields_CaseInsensitive(t *testing.T) {
+	data := []byte(`{
+		"Name": "parent",
+		"CHILD": {"name": "child", "newChildSetting": 1},
+		"Children": [{"NAME": "first", "extra": "value"}]
+	}`)
+
+	actual := &WithUnknownFields{}
+	err := Unmarshal(data, actual)
+
+	assert.NoError(t, err)
+	assert.Nil(t, actual.GetUnknownFields())
+	assert.Equal(t, "child", actual.Child.Name)
+	assert.JSONEq(t, `1`, string(actual.Child.GetUnknownFields()["newChildSetting"]))
+	assert.JSONEq(t, `"value"`, string(actual.Children[0].GetUnknownFields()["extra"]))
+}
+
+func TestUnmarshalUnknownFields_MismatchedTypes(t *testing.T) {
+	actual := &WithUnknownFields{}
+	err := Unmarshal([]byte(`{"name": "parent", "child": null, "children": [], "byName": {}, "extra": [{"a": [1, {"b": 2}]}]}`), actual)
+
+	assert.NoError(t, err)
+	assert.Len(t, actual.GetUnknownFields(), 1)
+	assert.JSONEq(t, `[{"a": [1, {"b": 2}]}]`, string(actual.GetUnknownFields()["extra"]))
+}
+
+func TestMarshalUnknownFields(t *testing.T) {
+	data := `{
+		"name": "parent",
+		"newSetting": {"enabled": true, "ratio": 0.125},
+		"child": {"name": "child", "newChildSetting": 12345678901234567890},
+		"children": [{"name": "first"}, {"name": "second", "extra": "value"}],
+		"byName": {"third": {"name": "third", "more": [1, 2]}}
+	}`
+
+	value := &WithUnknownFields{}
+	err := Unmarshal([]byte(data), value)
+	assert.NoError(t, err)
+
+	actual, err := Marshal(value)
+	assert.NoError(t, err)
+	assert.JSONEq(t, data, string(actual))
+
+	value.Name = "renamed"
+	value.Child.SetUnknownFields(nil)
+
+	actual, err = Marshal(*value)
+	assert.NoError(t, err)
+	assert.JSONEq(t, `{
+		"name": "renamed",
+		"newSetting": {"enabled": true, "ratio": 0.125},
+		"child": {"name": "child"},
+		"children": [{"name": "first"}, {"name": "second", "extra": "value"}],
+		"byName": {"third": {"name": "third", "more": [1, 2]}}
+	}`, string(actual))
+}
+
+func TestMarshalWithoutUnknownFields(t *testing.T) {
+	value := &WithUnknownFields{Name: "test", Child: &WithUnknownFields{Name: "child"}}
+
+	expected, _ := json.Marshal(value)
+	actual, err := Marshal(value)
+
+	assert.NoError(t, err)
+	assert.Equal(t, string(expected), string(actual))
+}
@@ -0,0 +1,498 @@
+package jsonhooks
+
+import (
+	"bytes"
+	"encoding/json"
+	"reflect"
+	"strings"
+	"sync"
+)
+
+// UnknownFieldsHolder infers support for retaining JSON object members that
+// have no matching struct field.
+//
+// Unmarshal stores unknown members via SetUnknownFields, and Marshal writes
+// them back out, so a read-modify-write cycle does not drop fields added to
+// the API after the Go type was written. Unlike the pre/post hooks, unknown
+// fields are tracked for nested values too.
+type UnknownFieldsHolder interface {
+	GetUnknownFields() map[string]json.RawMessage
+	SetUnknownFields(fields map[string]json.RawMessage)
+}
+
+// ImplementsUnknownFieldsHolder checks for support for retaining unknown fields
+func ImplementsUnknownFieldsHolder(v interface{}) bool {
+	value := reflect.ValueOf(v)
+	if value.Kind() == reflect.Ptr && value.IsNil() {
+		return false
+	}
+
+	_, ok := value.Interface().(UnknownFieldsHolder)
+	return ok
+}
+
+// UnknownFields is an embeddable implementation of UnknownFieldsHolder
+type UnknownFields struct {
+	unknownFields map[string]json.RawMessage
+}
+
+// GetUnknownFields returns the JSON members retained by the last Unmarshal
+func (fields *UnknownFields) GetUnknownFields() map[string]json.RawMessage {
+	return fields.unknownFields
+}
+
+// SetUnknownFields replaces the retained JSON members
+func (fields *UnknownFields) SetUnknownFields(unknownFields map[string]json.RawMessage) {
+	fields.unknownFields = unknownFields
+}
+
+var (
+	unknownFieldsHolderType = reflect.TypeOf((*UnknownFieldsHolder)(nil)).Elem()
+
+	// fieldsCache and holderCache memoize jsonFields and mayHoldUnknownFields
+	// by type, as both are needed on every Marshal and Unmarshal
+	fieldsCache sync.Map
+	holderCache sync.Map
+)
+
+// jsonField is a struct field as seen by encoding/json
+type jsonField struct {
+	name  string
+	index []int
+	typ   reflect.Type
+}
+
+// jsonFields lists the fields encoding/json would use for t, flattening
+// embedded structs the same way encoding/json does.
+func jsonFields(t reflect.Type) []jsonField {
+	if cached, ok := fieldsCache.Load(t); ok {
+		return cached.([]jsonField)
+	}
+
+	var fields []jsonField
+	names := map[string]bool{}
+
+	var walk func(t reflect.Type, index []int)
+	walk = func(t reflect.Type, index []int) {
+		for i := 0; i < t.NumField(); i++ {
+			field := t.Field(i)
+			tag := field.Tag.Get("json")
+			if tag == "-" {
+				continue
+			}
+
+			name := strings.Split(tag, ",")[0]
+			fieldIndex := append(append([]int{}, index...), i)
+
+			if field.Anonymous && name == "" {
+				ft := field.Type
+				if ft.Kind() == reflect.Ptr {
+					ft = ft.Elem()
+				}
+				if ft.Kind() == reflect.Struct {
+					walk(ft, fieldIndex)
+					continue
+				}
+			}
+
+			if field.PkgPath != "" {
+				continue
+			}
+
+			if name == "" {
+				name = field.Name
+			}
+
+			// Shallower fields win, as with encoding/json
+			if names[name] {
+				continue
+			}
+			names[name] = true
+
+			fields = append(fields, jsonField{name: name, index: fieldIndex, typ: field.Type})
+		}
+	}
+	walk(t, nil)
+
+	fieldsCache.Store(t, fields)
+	return fields
+}
+
+// matchField returns the field encoding/json decodes key into, preferring an
+// exact match over a case-insensitive one as encoding/json does
+func matchField(fields []jsonField, key string) (int, bool) {
+	for i, field := range fields {
+		if field.name == key {
+			return i, true
+		}
+	}
+
+	for i, field := range fields {
+		if strings.EqualFold(field.name, key) {
+			return i, true
+		}
+	}
+
+	return 0, false
+}
+
+// mayHoldUnknownFields reports whether a value of type t can reach an
+// UnknownFieldsHolder, so that values which cannot are not walked at all.
+// Interfaces may hold anything, so they always may.
+func mayHoldUnknownFields(t reflect.Type) bool {
+	if cached, ok := holderCache.Load(t); ok {
+		return cached.(bool)
+	}
+
+	result := reachesHolder(t, map[reflect.Type]bool{})
+	holderCache.Store(t, result)
+
+	return result
+}
+
+// reachesHolder searches the types reachable from t for an
+// UnknownFieldsHolder, visiting each type once
+func reachesHolder(t reflect.Type, seen map[reflect.Type]bool) bool {
+	if seen[t] {
+		return false
+	}
+	seen[t] = true
+
+	if t.Kind() != reflect.Ptr && reflect.PtrTo(t).Implements(unknownFieldsHolderType) {
+		return true
+	}
+
+	switch t.Kind() {
+	case reflect.Interface:
+		return true
+	case reflect.Ptr, reflect.Slice, reflect.Array, reflect.Map:
+		return reachesHolder(t.Elem(), seen)
+	case reflect.Struct:
+		for _, field := range jsonFields(t) {
+			if reachesHolder(field.typ, seen) {
+				return true
+			}
+		}
+	}
+
+	return false
+}
+
+// fieldByIndex is reflect.Value.FieldByIndex without the panic on nil
+// embedded pointers
+func fieldByIndex(v reflect.Value, index []int) (reflect.Value, bool) {
+	for i, x := range index {
+		if i > 0 && v.Kind() == reflect.Ptr {
+			if v.IsNil() {
+				return reflect.Value{}, false
+			}
+			v = v.Elem()
+		}
+		v = v.Field(x)
+	}
+
+	return v, true
+}
+
+// asUnknownFieldsHolder returns v as an UnknownFieldsHolder, if it is one
+func asUnknownFieldsHolder(v reflect.Value) (UnknownFieldsHolder, bool) {
+	if !v.CanAddr() || !v.Addr().Type().Implements(unknownFieldsHolderType) {
+		return nil, false
+	}
+
+	return v.Addr().Interface().(UnknownFieldsHolder), true
+}
+
+// jsonNode is the structure of a JSON document, parsed once so that
+// captureUnknownFields does not decode each nesting level again. Raw spans
+// point into the original document.
+type jsonNode struct {
+	raw      json.RawMessage
+	object   bool
+	members  []jsonMember
+	array    bool
+	elements []*jsonNode
+}
+
+// jsonMember is an object member, in document order
+type jsonMember struct {
+	key   string
+	value *jsonNode
+}
+
+// parseJSONNode parses data into a tree of jsonNodes
+func parseJSONNode(data []byte) (*jsonNode, error) {
+	decoder := json.NewDecoder(bytes.NewReader(data))
+	decoder.UseNumber()
+
+	return parseNextJSONNode(decoder, data)
+}
+
+func parseNextJSONNode(decoder *json.Decoder, data []byte) (*jsonNode, error) {
+	// The offset is the end of the previous token, so the span is trimmed
+	// of the separators and whitespace before the value
+	start := decoder.InputOffset()
+
+	token, err := decoder.Token()
+	if err != nil {
+		return nil, err
+	}
+
+	node := &jsonNode{}
+	switch token {
+	case json.Delim('{'):
+		node.object = true
+		for decoder.More() {
+			key, err := decoder.Token()
+			if err != nil {
+				return nil, err
+			}
+
+			value, err := parseNextJSONNode(decoder, data)
+			if err != nil {
+				return nil, err
+			}
+
+			node.members = append(node.members, jsonMember{key: key.(string), value: value})
+		}
+		if _, err := decoder.Token(); err != nil {
+			return nil, err
+		}
+	case json.Delim('['):
+		node.array = true
+		for decoder.More() {
+			element, err := parseNextJSONNode(decoder, data)
+			if err != nil {
+				return nil, err
+			}
+
+			node.elements = append(node.elements, element)
+		}
+		if _, err := decoder.Token(); err != nil {
+			return nil, err
+		}
+	}
+
+	node.raw = bytes.TrimLeft(data[start:decoder.InputOffset()], " \t\r\n:,")
+
+	return node, nil
+}
+
+// captureUnknownFields walks v alongside the JSON it was decoded from, and
+// stores any members without a matching field in the relevant holder
+func captureUnknownFields(data []byte, v reflect.Value) {
+	if !v.IsValid() || !mayHoldUnknownFields(v.Type()) {
+		return
+	}
+
+	node, err := parseJSONNode(data)
+	if err != nil {
+		return
+	}
+
+	captureNodeUnknownFields(node, v)
+}
+
+func captureNodeUnknownFields(node *jsonNode, v reflect.Value) {
+	for v.Kind() == reflect.Ptr || v.Kind() == reflect.Interface {
+		if v.IsNil() {
+			return
+		}
+		v = v.Elem()
+	}
+
+	if !mayHoldUnknownFields(v.Type()) {
+		return
+	}
+
+	switch v.Kind() {
+	case reflect.Struct:
+		if !node.object {
+			return
+		}
+
+		fields := jsonFields(v.Type())
+		// As with encoding/json, the last member matching a field wins
+		values := make([]*jsonNode, len(fields))
+		var unknown map[string]json.RawMessage
+		for _, member := range node.members {
+			if i, ok := matchField(fields, member.key); ok {
+				values[i] = member.value
+				continue
+			}
+
+			if unknown == nil {
+				unknown = map[string]json.RawMessage{}
+			}
+			unknown[member.key] = append(json.RawMessage{}, member.value.raw...)
+		}
+
+		if holder, ok := asUnknownFieldsHolder(v); ok {
+			holder.SetUnknownFields(unknown)
+		}
+
+		for i, field := range fields {
+			if values[i] == nil {
+				continue
+			}
+			if fv, ok := fieldByIndex(v, field.index); ok {
+				captureNodeUnknownFields(values[i], fv)
+			}
+		}
+	case reflect.Slice, reflect.Array:
+		if !node.array {
+			return
+		}
+
+		for i := 0; i < len(node.elements) && i < v.Len(); i++ {
+			captureNodeUnknownFields(node.elements[i], v.Index(i))
+		}
+	case reflect.Map:
+		if !node.object || v.Type().Key().Kind() != reflect.String {
+			return
+		}
+
+		for _, member := range node.members {
+			// Map elements are not addressable, only pointers to structs can be updated
+			if element := v.MapIndex(reflect.ValueOf(member.key).Convert(v.Type().Key())); element.IsValid() {
+				captureNodeUnknownFields(member.value, element)
+			}
+		}
+	}
+}
+
+// hasUnknownFields reports whether v, or anything reachable from it, holds
+// unknown fields
+func hasUnknownFields(v reflect.Value) bool {
+	for v.Kind() == reflect.Ptr || v.Kind() == reflect.Interface {
+		if v.IsNil() {
+			return false
+		}
+		v = v.Elem()
+	}
+
+	if !mayHoldUnknownFields(v.Type()) {
+		return false
+	}
+
+	switch v.Kind() {
+	case reflect.Struct:
+		if holder, ok := asUnknownFieldsHolder(v); ok && len(holder.GetUnknownFields()) > 0 {
+			return true
+		}
+
+		for _, field := range jsonFields(v.Type()) {
+			if fv, ok := fieldByIndex(v, field.index); ok && hasUnknownFields(fv) {
+				return true
+			}
+		}
+	case reflect.Slice, reflect.Array:
+		for i := 0; i < v.Len(); i++ {
+			if hasUnknownFields(v.Index(i)) {
+				return true
+			}
+		}
+	case reflect.Map:
+		iter := v.MapRange()
+		for iter.Next() {
+			if hasUnknownFields(iter.Value()) {
+				return true
+			}
+		}
+	}
+
+	return false
+}
+
+// mergeUnknownFields adds the unknown fields held within v into node, the
+// generic decoding of v's JSON encoding
+func mergeUnknownFields(node interface{}, v reflect.Value) {
+	for v.Kind() == reflect.Ptr || v.Kind() == reflect.Interface {
+		if v.IsNil() {
+			return
+		}
+		v = v.Elem()
+	}
+
+	if !mayHoldUnknownFields(v.Type()) {
+		return
+	}
+
+	switch v.Kind() {
+	case reflect.Struct:
+		members, ok := node.(map[string]interface{})
+		if !ok {
+			return
+		}
+
+		if holder, ok := asUnknownFieldsHolder(v); ok {
+			for key, value := range holder.GetUnknownFields() {
+				if _, exists := members[key]; !exists {
+					members[key] = value
+				}
+			}
+		}
+
+		for _, field := range jsonFields(v.Type()) {
+			member, ok := members[field.name]
+			if !ok {
+				continue
+			}
+			if fv, ok := fieldByIndex(v, field.index); ok {
+				mergeUnknownFields(member, fv)
+			}
+		}
+	case reflect.Slice, reflect.Array:
+		elements, ok := node.([]interface{})
+		if !ok {
+			return
+		}
+
+		for i := 0; i < len(elements) && i < v.Len(); i++ {
+			mergeUnknownFields(elements[i], v.Index(i))
+		}
+	case reflect.Map:
+		members, ok := node.(map[string]interface{})
+		if !ok || v.Type().Key().Kind() != reflect.String {
+			return
+		}
+
+		iter := v.MapRange()
+		for iter.Next() {
+			if member, ok := members[iter.Key().String()]; ok {
+				mergeUnknownFields(member, iter.Value())
+			}
+		}
+	}
+}
+
+// marshalUnknownFields re-encodes data, the encoding of v, with the unknown
+// fields held within v added back in
+func marshalUnknownFields(data []byte, v interface{}) ([]byte, error) {
+	value := reflect.ValueOf(v)
+	if !value.IsValid() || !mayHoldUnknownFields(value.Type()) {
+		return data, nil
+	}
+
+	if value.Kind() != reflect.Ptr {
+		// Holders are only reachable through addressable values
+		addressable := reflect.New(value.Type()).Elem()
+		addressable.Set(value)
+		value = addressable
+	}
+
+	if !hasUnknownFields(value) {
+		return data, nil
+	}
+
+	var node interface{}
+	decoder := json.NewDecoder(bytes.NewReader(data))
+	decoder.UseNumber()
+	if err := decoder.Decode(&node); err != nil {
+		return nil, err
+	}
+
+	mergeUnknownFields(node, value)
+
+	return json.Marshal(node)
+}
@@ -0,0 +1,293 @@
+package jsonpatch
+
+import (
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"strconv"
+
+	"github.com/akamai/AkamaiOPEN-edgegrid-golang/jsonhooks-v1"
+)
+
+// Apply applies the patch to a JSON document, returning the patched document
+//
+// The document is left untouched if any operation fails.
+func (patch Patch) Apply(document []byte) ([]byte, error) {
+	doc, err := toDocument(document)
+	if err != nil {
+		return nil, err
+	}
+
+	if doc, err = patch.apply(doc); err != nil {
+		return nil, err
+	}
+
+	return json.Marshal(doc)
+}
+
+// ApplyTo applies the patch to v, which must be a pointer
+//
+// v is marshaled, patched, reset to its zero value and unmarshaled from the
+// patched document, so fields removed by the patch are cleared.
+func (patch Patch) ApplyTo(v interface{}) error {
+	value := reflect.ValueOf(v)
+	if value.Kind() != reflect.Ptr || value.IsNil() {
+		return fmt.Errorf("%w: ApplyTo requires a non-nil pointer", ErrInvalidOperation)
+	}
+
+	doc, err := toDocument(v)
+	if err != nil {
+		return err
+	}
+
+	if doc, err = patch.apply(doc); err != nil {
+		return err
+	}
+
+	data, err := json.Marshal(doc)
+	if err != nil {
+		return err
+	}
+
+	value.Elem().Set(reflect.Zero(value.Elem().Type()))
+
+	return jsonhooks.Unmarshal(data, v)
+}
+
+// apply applies the patch to a generic document
+func (patch Patch) apply(doc interface{}) (interface{}, error) {
+	var err error
+	for _, operation := range patch {
+		if doc, err = operation.apply(doc); err != nil {
+			return nil, fmt.Errorf("%s: %w", operation, err)
+		}
+	}
+
+	return doc, nil
+}
+
+// apply applies a single operation to a generic document
+func (operation Operation) apply(doc interface{}) (interface{}, error) {
+	path, err := ParsePointer(operation.Path)
+	if err != nil {
+		return nil, err
+	}
+
+	switch operation.Op {
+	case OpAdd:
+		value, err := toDocument(operation.Value)
+		if err != nil {
+			return nil, err
+		}
+		return add(doc, path, value)
+	case OpRemove:
+		doc, _, err := remove(doc, path)
+		return doc, err
+	case OpReplace:
+		value, err := toDocument(operation.Value)
+		if err != nil {
+			return nil, err
+		}
+		if doc, _, err = remove(doc, path); err != nil {
+			return nil, err
+		}
+		return add(doc, path, value)
+	case OpMove:
+		from, err := ParsePointer(operation.From)
+		if err != nil {
+			return nil, err
+		}
+		if len(from) < len(path) && reflect.DeepEqual(from, path[:len(from)]) {
+			return nil, fmt.Errorf("%w: cannot move %s into itself", ErrInvalidOperation, operation.From)
+		}
+		doc, value, err := remove(doc, from)
+		if err != nil {
+			return nil, err
+		}
+		return add(doc, path, value)
+	case OpCopy:
+		from, err := ParsePointer(operation.From)
+		if err != nil {
+			return nil, err
+		}
+		value, err := get(doc, from)
+		if err != nil {
+			return nil, err
+		}
+		if value, err = deepCopy(value); err != nil {
+			return nil, err
+		}
+		return add(doc, path, value)
+	case OpTest:
+		expected, err := toDocument(operation.Value)
+		if err != nil {
+			return nil, err
+		}
+		actual, err := get(doc, path)
+		if err != nil {
+			return nil, err
+		}
+		if !equal(expected, actual) {
+			return nil, ErrTestFailed
+		}
+		return doc, nil
+	}
+
+	return nil, fmt.Errorf("%w: unknown op %q", ErrInvalidOperation, operation.Op)
+}
+
+// Get resolves a JSON pointer within v
+func Get(v interface{}, pointer string) (interface{}, error) {
+	doc, err := toDocument(v)
+	if err != nil {
+		return nil, err
+	}
+
+	path, err := ParsePointer(pointer)
+	if err != nil {
+		return nil, err
+	}
+
+	return get(doc, path)
+}
+
+func get(doc interface{}, path []string) (interface{}, error) {
+	for key, token := range path {
+		switch node := doc.(type) {
+		case map[string]interface{}:
+			value, ok := node[token]
+			if !ok {
+				return nil, fmt.Errorf("%w: %s", ErrPathNotFound, FormatPointer(path[:key+1]...))
+			}
+			doc = value
+		case []interface{}:
+			index, err := arrayIndex(token, len(node), false)
+			if err != nil {
+				return nil, err
+			}
+			doc = node[index]
+		default:
+			return nil, fmt.Errorf("%w: %s", ErrPathNotFound, FormatPointer(path[:key+1]...))
+		}
+	}
+
+	return doc, nil
+}
+
+// add inserts value at path, returning the (possibly new) document root
+func add(doc interface{}, path []string, value interface{}) (interface{}, error) {
+	if len(path) == 0 {
+		return value, nil
+	}
+
+	parent, err := get(doc, path[:len(path)-1])
+	if err != nil {
+		return nil, err
+	}
+
+	token := path[len(path)-1]
+	switch node := parent.(type) {
+	case map[string]interface{}:
+		node[token] = value
+		return doc, nil
+	case []interface{}:
+		index, err := arrayIndex(token, len(node), true)
+		if err != nil {
+			return nil, err
+		}
+		node = append(node, nil)
+		copy(node[index+1:], node[index:])
+		node[index] = value
+		return set(doc, path[:len(path)-1], node)
+	}
+
+	return nil, fmt.Errorf("%w: %s", ErrPathNotFound, FormatPointer(path...))
+}
+
+// remove deletes the value at path, returning the document and the removed value
+func remove(doc interface{}, path []string) (interface{}, interface{}, error) {
+	if len(path) == 0 {
+		return nil, doc, nil
+	}
+
+	parent, err := get(doc, path[:len(path)-1])
+	if err != nil {
+		return nil, nil, err
+	}
+
+	token := path[len(path)-1]
+	switch node := parent.(type) {
+	case map[string]interface{}:
+		value, ok := node[token]
+		if !ok {
+			return nil, nil, fmt.Errorf("%w: %s", ErrPathNotFound, FormatPointer(path...))
+		}
+		delete(node, token)
+		return doc, value, nil
+	case []interface{}:
+		index, err := arrayIndex(token, len(node), false)
+		if err != nil {
+			return nil, nil, err
+		}
+		value := node[index]
+		node = append(node[:index:index], node[index+1:]...)
+		doc, err = set(doc, path[:len(path)-1], node)
+		return doc, value, err
+	}
+
+	return nil, nil, fmt.Errorf("%w: %s", ErrPathNotFound, FormatPointer(path...))
+}
+
+// set replaces the container at path, as slices change identity when resized
+func set(doc interface{}, path []string, value interface{}) (interface{}, error) {
+	if len(path) == 0 {
+		return value, nil
+	}
+
+	parent, err := get(doc, path[:len(path)-1])
+	if err != nil {
+		return nil, err
+	}
+
+	token := path[len(path)-1]
+	switch node := parent.(type) {
+	case map[string]interface{}:
+		node[token] = value
+	case []interface{}:
+		index, err := arrayIndex(token, len(node), false)
+		if err != nil {
+			return nil, err
+		}
+		node[index] = value
+	}
+
+	return doc, nil
+}
+
+// arrayIndex parses an array reference token; "-" refers past the last
+// element and is only valid when appending
+func arrayIndex(token string, length int, appending bool) (int, error) {
+	if token == "-" && appending {
+		return length, nil
+	}
+
+	index, err := strconv.Atoi(token)
+	if err != nil || index < 0 || (token != "0" && token[0] == '0') {
+		return 0, fmt.Errorf("%w: invalid array index %q", ErrInvalidPointer, token)
+	}
+
+	if index > length || (!appending && index == length) {
+		return 0, fmt.Errorf("%w: array index %d out of range", ErrPathNotFound, index)
+	}
+
+	return index, nil
+}
+
+func deepCopy(value interface{}) (interface{}, error) {
+	data, err := json.Marshal(value)
+	if err != nil {
+		return nil, err
+	}
+
+	return toDocument(data)
+}
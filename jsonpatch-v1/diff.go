@@ -0,0 +1,184 @@
+package jsonpatch
+
+import (
+	"encoding/json"
+	"sort"
+)
+
+// CreatePatch computes the patch that turns original into modified
+//
+// Both values may be raw JSON or any value that marshals to JSON.
+func CreatePatch(original, modified interface{}) (Patch, error) {
+	from, err := toDocument(original)
+	if err != nil {
+		return nil, err
+	}
+
+	to, err := toDocument(modified)
+	if err != nil {
+		return nil, err
+	}
+
+	return diff(nil, from, to, ""), nil
+}
+
+// diff appends the operations turning from into to, located at pointer
+func diff(patch Patch, from, to interface{}, pointer string) Patch {
+	switch fromNode := from.(type) {
+	case map[string]interface{}:
+		toNode, ok := to.(map[string]interface{})
+		if !ok {
+			break
+		}
+
+		// Sorted for deterministic patches
+		var keys []string
+		for key := range fromNode {
+			keys = append(keys, key)
+		}
+		for key := range toNode {
+			if _, ok := fromNode[key]; !ok {
+				keys = append(keys, key)
+			}
+		}
+		sort.Strings(keys)
+
+		for _, key := range keys {
+			fromValue, inFrom := fromNode[key]
+			toValue, inTo := toNode[key]
+			path := pointer + FormatPointer(key)
+
+			switch {
+			case !inTo:
+				patch = append(patch, Operation{Op: OpRemove, Path: path})
+			case !inFrom:
+				patch = append(patch, Operation{Op: OpAdd, Path: path, Value: toValue})
+			default:
+				patch = diff(patch, fromValue, toValue, path)
+			}
+		}
+
+		return patch
+	case []interface{}:
+		toNode, ok := to.([]interface{})
+		if !ok {
+			break
+		}
+
+		return diffArray(patch, fromNode, toNode, pointer)
+	}
+
+	if !equal(from, to) {
+		patch = append(patch, Operation{Op: OpReplace, Path: pointer, Value: to})
+	}
+
+	return patch
+}
+
+// diffArray aligns the two arrays on their identity keys and emits removals,
+// insertions and nested patches in an order that can be applied sequentially
+func diffArray(patch Patch, from, to []interface{}, pointer string) Patch {
+	fromKeys := make([]string, len(from))
+	for key, value := range from {
+		fromKeys[key] = identity(value)
+	}
+
+	toKeys := make([]string, len(to))
+	for key, value := range to {
+		toKeys[key] = identity(value)
+	}
+
+	// Longest common subsequence of identity keys
+	lcs := make([][]int, len(from)+1)
+	for i := range lcs {
+		lcs[i] = make([]int, len(to)+1)
+	}
+	for i := len(from) - 1; i >= 0; i-- {
+		for j := len(to) - 1; j >= 0; j-- {
+			if fromKeys[i] == toKeys[j] {
+				lcs[i][j] = lcs[i+1][j+1] + 1
+			} else if lcs[i+1][j] >= lcs[i][j+1] {
+				lcs[i][j] = lcs[i+1][j]
+			} else {
+				lcs[i][j] = lcs[i][j+1]
+			}
+		}
+	}
+
+	i, j, index := 0, 0, 0
+	for i < len(from) || j < len(to) {
+		switch {
+		case i < len(from) && j < len(to) && fromKeys[i] == toKeys[j]:
+			patch = diff(patch, from[i], to[j], pointer+FormatPointer(Index(index)))
+			i++
+			j++
+			index++
+		case j < len(to) && (i == len(from) || lcs[i][j+1] >= lcs[i+1][j]):
+			patch = append(patch, Operation{Op: OpAdd, Path: pointer + FormatPointer(Index(index)), Value: to[j]})
+			j++
+			index++
+		default:
+			patch = append(patch, Operation{Op: OpRemove, Path: pointer + FormatPointer(Index(index))})
+			i++
+		}
+	}
+
+	return patch
+}
+
+// identity keys an array element: objects by "uuid" then "name", everything
+// else by value
+func identity(value interface{}) string {
+	if object, ok := value.(map[string]interface{}); ok {
+		for _, key := range []string{"uuid", "name"} {
+			if id, ok := object[key].(string); ok && id != "" {
+				return key + ":" + id
+			}
+		}
+	}
+
+	data, _ := json.Marshal(value)
+	return "value:" + string(data)
+}
+
+// equal compares two generic JSON values, treating numbers by value
+func equal(a, b interface{}) bool {
+	switch aValue := a.(type) {
+	case map[string]interface{}:
+		bValue, ok := b.(map[string]interface{})
+		if !ok || len(aValue) != len(bValue) {
+			return false
+		}
+		for key, value := range aValue {
+			other, ok := bValue[key]
+			if !ok || !equal(value, other) {
+				return false
+			}
+		}
+		return true
+	case []interface{}:
+		bValue, ok := b.([]interface{})
+		if !ok || len(aValue) != len(bValue) {
+			return false
+		}
+		for key := range aValue {
+			if !equal(aValue[key], bValue[key]) {
+				return false
+			}
+		}
+		return true
+	case json.Number:
+		bValue, ok := b.(json.Number)
+		if !ok {
+			return false
+		}
+		if aValue == bValue {
+			return true
+		}
+		aFloat, aErr := aValue.Float64()
+		bFloat, bErr := bValue.Float64()
+		return aErr == nil && bErr == nil && aFloat == bFloat
+	}
+
+	return a == b
+}
@@ -0,0 +1,206 @@
+// Package jsonpatch creates and applies RFC 6902 JSON Patch documents.
+//
+// Values may be given as raw JSON ([]byte or json.RawMessage) or as any value
+// that marshals to JSON, including client.Resource types. Marshaling and
+// unmarshaling go through jsonhooks, so PreMarshalJSON/PostUnmarshalJSON hooks
+// are honored and unknown fields are retained.
+//
+// Arrays of objects are matched by their "uuid" member, falling back to their
+// "name" member, so inserting a rule, behavior or criteria into a PAPI rule
+// tree patches only the inserted item instead of every item after it.
+package jsonpatch
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/akamai/AkamaiOPEN-edgegrid-golang/jsonhooks-v1"
+)
+
+// OpValue is used to create an "enum" of possible Operation.Op values
+type OpValue string
+
+const (
+	// OpAdd Operation.Op value add
+	OpAdd OpValue = "add"
+	// OpRemove Operation.Op value remove
+	OpRemove OpValue = "remove"
+	// OpReplace Operation.Op value replace
+	OpReplace OpValue = "replace"
+	// OpMove Operation.Op value move
+	OpMove OpValue = "move"
+	// OpCopy Operation.Op value copy
+	OpCopy OpValue = "copy"
+	// OpTest Operation.Op value test
+	OpTest OpValue = "test"
+)
+
+// ContentType is the media type of a JSON Patch document
+const ContentType = "application/json-patch+json"
+
+var (
+	// ErrInvalidPointer is returned for a malformed JSON pointer
+	ErrInvalidPointer = errors.New("invalid JSON pointer")
+	// ErrPathNotFound is returned when a pointer does not resolve
+	ErrPathNotFound = errors.New("path not found")
+	// ErrInvalidOperation is returned for an unknown or incomplete operation
+	ErrInvalidOperation = errors.New("invalid operation")
+	// ErrTestFailed is returned when a test operation does not match
+	ErrTestFailed = errors.New("test operation failed")
+)
+
+// Operation is a single JSON Patch operation
+type Operation struct {
+	Op    OpValue     `json:"op"`
+	Path  string      `json:"path"`
+	From  string      `json:"from,omitempty"`
+	Value interface{} `json:"value,omitempty"`
+}
+
+// MarshalJSON always includes the value for operations that require one,
+// even when it is null
+func (operation Operation) MarshalJSON() ([]byte, error) {
+	body := map[string]interface{}{
+		"op":   operation.Op,
+		"path": operation.Path,
+	}
+
+	switch operation.Op {
+	case OpAdd, OpReplace, OpTest:
+		body["value"] = operation.Value
+	case OpMove, OpCopy:
+		body["from"] = operation.From
+	}
+
+	return json.Marshal(body)
+}
+
+// String returns a short, human readable form of the operation
+func (operation Operation) String() string {
+	switch operation.Op {
+	case OpMove, OpCopy:
+		return fmt.Sprintf("%s %s -> %s", operation.Op, operation.From, operation.Path)
+	case OpRemove:
+		return fmt.Sprintf("%s %s", operation.Op, operation.Path)
+	}
+
+	value, _ := json.Marshal(operation.Value)
+	return fmt.Sprintf("%s %s = %s", operation.Op, operation.Path, value)
+}
+
+// Patch is a JSON Patch document
+type Patch []Operation
+
+// Paths returns the paths touched by the patch, including move/copy sources
+func (patch Patch) Paths() []string {
+	var paths []string
+	for _, operation := range patch {
+		if operation.From != "" {
+			paths = append(paths, operation.From)
+		}
+		paths = append(paths, operation.Path)
+	}
+
+	return paths
+}
+
+// Prefix returns a copy of the patch with prefix prepended to every path
+func (patch Patch) Prefix(prefix string) Patch {
+	prefixed := make(Patch, len(patch))
+	for key, operation := range patch {
+		prefixed[key] = operation
+		prefixed[key].Path = prefix + operation.Path
+		if operation.From != "" {
+			prefixed[key].From = prefix + operation.From
+		}
+	}
+
+	return prefixed
+}
+
+// Filter returns the operations whose paths are at or below prefix, with the
+// prefix removed
+func (patch Patch) Filter(prefix string) Patch {
+	var filtered Patch
+	for _, operation := range patch {
+		if !IsPrefix(prefix, operation.Path) || (operation.From != "" && !IsPrefix(prefix, operation.From)) {
+			continue
+		}
+
+		operation.Path = strings.TrimPrefix(operation.Path, prefix)
+		if operation.From != "" {
+			operation.From = strings.TrimPrefix(operation.From, prefix)
+		}
+		filtered = append(filtered, operation)
+	}
+
+	return filtered
+}
+
+// ParsePointer splits a JSON pointer into its unescaped reference tokens
+func ParsePointer(pointer string) ([]string, error) {
+	if pointer == "" {
+		return []string{}, nil
+	}
+
+	if !strings.HasPrefix(pointer, "/") {
+		return nil, fmt.Errorf("%w: %q", ErrInvalidPointer, pointer)
+	}
+
+	tokens := strings.Split(pointer[1:], "/")
+	for key, token := range tokens {
+		tokens[key] = strings.Replace(strings.Replace(token, "~1", "/", -1), "~0", "~", -1)
+	}
+
+	return tokens, nil
+}
+
+// FormatPointer builds a JSON pointer from reference tokens, escaping them as needed
+func FormatPointer(tokens ...string) string {
+	var pointer strings.Builder
+	for _, token := range tokens {
+		pointer.WriteString("/")
+		pointer.WriteString(strings.Replace(strings.Replace(token, "~", "~0", -1), "/", "~1", -1))
+	}
+
+	return pointer.String()
+}
+
+// IsPrefix reports whether pointer is prefix itself or a location below it
+func IsPrefix(prefix, pointer string) bool {
+	return pointer == prefix || strings.HasPrefix(pointer, prefix+"/") || prefix == ""
+}
+
+// Index returns the array index token for key
+func Index(key int) string {
+	return strconv.Itoa(key)
+}
+
+// toDocument converts v to its generic JSON representation
+func toDocument(v interface{}) (interface{}, error) {
+	var data []byte
+	switch value := v.(type) {
+	case []byte:
+		data = value
+	case json.RawMessage:
+		data = value
+	default:
+		var err error
+		if data, err = jsonhooks.Marshal(v); err != nil {
+			return nil, err
+		}
+	}
+
+	var document interface{}
+	decoder := json.NewDecoder(bytes.NewReader(data))
+	decoder.UseNumber()
+	if err := decoder.Decode(&document); err != nil {
+		return nil, err
+	}
+
+	return document, nil
+}
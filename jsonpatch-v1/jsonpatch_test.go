@@ -0,0 +1,232 @@
+package jsonpatch
+
+import (
+	"encoding/json"
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+type Item struct {
+	Name    string                 `json:"name"`
+	UUID    string                 `json:"uuid,omitempty"`
+	Options map[string]interface{} `json:"options,omitempty"`
+	Items   []*Item                `json:"children,omitempty"`
+}
+
+func TestParsePointer(t *testing.T) {
+	tokens, err := ParsePointer("/a~1b/m~0n/0")
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"a/b", "m~n", "0"}, tokens)
+	assert.Equal(t, "/a~1b/m~0n/0", FormatPointer(tokens...))
+
+	tokens, err = ParsePointer("")
+	assert.NoError(t, err)
+	assert.Empty(t, tokens)
+
+	_, err = ParsePointer("a/b")
+	assert.True(t, errors.Is(err, ErrInvalidPointer))
+}
+
+func TestPatch_Apply(t *testing.T) {
+	tests := []struct {
+		Document string
+		Patch    string
+		Expected string
+		Err      error
+	}{
+		{`{"foo":"bar"}`, `[{"op":"add","path":"/baz","value":"qux"}]`, `{"baz":"qux","foo":"bar"}`, nil},
+		{`{"foo":["bar","baz"]}`, `[{"op":"add","path":"/foo/1","value":"qux"}]`, `{"foo":["bar","qux","baz"]}`, nil},
+		{`{"foo":["bar"]}`, `[{"op":"add","path":"/foo/-","value":"qux"}]`, `{"foo":["bar","qux"]}`, nil},
+		{`{"foo":["bar","qux","baz"]}`, `[{"op":"remove","path":"/foo/1"}]`, `{"foo":["bar","baz"]}`, nil},
+		{`{"foo":"bar"}`, `[{"op":"replace","path":"/foo","value":null}]`, `{"foo":null}`, nil},
+		{`{"foo":{"bar":"baz"},"qux":{}}`, `[{"op":"move","from":"/foo/bar","path":"/qux/thud"}]`, `{"foo":{},"qux":{"thud":"baz"}}`, nil},
+		{`{"foo":["all","grass","cows","eat"]}`, `[{"op":"move","from":"/foo/1","path":"/foo/3"}]`, `{"foo":["all","cows","eat","grass"]}`, nil},
+		{`{"foo":{"bar":1}}`, `[{"op":"copy","from":"/foo","path":"/baz"}]`, `{"foo":{"bar":1},"baz":{"bar":1}}`, nil},
+		{`{"foo":1.0}`, `[{"op":"test","path":"/foo","value":1}]`, `{"foo":1.0}`, nil},
+		{`{"foo":1}`, `[{"op":"test","path":"/foo","value":2}]`, ``, ErrTestFailed},
+		{`{"foo":1}`, `[{"op":"remove","path":"/bar"}]`, ``, ErrPathNotFound},
+		{`{"foo":[]}`, `[{"op":"add","path":"/foo/1","value":1}]`, ``, ErrPathNotFound},
+		{`{"foo":[]}`, `[{"op":"add","path":"/foo/01","value":1}]`, ``, ErrInvalidPointer},
+		{`{"foo":{}}`, `[{"op":"move","from":"/foo","path":"/foo/bar"}]`, ``, ErrInvalidOperation},
+		{`{"foo":1}`, `[{"op":"frobnicate","path":"/foo"}]`, ``, ErrInvalidOperation},
+	}
+
+	for _, test := range tests {
+		var patch Patch
+		assert.NoError(t, json.Unmarshal([]byte(test.Patch), &patch))
+
+		actual, err := patch.Apply([]byte(test.Document))
+		if test.Err != nil {
+			assert.True(t, errors.Is(err, test.Err), "%s: %v", test.Patch, err)
+			continue
+		}
+
+		assert.NoError(t, err)
+		assert.JSONEq(t, test.Expected, string(actual))
+	}
+}
+
+func TestPatch_ApplyTo(t *testing.T) {
+	item := &Item{Name: "default", Options: map[string]interface{}{"stale": true}}
+	patch := Patch{
+		{Op: OpRemove, Path: "/options/stale"},
+		{Op: OpAdd, Path: "/children", Value: []interface{}{map[string]interface{}{"name": "child"}}},
+	}
+
+	err := patch.ApplyTo(item)
+
+	assert.NoError(t, err)
+	assert.Empty(t, item.Options)
+	assert.Len(t, item.Items, 1)
+	assert.Equal(t, "child", item.Items[0].Name)
+}
+
+func TestOperation_MarshalJSON(t *testing.T) {
+	patch := Patch{
+		{Op: OpAdd, Path: "/foo", Value: nil},
+		{Op: OpRemove, Path: "/bar"},
+		{Op: OpMove, From: "/baz", Path: "/qux"},
+	}
+
+	actual, err := json.Marshal(patch)
+
+	assert.NoError(t, err)
+	assert.JSONEq(t, `[
+		{"op":"add","path":"/foo","value":null},
+		{"op":"remove","path":"/bar"},
+		{"op":"move","from":"/baz","path":"/qux"}
+	]`, string(actual))
+}
+
+func TestCreatePatch(t *testing.T) {
+	tests := []struct {
+		Original string
+		Modified string
+		Expected Patch
+	}{
+		{`{"a":1,"b":2}`, `{"a":1,"b":2}`, nil},
+		{`{"a":1,"b":2}`, `{"a":1.0,"c":3}`, Patch{
+			{Op: OpRemove, Path: "/b"},
+			{Op: OpAdd, Path: "/c", Value: json.Number("3")},
+		}},
+		{`{"a":[1,2,3]}`, `{"a":[1,3,4]}`, Patch{
+			{Op: OpRemove, Path: "/a/1"},
+			{Op: OpAdd, Path: "/a/2", Value: json.Number("4")},
+		}},
+		{`{"a":{"b":1}}`, `{"a":[1]}`, Patch{
+			{Op: OpReplace, Path: "/a", Value: []interface{}{json.Number("1")}},
+		}},
+		{`{"a/b":{"c~d":1}}`, `{"a/b":{"c~d":2}}`, Patch{
+			{Op: OpReplace, Path: "/a~1b/c~0d", Value: json.Number("2")},
+		}},
+	}
+
+	for _, test := range tests {
+		patch, err := CreatePatch([]byte(test.Original), []byte(test.Modified))
+		assert.NoError(t, err)
+		assert.Equal(t, test.Expected, patch)
+
+		patched, err := patch.Apply([]byte(test.Original))
+		assert.NoError(t, err)
+		assert.JSONEq(t, test.Modified, string(patched))
+	}
+}
+
+func TestCreatePatch_MatchesByIdentity(t *testing.T) {
+	original := &Item{
+		Name: "default",
+		Items: []*Item{
+			{Name: "first", UUID: "1", Options: map[string]interface{}{"ttl": "1d"}},
+			{Name: "second"},
+			{Name: "third"},
+		},
+	}
+	modified := &Item{
+		Name: "default",
+		Items: []*Item{
+			{Name: "inserted"},
+			{Name: "renamed", UUID: "1", Options: map[string]interface{}{"ttl": "2d"}},
+			{Name: "third"},
+		},
+	}
+
+	patch, err := CreatePatch(original, modified)
+
+	assert.NoError(t, err)
+	assert.Equal(t, Patch{
+		{Op: OpAdd, Path: "/children/0", Value: map[string]interface{}{"name": "inserted"}},
+		{Op: OpReplace, Path: "/children/1/name", Value: "renamed"},
+		{Op: OpReplace, Path: "/children/1/options/ttl", Value: "2d"},
+		{Op: OpRemove, Path: "/children/2"},
+	}, patch)
+
+	err = patch.ApplyTo(original)
+	assert.NoError(t, err)
+	assert.Equal(t, modified, original)
+}
+
+func TestPatch_Filter(t *testing.T) {
+	patch := Patch{
+		{Op: OpReplace, Path: "/etag", Value: "abc"},
+		{Op: OpReplace, Path: "/rules/name", Value: "default"},
+		{Op: OpMove, From: "/rules/children/0", Path: "/rules/children/1"},
+		{Op: OpReplace, Path: "/rulesFormat", Value: "latest"},
+	}
+
+	filtered := patch.Filter("/rules")
+
+	assert.Equal(t, Patch{
+		{Op: OpReplace, Path: "/name", Value: "default"},
+		{Op: OpMove, From: "/children/0", Path: "/children/1"},
+	}, filtered)
+	assert.Equal(t, patch[1:3], filtered.Prefix("/rules"))
+}
+
+func TestRebase(t *testing.T) {
+	base := []byte(`{"children": [{"name": "a", "ttl": "1d"}, {"name": "b", "ttl": "1d"}, {"name": "c", "ttl": "1d"}]}`)
+
+	ours := Patch{
+		{Op: OpReplace, Path: "/children/2/ttl", Value: "2d"},
+	}
+	theirs := Patch{
+		{Op: OpAdd, Path: "/children/0", Value: map[string]interface{}{"name": "z"}},
+		{Op: OpReplace, Path: "/children/2/ttl", Value: "3d"},
+	}
+
+	rebased, err := Rebase(ours, theirs)
+	assert.NoError(t, err)
+	assert.Equal(t, Patch{{Op: OpReplace, Path: "/children/3/ttl", Value: "2d"}}, rebased)
+
+	current, err := theirs.Apply(base)
+	assert.NoError(t, err)
+	merged, err := rebased.Apply(current)
+	assert.NoError(t, err)
+	assert.JSONEq(t, `{"children": [{"name": "z"}, {"name": "a", "ttl": "1d"}, {"name": "b", "ttl": "3d"}, {"name": "c", "ttl": "2d"}]}`, string(merged))
+}
+
+func TestRebase_Conflicts(t *testing.T) {
+	tests := map[string]struct {
+		Ours   Patch
+		Theirs Patch
+	}{
+		"same location": {
+			Ours:   Patch{{Op: OpReplace, Path: "/children/0/ttl", Value: "2d"}},
+			Theirs: Patch{{Op: OpReplace, Path: "/children/0/ttl", Value: "3d"}},
+		},
+		"removed parent": {
+			Ours:   Patch{{Op: OpReplace, Path: "/children/0/ttl", Value: "2d"}},
+			Theirs: Patch{{Op: OpRemove, Path: "/children/0"}},
+		},
+		"same array": {
+			Ours:   Patch{{Op: OpAdd, Path: "/children/1", Value: "x"}},
+			Theirs: Patch{{Op: OpRemove, Path: "/children/3"}},
+		},
+	}
+
+	for name, test := range tests {
+		_, err := Rebase(test.Ours, test.Theirs)
+		assert.True(t, errors.Is(err, ErrConflict), name)
+	}
+}
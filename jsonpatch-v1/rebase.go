@@ -0,0 +1,172 @@
+package jsonpatch
+
+import (
+	"errors"
+	"fmt"
+	"strconv"
+)
+
+// ErrConflict is returned when two patches cannot be rebased onto each other
+var ErrConflict = errors.New("patches conflict")
+
+// Rebase rewrites ours, a patch made against some document, so it can be
+// applied after theirs, another patch made against the same document
+//
+// Array indices in ours are shifted to account for elements theirs inserted
+// or removed. ErrConflict is returned when the patches touch the same
+// location (or one touches a parent of the other), or when both insert into
+// or remove from the same array.
+func Rebase(ours, theirs Patch) (Patch, error) {
+	rebased := make(Patch, len(ours))
+	copy(rebased, ours)
+
+	// Each of their operations is checked against ours as shifted by the
+	// operations before it, so both refer to the same document state
+	for _, their := range theirs {
+		for _, our := range rebased {
+			if err := conflict(our, their); err != nil {
+				return nil, err
+			}
+		}
+
+		var shifts []arrayShift
+		switch their.Op {
+		case OpAdd, OpCopy:
+			shifts = []arrayShift{{their.Path, 1}}
+		case OpRemove:
+			shifts = []arrayShift{{their.Path, -1}}
+		case OpMove:
+			shifts = []arrayShift{{their.From, -1}, {their.Path, 1}}
+		}
+
+		for _, shift := range shifts {
+			for key := range rebased {
+				rebased[key].Path = shift.apply(rebased[key].Path)
+				if rebased[key].From != "" {
+					rebased[key].From = shift.apply(rebased[key].From)
+				}
+			}
+		}
+	}
+
+	return rebased, nil
+}
+
+// conflict returns ErrConflict if two operations touch overlapping locations
+// or resize the same array
+func conflict(our, their Operation) error {
+	for _, ourContainer := range resizedArrays(our) {
+		for _, theirContainer := range resizedArrays(their) {
+			if ourContainer == theirContainer {
+				return fmt.Errorf("%w: %s and %s", ErrConflict, our, their)
+			}
+		}
+	}
+
+	for _, ourPath := range touchedPaths(our) {
+		for _, theirPath := range touchedPaths(their) {
+			if IsPrefix(ourPath, theirPath) || IsPrefix(theirPath, ourPath) {
+				return fmt.Errorf("%w: %s and %s", ErrConflict, our, their)
+			}
+		}
+	}
+
+	return nil
+}
+
+// arrayShift is an insertion (+1) or removal (-1) at an array index
+type arrayShift struct {
+	at    string
+	delta int
+}
+
+// apply shifts pointer if it refers into the same array at or after the
+// shifted index
+func (shift arrayShift) apply(pointer string) string {
+	at, err := ParsePointer(shift.at)
+	if err != nil || len(at) == 0 {
+		return pointer
+	}
+
+	index, err := strconv.Atoi(at[len(at)-1])
+	if err != nil {
+		return pointer
+	}
+
+	path, err := ParsePointer(pointer)
+	if err != nil || len(path) < len(at) {
+		return pointer
+	}
+
+	for key := range at[:len(at)-1] {
+		if path[key] != at[key] {
+			return pointer
+		}
+	}
+
+	current, err := strconv.Atoi(path[len(at)-1])
+	if err != nil || current < index || (current == index && shift.delta < 0) {
+		return pointer
+	}
+
+	path[len(at)-1] = strconv.Itoa(current + shift.delta)
+
+	return FormatPointer(path...)
+}
+
+// touchedPaths returns the existing locations an operation reads or changes;
+// an insertion into an array touches no existing element
+func touchedPaths(operation Operation) []string {
+	var paths []string
+	if operation.From != "" {
+		paths = append(paths, operation.From)
+	}
+
+	switch operation.Op {
+	case OpAdd, OpCopy, OpMove:
+		if isArrayToken(operation.Path) {
+			return paths
+		}
+	}
+
+	return append(paths, operation.Path)
+}
+
+// resizedArrays returns the arrays an operation inserts into or removes from
+func resizedArrays(operation Operation) []string {
+	var pointers []string
+	switch operation.Op {
+	case OpAdd, OpCopy, OpRemove:
+		pointers = []string{operation.Path}
+	case OpMove:
+		pointers = []string{operation.From, operation.Path}
+	}
+
+	var containers []string
+	for _, pointer := range pointers {
+		if !isArrayToken(pointer) {
+			continue
+		}
+
+		path, _ := ParsePointer(pointer)
+		containers = append(containers, FormatPointer(path[:len(path)-1]...))
+	}
+
+	return containers
+}
+
+// isArrayToken reports whether the last token of pointer is an array index
+func isArrayToken(pointer string) bool {
+	path, err := ParsePointer(pointer)
+	if err != nil || len(path) == 0 {
+		return false
+	}
+
+	last := path[len(path)-1]
+	if last == "-" {
+		return true
+	}
+
+	_, err = strconv.Atoi(last)
+	return err == nil
+}
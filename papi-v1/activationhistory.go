@@ -0,0 +1,289 @@
+package papi
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"io"
+	"path"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// ActivationHistoryOptions filters an activation history. Zero values
+// match everything.
+type ActivationHistoryOptions struct {
+	// Network limits the history to one network
+	Network NetworkValue
+	// Statuses limits the history to activations with one of these statuses
+	Statuses []StatusValue
+	// Since and Until limit the history to activations last updated within
+	// the range, inclusive
+	Since time.Time
+	Until time.Time
+	// User limits the history to activations notifying this email, or of a
+	// version last updated by this user. Matching is case-insensitive.
+	User string
+	// PropertyName limits the history to properties matching this pattern,
+	// as used by path.Match, e.g. "*.example.com"
+	PropertyName string
+	// Parallelism is the maximum number of concurrent requests. Defaults to
+	// DefaultLoadParallelism
+	Parallelism int
+}
+
+// ActivationHistoryEntry is an activation in an activation history
+type ActivationHistoryEntry struct {
+	PropertyID      string          `json:"propertyId"`
+	PropertyName    string          `json:"propertyName"`
+	ContractID      string          `json:"contractId"`
+	GroupID         string          `json:"groupId"`
+	PropertyVersion int             `json:"propertyVersion"`
+	ActivationID    string          `json:"activationId"`
+	ActivationType  ActivationValue `json:"activationType"`
+	Network         NetworkValue    `json:"network"`
+	Status          StatusValue     `json:"status"`
+	SubmitDate      time.Time       `json:"submitDate"`
+	UpdateDate      time.Time       `json:"updateDate"`
+	UpdatedByUser   string          `json:"updatedByUser,omitempty"`
+	NotifyEmails    []string        `json:"notifyEmails"`
+	Note            string          `json:"note,omitempty"`
+}
+
+// ActivationHistory is a list of activations, most recently updated first
+type ActivationHistory []*ActivationHistoryEntry
+
+// GetActivationHistory lists the activations of every property in the
+// scopes, filtered by options
+//
+// Properties and activations are fetched with at most options.Parallelism
+// requests at a time. The versions of a property are only fetched when
+// filtering by User. Errors are returned as LoadErrors together with the
+// history of the properties that could be fetched.
+//
+//	history, err := papi.GetActivationHistory(scopes, &papi.ActivationHistoryOptions{
+//		Network: papi.NetworkProduction,
+//		Since:   time.Now().Add(-2 * time.Hour),
+//	}, "")
+func GetActivationHistory(scopes []PropertyScope, options *ActivationHistoryOptions, correlationid string) (ActivationHistory, error) {
+	if options == nil {
+		options = &ActivationHistoryOptions{}
+	}
+
+	if options.PropertyName != "" {
+		if _, err := path.Match(options.PropertyName, ""); err != nil {
+			return nil, fmt.Errorf("property name pattern %q: %w", options.PropertyName, err)
+		}
+	}
+
+	loader := newLoader(options.Parallelism)
+
+	found, err := loader.scopeProperties(scopes, correlationid)
+	if err != nil {
+		return nil, err
+	}
+
+	var properties []*Property
+	for _, property := range found {
+		if options.matchesPropertyName(property.PropertyName) {
+			properties = append(properties, property)
+		}
+	}
+
+	activations := make([]*Activations, len(properties))
+	versions := make([]*Versions, len(properties))
+	for key, property := range properties {
+		key, property := key, property
+		loader.run("activations:"+property.PropertyID, func() error {
+			propertyActivations := NewActivations()
+			if err := propertyActivations.GetActivations(property); err != nil {
+				return fmt.Errorf("property %s: activations: %w", property.PropertyID, err)
+			}
+			activations[key] = propertyActivations
+			return nil
+		})
+
+		if options.User != "" {
+			loader.run("versions:"+property.PropertyID, func() error {
+				propertyVersions := NewVersions()
+				if err := propertyVersions.GetVersions(property, correlationid); err != nil {
+					return fmt.Errorf("property %s: versions: %w", property.PropertyID, err)
+				}
+				versions[key] = propertyVersions
+				return nil
+			})
+		}
+	}
+	loader.wait()
+
+	history := ActivationHistory{}
+	for key, property := range properties {
+		if activations[key] == nil {
+			continue
+		}
+
+		updatedBy := map[int]string{}
+		if versions[key] != nil {
+			for _, version := range versions[key].Versions.Items {
+				updatedBy[version.PropertyVersion] = version.UpdatedByUser
+			}
+		}
+
+		for _, activation := range activations[key].Activations.Items {
+			entry := &ActivationHistoryEntry{
+				PropertyID:      property.PropertyID,
+				PropertyName:    property.PropertyName,
+				ContractID:      property.ContractID,
+				GroupID:         property.GroupID,
+				PropertyVersion: activation.PropertyVersion,
+				ActivationID:    activation.ActivationID,
+				ActivationType:  activation.ActivationType,
+				Network:         activation.Network,
+				Status:          activation.Status,
+				SubmitDate:      parseActivationDate(activation.SubmitDate),
+				UpdateDate:      parseActivationDate(activation.UpdateDate),
+				UpdatedByUser:   updatedBy[activation.PropertyVersion],
+				NotifyEmails:    activation.NotifyEmails,
+				Note:            activation.Note,
+			}
+
+			if options.matches(entry) {
+				history = append(history, entry)
+			}
+		}
+	}
+
+	sort.SliceStable(history, func(i, j int) bool {
+		if !history[i].UpdateDate.Equal(history[j].UpdateDate) {
+			return history[i].UpdateDate.After(history[j].UpdateDate)
+		}
+		return history[i].ActivationID < history[j].ActivationID
+	})
+
+	return history, loader.err()
+}
+
+func (options *ActivationHistoryOptions) matchesPropertyName(name string) bool {
+	if options.PropertyName == "" {
+		return true
+	}
+
+	matched, _ := path.Match(options.PropertyName, name)
+	return matched
+}
+
+func (options *ActivationHistoryOptions) matches(entry *ActivationHistoryEntry) bool {
+	if options.Network != "" && options.Network != entry.Network {
+		return false
+	}
+
+	if len(options.Statuses) != 0 {
+		matched := false
+		for _, status := range options.Statuses {
+			matched = matched || status == entry.Status
+		}
+		if !matched {
+			return false
+		}
+	}
+
+	if !options.Since.IsZero() && entry.UpdateDate.Before(options.Since) {
+		return false
+	}
+	if !options.Until.IsZero() && entry.UpdateDate.After(options.Until) {
+		return false
+	}
+
+	if options.User != "" {
+		matched := strings.EqualFold(options.User, entry.UpdatedByUser)
+		for _, email := range entry.NotifyEmails {
+			matched = matched || strings.EqualFold(options.User, email)
+		}
+		if !matched {
+			return false
+		}
+	}
+
+	return true
+}
+
+// parseActivationDate parses an activation's submitDate or updateDate,
+// returning the zero time if it is missing or invalid
+func parseActivationDate(date string) time.Time {
+	parsed, err := time.Parse(time.RFC3339, date)
+	if err != nil {
+		return time.Time{}
+	}
+
+	return parsed
+}
+
+// WriteJSON writes the history as a JSON array
+func (history ActivationHistory) WriteJSON(w io.Writer) error {
+	encoder := json.NewEncoder(w)
+	encoder.SetIndent("", "  ")
+
+	return encoder.Encode(history)
+}
+
+// activationHistoryCSVHeader is the header row written by WriteCSV
+var activationHistoryCSVHeader = []string{
+	"propertyId",
+	"propertyName",
+	"contractId",
+	"groupId",
+	"propertyVersion",
+	"activationId",
+	"activationType",
+	"network",
+	"status",
+	"submitDate",
+	"updateDate",
+	"updatedByUser",
+	"notifyEmails",
+	"note",
+}
+
+// WriteCSV writes the history as CSV with a header row. Dates are RFC 3339
+// and notification emails are separated by spaces.
+func (history ActivationHistory) WriteCSV(w io.Writer) error {
+	writer := csv.NewWriter(w)
+	if err := writer.Write(activationHistoryCSVHeader); err != nil {
+		return err
+	}
+
+	for _, entry := range history {
+		err := writer.Write([]string{
+			entry.PropertyID,
+			entry.PropertyName,
+			entry.ContractID,
+			entry.GroupID,
+			strconv.Itoa(entry.PropertyVersion),
+			entry.ActivationID,
+			string(entry.ActivationType),
+			string(entry.Network),
+			string(entry.Status),
+			formatActivationDate(entry.SubmitDate),
+			formatActivationDate(entry.UpdateDate),
+			entry.UpdatedByUser,
+			strings.Join(entry.NotifyEmails, " "),
+			entry.Note,
+		})
+		if err != nil {
+			return err
+		}
+	}
+
+	writer.Flush()
+	return writer.Error()
+}
+
+func formatActivationDate(date time.Time) string {
+	if date.IsZero() {
+		return ""
+	}
+
+	return date.Format(time.RFC3339)
+}
@@ -0,0 +1,180 @@
+package papi
+
+import (
+	"bytes"
+	"encoding/csv"
+	"encoding/json"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"gopkg.in/h2non/gock.v1"
+)
+
+func mockActivationHistory() []PropertyScope {
+	gock.New("https://akaa-baseurl-xxxxxxxxxxx-xxxxxxxxxxxxx.luna.akamaiapis.net").
+		Get("/papi/v1/properties").
+		MatchParam("groupId", "grp_1").
+		Reply(200).
+		SetHeader("Content-Type", "application/json").
+		BodyString(`{"properties": {"items": [
+			{"propertyId": "prp_1", "propertyName": "www.example.com", "contractId": "ctr_1", "groupId": "grp_1"},
+			{"propertyId": "prp_2", "propertyName": "www.example.org", "contractId": "ctr_1", "groupId": "grp_1"}
+		]}}`)
+
+	gock.New("https://akaa-baseurl-xxxxxxxxxxx-xxxxxxxxxxxxx.luna.akamaiapis.net").
+		Get("/papi/v1/properties").
+		MatchParam("groupId", "grp_2").
+		Reply(200).
+		SetHeader("Content-Type", "application/json").
+		BodyString(`{"properties": {"items": [
+			{"propertyId": "prp_3", "propertyName": "api.example.com", "contractId": "ctr_1", "groupId": "grp_2"}
+		]}}`)
+
+	gock.New("https://akaa-baseurl-xxxxxxxxxxx-xxxxxxxxxxxxx.luna.akamaiapis.net").
+		Get("/papi/v1/properties/prp_1/activations").
+		Reply(200).
+		SetHeader("Content-Type", "application/json").
+		BodyString(`{"activations": {"items": [
+			{"activationId": "atv_11", "propertyVersion": 4, "network": "PRODUCTION", "status": "ACTIVE", "activationType": "ACTIVATE", "submitDate": "2021-06-01T10:00:00Z", "updateDate": "2021-06-01T10:20:00Z", "notifyEmails": ["noc@example.com"]},
+			{"activationId": "atv_12", "propertyVersion": 4, "network": "STAGING", "status": "ACTIVE", "activationType": "ACTIVATE", "submitDate": "2021-06-01T09:00:00Z", "updateDate": "2021-06-01T09:05:00Z", "notifyEmails": ["dev@example.com"]},
+			{"activationId": "atv_10", "propertyVersion": 3, "network": "PRODUCTION", "status": "ACTIVE", "activationType": "ACTIVATE", "submitDate": "2021-05-01T09:00:00Z", "updateDate": "2021-05-01T09:30:00Z", "notifyEmails": ["noc@example.com"]}
+		]}}`)
+
+	gock.New("https://akaa-baseurl-xxxxxxxxxxx-xxxxxxxxxxxxx.luna.akamaiapis.net").
+		Get("/papi/v1/properties/prp_3/activations").
+		Reply(200).
+		SetHeader("Content-Type", "application/json").
+		BodyString(`{"activations": {"items": [
+			{"activationId": "atv_31", "propertyVersion": 8, "network": "PRODUCTION", "status": "FAILED", "activationType": "ACTIVATE", "submitDate": "2021-06-01T10:30:00Z", "updateDate": "2021-06-01T10:45:00Z", "notifyEmails": ["api@example.com"], "note": "rollout, part 2"}
+		]}}`)
+
+	contract := NewContract(NewContracts())
+	contract.ContractID = "ctr_1"
+
+	var scopes []PropertyScope
+	for _, groupID := range []string{"grp_1", "grp_2"} {
+		group := NewGroup(NewGroups())
+		group.GroupID = groupID
+		scopes = append(scopes, PropertyScope{Contract: contract, Group: group})
+	}
+
+	return scopes
+}
+
+func TestGetActivationHistory(t *testing.T) {
+	defer gock.Off()
+
+	scopes := mockActivationHistory()
+
+	Init(config)
+
+	history, err := GetActivationHistory(scopes, &ActivationHistoryOptions{
+		Network:      NetworkProduction,
+		Since:        time.Date(2021, 6, 1, 9, 0, 0, 0, time.UTC),
+		PropertyName: "*.example.com",
+		Parallelism:  2,
+	}, "")
+	assert.NoError(t, err)
+	assert.True(t, gock.IsDone())
+
+	var activationIDs []string
+	for _, entry := range history {
+		activationIDs = append(activationIDs, entry.ActivationID)
+	}
+	assert.Equal(t, []string{"atv_31", "atv_11"}, activationIDs)
+	assert.Equal(t, "api.example.com", history[0].PropertyName)
+	assert.Equal(t, "grp_2", history[0].GroupID)
+	assert.Equal(t, time.Date(2021, 6, 1, 10, 45, 0, 0, time.UTC), history[0].UpdateDate)
+}
+
+func TestGetActivationHistory_User(t *testing.T) {
+	defer gock.Off()
+
+	scopes := mockActivationHistory()
+
+	for _, property := range []string{"prp_1", "prp_3"} {
+		gock.New("https://akaa-baseurl-xxxxxxxxxxx-xxxxxxxxxxxxx.luna.akamaiapis.net").
+			Get("/papi/v1/properties/" + property + "/versions").
+			Reply(200).
+			SetHeader("Content-Type", "application/json").
+			BodyString(`{"versions": {"items": [{"propertyVersion": 3, "updatedByUser": "jdoe"}, {"propertyVersion": 8, "updatedByUser": "jdoe"}]}}`)
+	}
+
+	Init(config)
+
+	history, err := GetActivationHistory(scopes, &ActivationHistoryOptions{
+		User:         "JDoe",
+		Statuses:     []StatusValue{StatusActive},
+		PropertyName: "*.example.com",
+	}, "")
+	assert.NoError(t, err)
+	assert.Len(t, history, 1)
+	assert.Equal(t, "atv_10", history[0].ActivationID)
+	assert.Equal(t, "jdoe", history[0].UpdatedByUser)
+}
+
+func TestGetActivationHistory_PropertiesError(t *testing.T) {
+	defer gock.Off()
+
+	gock.New("https://akaa-baseurl-xxxxxxxxxxx-xxxxxxxxxxxxx.luna.akamaiapis.net").
+		Get("/papi/v1/properties").
+		MatchParam("groupId", "grp_1").
+		ReplyError(errors.New("connection reset"))
+
+	Init(config)
+
+	contract := NewContract(NewContracts())
+	contract.ContractID = "ctr_1"
+	group := NewGroup(NewGroups())
+	group.GroupID = "grp_1"
+
+	history, err := GetActivationHistory([]PropertyScope{{Contract: contract, Group: group}}, nil, "")
+	assert.Empty(t, history)
+
+	var loadErrors LoadErrors
+	assert.True(t, errors.As(err, &loadErrors))
+	assert.Contains(t, err.Error(), "properties of grp_1:")
+	assert.Contains(t, err.Error(), "connection reset")
+}
+
+func TestActivationHistory_Write(t *testing.T) {
+	history := ActivationHistory{
+		{
+			PropertyID:      "prp_3",
+			PropertyName:    "api.example.com",
+			ContractID:      "ctr_1",
+			GroupID:         "grp_2",
+			PropertyVersion: 8,
+			ActivationID:    "atv_31",
+			ActivationType:  ActivationTypeActivate,
+			Network:         NetworkProduction,
+			Status:          StatusFailed,
+			SubmitDate:      time.Date(2021, 6, 1, 10, 30, 0, 0, time.UTC),
+			UpdateDate:      time.Date(2021, 6, 1, 10, 45, 0, 0, time.UTC),
+			NotifyEmails:    []string{"api@example.com", "noc@example.com"},
+			Note:            "rollout, part 2",
+		},
+	}
+
+	var buffer bytes.Buffer
+	assert.NoError(t, history.WriteCSV(&buffer))
+
+	records, err := csv.NewReader(&buffer).ReadAll()
+	assert.NoError(t, err)
+	assert.Len(t, records, 2)
+	assert.Equal(t, "propertyId", records[0][0])
+	assert.Equal(t, []string{
+		"prp_3", "api.example.com", "ctr_1", "grp_2", "8", "atv_31", "ACTIVATE", "PRODUCTION", "FAILED",
+		"2021-06-01T10:30:00Z", "2021-06-01T10:45:00Z", "", "api@example.com noc@example.com", "rollout, part 2",
+	}, records[1])
+
+	buffer.Reset()
+	assert.NoError(t, history.WriteJSON(&buffer))
+
+	var decoded []map[string]interface{}
+	assert.NoError(t, json.Unmarshal(buffer.Bytes(), &decoded))
+	assert.Equal(t, "atv_31", decoded[0]["activationId"])
+	assert.Equal(t, "2021-06-01T10:45:00Z", decoded[0]["updateDate"])
+}
@@ -1,9 +1,12 @@
 package papi
 
 import (
-	"encoding/json"
+	"context"
 	"fmt"
 	"io/ioutil"
+	"net/http"
+	"net/url"
+	"strconv"
 	"time"
 
 	"github.com/akamai/AkamaiOPEN-edgegrid-golang/client-v1"
@@ -132,6 +135,10 @@ type Activation struct {
 	UpdateDate          string                      `json:"updateDate,omitempty"`
 	Note                string                      `json:"note,omitempty"`
 	NotifyEmails        []string                    `json:"notifyEmails"`
+	FMAPercent          *float64                    `json:"fmaPercent,omitempty"`
+	UseFastFallback     bool                        `json:"useFastFallback,omitempty"`
+	FallbackInfo        *ActivationFallbackInfo     `json:"fallbackInfo,omitempty"`
+	Warnings            ActivationWarnings          `json:"-"`
 	StatusChange        chan bool                   `json:"-"`
 }
 
@@ -139,6 +146,17 @@ type ActivationComplianceRecord struct {
 	NoncomplianceReason string `json:"noncomplianceReason,omitempty"`
 }
 
+// ActivationFallbackInfo describes whether an activation can still be rolled
+// back with fast fallback. Times are in seconds since the epoch.
+type ActivationFallbackInfo struct {
+	FastFallbackAttempted      bool   `json:"fastFallbackAttempted"`
+	FallbackVersion            int    `json:"fallbackVersion"`
+	CanFastFallback            bool   `json:"canFastFallback"`
+	SteadyStateTime            int64  `json:"steadyStateTime"`
+	FastFallbackExpirationTime int64  `json:"fastFallbackExpirationTime"`
+	FastFallbackRecoveryState  string `json:"fastFallbackRecoveryState,omitempty"`
+}
+
 // NewActivation creates a new Activation
 func NewActivation(parent *Activations) *Activation {
 	activation := &Activation{parent: parent}
@@ -157,15 +175,28 @@ func (activation *Activation) Init() {
 // API Docs: https://developer.akamai.com/api/luna/papi/resources.html#getanactivation
 // Endpoint: GET /papi/v1/properties/{propertyId}/activations/{activationId}{?contractId,groupId}
 func (activation *Activation) GetActivation(property *Property) (time.Duration, error) {
+	return activation.getActivation(context.Background(), property, 30*time.Second)
+}
+
+// getActivation populates the Activation resource, returning the poll
+// interval suggested by the Retry-After header, no shorter than minimum
+func (activation *Activation) getActivation(ctx context.Context, property *Property, minimum time.Duration) (time.Duration, error) {
+	query := url.Values{}
+	if property.ContractID != "" {
+		query.Set("contractId", property.ContractID)
+	}
+	if property.GroupID != "" {
+		query.Set("groupId", property.GroupID)
+	}
+
 	req, err := client.NewRequest(
 		Config,
 		"GET",
 		fmt.Sprintf(
-			"/papi/v1/properties/%s/activations/%s?contractId=%s&groupId=%s",
+			"/papi/v1/properties/%s/activations/%s?%s",
 			property.PropertyID,
 			activation.ActivationID,
-			property.ContractID,
-			property.GroupID,
+			query.Encode(),
 		),
 		nil,
 	)
@@ -174,6 +205,8 @@ func (activation *Activation) GetActivation(property *Property) (time.Duration,
 		return 0, err
 	}
 
+	req = req.WithContext(ctx)
+
 	edge.PrintHttpRequest(req, true)
 
 	res, err := client.Do(Config, req)
@@ -207,11 +240,29 @@ func (activation *Activation) GetActivation(property *Property) (time.Duration,
 	activation.UpdateDate = activations.Activations.Items[0].UpdateDate
 	activation.Note = activations.Activations.Items[0].Note
 	activation.NotifyEmails = activations.Activations.Items[0].NotifyEmails
+	activation.FMAPercent = activations.Activations.Items[0].FMAPercent
+	activation.FallbackInfo = activations.Activations.Items[0].FallbackInfo
+
+	return retryAfter(res.Header.Get("Retry-After"), minimum), nil
+}
 
-	//retry, _ := strconv.Atoi(res.Header.Get("Retry-After"))
-	//retry *= int(time.Second)
+// retryAfter parses a Retry-After header, given in seconds or as a date.
+// The result is never less than minimum, which is also used when the header
+// is missing or invalid, so a Retry-After of 0 or a past date does not poll
+// PAPI without a pause.
+func retryAfter(header string, minimum time.Duration) time.Duration {
+	wait := minimum
+	if seconds, err := strconv.Atoi(header); err == nil && seconds >= 0 {
+		wait = time.Duration(seconds) * time.Second
+	} else if date, err := http.ParseTime(header); err == nil {
+		wait = time.Until(date)
+	}
+
+	if wait < minimum {
+		return minimum
+	}
 
-	return time.Duration(30 * time.Second), nil
+	return wait
 }
 
 // Save activates a given property
@@ -219,10 +270,35 @@ func (activation *Activation) GetActivation(property *Property) (time.Duration,
 // If acknowledgeWarnings is true and warnings are returned on the first attempt,
 // a second attempt is made, acknowledging the warnings.
 //
+// See: Activation.SaveWithPolicy() to acknowledge only some warnings
 // See: Property.Activate()
 // API Docs: https://developer.akamai.com/api/luna/papi/resources.html#activateaproperty
 // Endpoint: POST /papi/v1/properties/{propertyId}/activations/{?contractId,groupId}
 func (activation *Activation) Save(property *Property, acknowledgeWarnings bool) error {
+	var policy ActivationWarningPolicy
+	if acknowledgeWarnings {
+		policy = AcknowledgeAllWarnings
+	}
+
+	return activation.SaveWithPolicy(property, policy)
+}
+
+// SaveWithPolicy activates a given property, deciding which warnings to
+// acknowledge with policy
+//
+// If warnings are returned on the first attempt they are stored in
+// activation.Warnings and policy is called for each of them. When policy
+// approves every warning, a second attempt is made acknowledging the approved
+// message IDs. Otherwise the activation is not resubmitted and the rejected
+// warnings are returned as ActivationWarnings.
+//
+// With a nil policy, the API error is returned and activation.Warnings is set,
+// so the warnings can be reviewed and added to AcknowledgeWarnings before
+// trying again.
+//
+// API Docs: https://developer.akamai.com/api/luna/papi/resources.html#activateaproperty
+// Endpoint: POST /papi/v1/properties/{propertyId}/activations/{?contractId,groupId}
+func (activation *Activation) SaveWithPolicy(property *Property, policy ActivationWarningPolicy) error {
 	if activation.ComplianceRecord == nil {
 		activation.ComplianceRecord = &ActivationComplianceRecord{
 			NoncomplianceReason: "NO_PRODUCTION_TRAFFIC",
@@ -255,44 +331,42 @@ func (activation *Activation) Save(property *Property, acknowledgeWarnings bool)
 
 	edge.PrintHttpResponse(res, true)
 
-	if client.IsError(res) && (!acknowledgeWarnings || (acknowledgeWarnings && res.StatusCode != 400)) {
+	if client.IsError(res) && res.StatusCode != 400 {
 		return client.NewAPIError(res)
 	}
 
-	if res.StatusCode == 400 && acknowledgeWarnings {
-		warnings := &struct {
-			Warnings []struct {
-				Detail    string `json:"detail"`
-				MessageID string `json:"messageId"`
-			} `json:"warnings,omitempty"`
-		}{}
-
+	if res.StatusCode == 400 {
 		body, err := ioutil.ReadAll(res.Body)
 		if err != nil {
 			return err
 		}
 
-		if err = json.Unmarshal(body, warnings); err != nil {
-			return err
+		// Just in case we got a 400 for a different reason
+		warnings := parseActivationWarnings(body)
+		if len(warnings) == 0 {
+			return client.NewAPIErrorFromBody(res, body)
 		}
 
-		// Just in case we got a 400 for a different reason
-		if len(warnings.Warnings) == 0 {
-			jsonBody := &client.JSONBody{}
+		activation.Warnings = warnings
+		if policy == nil {
+			return client.NewAPIErrorFromBody(res, body)
+		}
 
-			if err = json.Unmarshal(body, jsonBody); err != nil {
-				return err
+		var rejected ActivationWarnings
+		for _, warning := range warnings {
+			if !policy(warning) {
+				rejected = append(rejected, warning)
 			}
-
-			return client.NewAPIErrorFromBody(res, body)
 		}
 
-		for _, warning := range warnings.Warnings {
-			activation.AcknowledgeWarnings = append(activation.AcknowledgeWarnings, warning.MessageID)
+		if len(rejected) != 0 {
+			return rejected
 		}
 
-		// Don't acknowledgeWarnings again, halting a potential endless recursion
-		return activation.Save(property, false)
+		activation.AcknowledgeWarnings = append(activation.AcknowledgeWarnings, warnings.MessageIDs()...)
+
+		// Don't acknowledge warnings again, halting a potential endless recursion
+		return activation.SaveWithPolicy(property, nil)
 	}
 
 	var location client.JSONBody
@@ -399,6 +473,203 @@ func (activation *Activation) PollStatus(property *Property) bool {
 	return true
 }
 
+// ActivationEvent reports the progress of an activation to Activation.Wait callers
+type ActivationEvent struct {
+	ActivationID string
+	Network      NetworkValue
+	OldStatus    StatusValue
+	NewStatus    StatusValue
+	// FMAPercent is the rollout progress, when PAPI reports it
+	FMAPercent *float64
+	// Elapsed is the time since Wait was called
+	Elapsed time.Duration
+	// NextPoll is how long Wait will sleep before polling again, zero once the
+	// activation is done
+	NextPoll time.Duration
+}
+
+// ActivationWaitOptions configures Activation.Wait
+type ActivationWaitOptions struct {
+	// Property the activation belongs to. Defaults to one built from the
+	// activation's PropertyID and the parent Activations' contract and group
+	Property *Property
+	// Events, if set, receives an event each time the status or FMAPercent
+	// changes. Sends block, so the channel should be drained or buffered
+	Events chan<- ActivationEvent
+	// PollInterval is the shortest poll interval, used unless PAPI suggests a
+	// longer one with Retry-After. Defaults to 30 seconds
+	PollInterval time.Duration
+	// MaxPollInterval caps the poll interval. Defaults to one minute for
+	// staging and five minutes for production
+	MaxPollInterval time.Duration
+}
+
+// IsTerminal reports whether an activation with this status is done
+func (status StatusValue) IsTerminal() bool {
+	switch status {
+	case StatusActive, StatusFailed, StatusAborted, StatusDeactivated:
+		return true
+	}
+
+	return false
+}
+
+// Wait polls the activation until its status is terminal or ctx is done
+//
+// The poll interval follows PAPI's Retry-After header, no shorter than
+// opts.PollInterval and capped by opts.MaxPollInterval, and never sleeps past the ctx deadline. Wait returns
+// nil once the status is ACTIVE or DEACTIVATED, and an error wrapping
+// ErrorMap[ErrActivationFailed] if it is FAILED or ABORTED. If ctx is done
+// first, its error is returned wrapped with the last known status.
+//
+//	ctx, cancel := context.WithTimeout(context.Background(), time.Hour)
+//	defer cancel()
+//
+//	events := make(chan papi.ActivationEvent, 10)
+//	go func() {
+//		for event := range events {
+//			log.Printf("%s: %s -> %s", event.Elapsed, event.OldStatus, event.NewStatus)
+//		}
+//	}()
+//
+//	err := activation.Wait(ctx, &papi.ActivationWaitOptions{Events: events})
+//	close(events)
+func (activation *Activation) Wait(ctx context.Context, opts *ActivationWaitOptions) error {
+	if opts == nil {
+		opts = &ActivationWaitOptions{}
+	}
+
+	property := opts.Property
+	if property == nil {
+		property = NewProperty(NewProperties())
+		property.PropertyID = activation.PropertyID
+		if activation.parent != nil {
+			property.ContractID = activation.parent.ContractID
+			property.GroupID = activation.parent.GroupID
+		}
+	}
+
+	return waitForActivation(ctx, &propertyActivationPoller{activation: activation, property: property}, opts)
+}
+
+// activationPoller is an activation that waitForActivation can poll
+type activationPoller interface {
+	// poll refreshes the activation, returning the poll interval suggested
+	// by PAPI, no shorter than minimum
+	poll(ctx context.Context, minimum time.Duration) (time.Duration, error)
+	state() activationState
+}
+
+// activationState is the progress of an activation
+type activationState struct {
+	activationID string
+	network      NetworkValue
+	status       StatusValue
+	fmaPercent   *float64
+}
+
+// propertyActivationPoller polls a property activation
+type propertyActivationPoller struct {
+	activation *Activation
+	property   *Property
+}
+
+func (poller *propertyActivationPoller) poll(ctx context.Context, minimum time.Duration) (time.Duration, error) {
+	return poller.activation.getActivation(ctx, poller.property, minimum)
+}
+
+func (poller *propertyActivationPoller) state() activationState {
+	return activationState{
+		activationID: poller.activation.ActivationID,
+		network:      poller.activation.Network,
+		status:       poller.activation.Status,
+		fmaPercent:   poller.activation.FMAPercent,
+	}
+}
+
+// waitForActivation implements Activation.Wait for any activationPoller
+func waitForActivation(ctx context.Context, poller activationPoller, opts *ActivationWaitOptions) error {
+	pollInterval := opts.PollInterval
+	if pollInterval == 0 {
+		pollInterval = 30 * time.Second
+	}
+
+	start := time.Now()
+	for {
+		old := poller.state()
+
+		retry, err := poller.poll(ctx, pollInterval)
+		if err != nil {
+			if ctx.Err() != nil {
+				return fmt.Errorf("activation %s still %s: %w", old.activationID, old.status, ctx.Err())
+			}
+			return err
+		}
+
+		current := poller.state()
+
+		maxPollInterval := opts.MaxPollInterval
+		if maxPollInterval == 0 {
+			maxPollInterval = 5 * time.Minute
+			if current.network == NetworkStaging {
+				maxPollInterval = time.Minute
+			}
+		}
+
+		if retry > maxPollInterval {
+			retry = maxPollInterval
+		}
+		if deadline, ok := ctx.Deadline(); ok && time.Until(deadline) < retry {
+			retry = time.Until(deadline)
+		}
+		if current.status.IsTerminal() {
+			retry = 0
+		}
+
+		if old.status != current.status || !equalPercent(old.fmaPercent, current.fmaPercent) {
+			event := ActivationEvent{
+				ActivationID: current.activationID,
+				Network:      current.network,
+				OldStatus:    old.status,
+				NewStatus:    current.status,
+				FMAPercent:   current.fmaPercent,
+				Elapsed:      time.Since(start),
+				NextPoll:     retry,
+			}
+
+			if opts.Events != nil {
+				select {
+				case opts.Events <- event:
+				case <-ctx.Done():
+				}
+			}
+		}
+
+		switch current.status {
+		case StatusActive, StatusDeactivated:
+			return nil
+		case StatusFailed, StatusAborted:
+			return fmt.Errorf("%w: activation %s is %s", ErrorMap[ErrActivationFailed], current.activationID, current.status)
+		}
+
+		timer := time.NewTimer(retry)
+		select {
+		case <-timer.C:
+		case <-ctx.Done():
+			timer.Stop()
+			return fmt.Errorf("activation %s still %s: %w", current.activationID, current.status, ctx.Err())
+		}
+	}
+}
+
+func equalPercent(a, b *float64) bool {
+	if a == nil || b == nil {
+		return a == b
+	}
+
+	return *a == *b
+}
+
 // Cancel an activation in progress
 //
 // API Docs: https://developer.akamai.com/api/luna/papi/resources.html#cancelapendingactivation
@@ -0,0 +1,123 @@
+package papi
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"gopkg.in/h2non/gock.v1"
+)
+
+func mockActivationStatus(network NetworkValue, status StatusValue, extra string) {
+	gock.New("https://akaa-baseurl-xxxxxxxxxxx-xxxxxxxxxxxxx.luna.akamaiapis.net").
+		Get("/papi/v1/properties/prp_123/activations/atv_456").
+		MatchParam("contractId", "ctr_1").
+		Reply(200).
+		SetHeader("Content-Type", "application/json").
+		SetHeader("Retry-After", "0").
+		BodyString(`{
+			"activations": {
+				"items": [{
+					"activationId": "atv_456",
+					"propertyId": "prp_123",
+					"propertyVersion": 2,
+					"network": "` + string(network) + `",
+					"status": "` + string(status) + `"` + extra + `
+				}]
+			}
+		}`)
+}
+
+func TestActivation_Wait(t *testing.T) {
+	defer gock.Off()
+
+	mockActivationStatus(NetworkProduction, StatusPending, "")
+	mockActivationStatus(NetworkProduction, StatusZone1, `, "fmaPercent": 10`)
+	mockActivationStatus(NetworkProduction, StatusZone1, `, "fmaPercent": 60`)
+	mockActivationStatus(NetworkProduction, StatusActive, `, "fmaPercent": 100`)
+
+	Init(config)
+
+	activations := NewActivations()
+	activations.ContractID = "ctr_1"
+	activation := NewActivation(activations)
+	activation.ActivationID = "atv_456"
+	activation.PropertyID = "prp_123"
+
+	events := make(chan ActivationEvent, 10)
+	err := activation.Wait(context.Background(), &ActivationWaitOptions{Events: events, PollInterval: time.Millisecond})
+	close(events)
+
+	assert.NoError(t, err)
+	assert.Equal(t, StatusActive, activation.Status)
+	assert.True(t, gock.IsDone())
+
+	var statuses []StatusValue
+	var percents []float64
+	for event := range events {
+		statuses = append(statuses, event.OldStatus, event.NewStatus)
+		if event.FMAPercent != nil {
+			percents = append(percents, *event.FMAPercent)
+		}
+	}
+	assert.Equal(t, []StatusValue{"", StatusPending, StatusPending, StatusZone1, StatusZone1, StatusZone1, StatusZone1, StatusActive}, statuses)
+	assert.Equal(t, []float64{10, 60, 100}, percents)
+}
+
+func TestActivation_Wait_Failed(t *testing.T) {
+	defer gock.Off()
+
+	mockActivationStatus(NetworkStaging, StatusFailed, "")
+
+	Init(config)
+
+	activation := NewActivation(NewActivations())
+	activation.ActivationID = "atv_456"
+	property := NewProperty(NewProperties())
+	property.PropertyID = "prp_123"
+	property.ContractID = "ctr_1"
+
+	err := activation.Wait(context.Background(), &ActivationWaitOptions{Property: property, PollInterval: time.Millisecond})
+
+	assert.True(t, errors.Is(err, ErrorMap[ErrActivationFailed]))
+	assert.Equal(t, StatusFailed, activation.Status)
+}
+
+func TestActivation_Wait_Deadline(t *testing.T) {
+	defer gock.Off()
+
+	gock.New("https://akaa-baseurl-xxxxxxxxxxx-xxxxxxxxxxxxx.luna.akamaiapis.net").
+		Get("/papi/v1/properties/prp_123/activations/atv_456").
+		Persist().
+		Reply(200).
+		SetHeader("Content-Type", "application/json").
+		SetHeader("Retry-After", "600").
+		BodyString(`{"activations": {"items": [{"activationId": "atv_456", "network": "STAGING", "status": "PENDING"}]}}`)
+
+	Init(config)
+
+	activation := NewActivation(NewActivations())
+	activation.ActivationID = "atv_456"
+	activation.PropertyID = "prp_123"
+
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+
+	start := time.Now()
+	err := activation.Wait(ctx, nil)
+
+	assert.True(t, errors.Is(err, context.DeadlineExceeded))
+	assert.Contains(t, err.Error(), "still PENDING")
+	assert.True(t, time.Since(start) < 5*time.Second)
+}
+
+func TestRetryAfter(t *testing.T) {
+	assert.Equal(t, 30*time.Second, retryAfter("", 30*time.Second))
+	assert.Equal(t, 45*time.Second, retryAfter("45", 30*time.Second))
+	assert.Equal(t, 30*time.Second, retryAfter("5", 30*time.Second))
+	assert.Equal(t, 30*time.Second, retryAfter("0", 30*time.Second))
+	assert.Equal(t, 30*time.Second, retryAfter("soon", 30*time.Second))
+	assert.Equal(t, 30*time.Second, retryAfter("Mon, 02 Jan 2006 15:04:05 GMT", 30*time.Second))
+}
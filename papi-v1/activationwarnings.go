@@ -0,0 +1,92 @@
+package papi
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+)
+
+// ActivationWarning is a warning returned by PAPI when activating a property.
+// The activation is only accepted once its warnings are acknowledged.
+//
+// See: Activation.SaveWithPolicy()
+type ActivationWarning struct {
+	// Type is the URL identifying the kind of warning
+	Type      string `json:"type"`
+	Title     string `json:"title,omitempty"`
+	Detail    string `json:"detail,omitempty"`
+	MessageID string `json:"messageId"`
+}
+
+// String returns a single line description of the warning
+func (warning *ActivationWarning) String() string {
+	if warning.Title != "" {
+		return fmt.Sprintf("%s (%s): %s", warning.Title, warning.MessageID, warning.Detail)
+	}
+
+	return fmt.Sprintf("%s (%s): %s", warning.Type, warning.MessageID, warning.Detail)
+}
+
+// ActivationWarnings is returned by Activation.SaveWithPolicy when warnings
+// were not acknowledged
+type ActivationWarnings []*ActivationWarning
+
+func (warnings ActivationWarnings) Error() string {
+	messages := make([]string, len(warnings))
+	for key, warning := range warnings {
+		messages[key] = warning.String()
+	}
+
+	return fmt.Sprintf("%s:\n%s", ErrorMap[ErrWarningsNotAcknowledged], strings.Join(messages, "\n"))
+}
+
+// Unwrap allows errors.Is(err, ErrorMap[ErrWarningsNotAcknowledged])
+func (warnings ActivationWarnings) Unwrap() error {
+	return ErrorMap[ErrWarningsNotAcknowledged]
+}
+
+// MessageIDs returns the message IDs of the warnings
+func (warnings ActivationWarnings) MessageIDs() []string {
+	messageIDs := make([]string, 0, len(warnings))
+	for _, warning := range warnings {
+		messageIDs = append(messageIDs, warning.MessageID)
+	}
+
+	return messageIDs
+}
+
+// ActivationWarningPolicy decides whether a warning is acknowledged
+type ActivationWarningPolicy func(warning *ActivationWarning) bool
+
+// AcknowledgeAllWarnings is an ActivationWarningPolicy acknowledging every
+// warning, as Activation.Save does when acknowledgeWarnings is true
+func AcknowledgeAllWarnings(warning *ActivationWarning) bool {
+	return true
+}
+
+// AcknowledgeWarningTypes returns an ActivationWarningPolicy acknowledging
+// the warnings with one of the given type URLs
+func AcknowledgeWarningTypes(types ...string) ActivationWarningPolicy {
+	allowed := map[string]bool{}
+	for _, warningType := range types {
+		allowed[warningType] = true
+	}
+
+	return func(warning *ActivationWarning) bool {
+		return allowed[warning.Type]
+	}
+}
+
+// parseActivationWarnings returns the warnings in the body of a rejected
+// activation, if any
+func parseActivationWarnings(body []byte) ActivationWarnings {
+	response := &struct {
+		Warnings ActivationWarnings `json:"warnings,omitempty"`
+	}{}
+
+	if err := json.Unmarshal(body, response); err != nil {
+		return nil
+	}
+
+	return response.Warnings
+}
@@ -0,0 +1,125 @@
+package papi
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/akamai/AkamaiOPEN-edgegrid-golang/client-v1"
+	"github.com/stretchr/testify/assert"
+	"gopkg.in/h2non/gock.v1"
+)
+
+func mockActivationWarnings() {
+	gock.New("https://akaa-baseurl-xxxxxxxxxxx-xxxxxxxxxxxxx.luna.akamaiapis.net").
+		Post("/papi/v1/properties/prp_123/activations").
+		MatchParam("contractId", "ctr_1").
+		Reply(400).
+		SetHeader("Content-Type", "application/problem+json").
+		BodyString(`{
+			"type": "https://problems.luna.akamaiapis.net/papi/v0/activation-warnings-not-acknowledged",
+			"title": "Activation Warnings Not Acknowledged",
+			"status": 400,
+			"warnings": [{
+				"type": "https://problems.luna.akamaiapis.net/papi/v0/validation/validation_message.ssl_custom_origin",
+				"title": "SSL custom origin",
+				"detail": "The origin certificate must be valid for the hostname.",
+				"messageId": "msg_1"
+			}, {
+				"type": "https://problems.luna.akamaiapis.net/papi/v0/validation/validation_message.variable_default_value",
+				"title": "Variable default value",
+				"detail": "PMUSER_ORIGIN has no default value.",
+				"messageId": "msg_2"
+			}]
+		}`)
+}
+
+func TestActivation_SaveWithPolicy(t *testing.T) {
+	defer gock.Off()
+
+	mockActivationWarnings()
+
+	gock.New("https://akaa-baseurl-xxxxxxxxxxx-xxxxxxxxxxxxx.luna.akamaiapis.net").
+		Post("/papi/v1/properties/prp_123/activations").
+		MatchParam("contractId", "ctr_1").
+		BodyString(`"acknowledgeWarnings":\["msg_1","msg_2"\]`).
+		Reply(201).
+		SetHeader("Content-Type", "application/json").
+		BodyString(`{"activationLink": "/papi/v1/properties/prp_123/activations/atv_456?contractId=ctr_1&groupId=grp_1"}`)
+
+	mockActivationStatus(NetworkStaging, StatusPending, "")
+
+	Init(config)
+
+	property := NewProperty(NewProperties())
+	property.PropertyID = "prp_123"
+	property.ContractID = "ctr_1"
+	property.GroupID = "grp_1"
+
+	activation := NewActivation(NewActivations())
+	activation.PropertyVersion = 2
+	activation.Network = NetworkStaging
+
+	err := activation.SaveWithPolicy(property, AcknowledgeWarningTypes(
+		"https://problems.luna.akamaiapis.net/papi/v0/validation/validation_message.ssl_custom_origin",
+		"https://problems.luna.akamaiapis.net/papi/v0/validation/validation_message.variable_default_value",
+	))
+
+	assert.NoError(t, err)
+	assert.Equal(t, "atv_456", activation.ActivationID)
+	assert.Equal(t, []string{"msg_1", "msg_2"}, activation.Warnings.MessageIDs())
+	assert.Equal(t, "SSL custom origin", activation.Warnings[0].Title)
+	assert.True(t, gock.IsDone())
+}
+
+func TestActivation_SaveWithPolicy_Rejected(t *testing.T) {
+	defer gock.Off()
+
+	mockActivationWarnings()
+
+	Init(config)
+
+	property := NewProperty(NewProperties())
+	property.PropertyID = "prp_123"
+	property.ContractID = "ctr_1"
+	property.GroupID = "grp_1"
+
+	activation := NewActivation(NewActivations())
+	activation.PropertyVersion = 2
+	activation.Network = NetworkProduction
+
+	err := activation.SaveWithPolicy(property, func(warning *ActivationWarning) bool {
+		return warning.MessageID != "msg_2"
+	})
+
+	var rejected ActivationWarnings
+	assert.True(t, errors.As(err, &rejected))
+	assert.True(t, errors.Is(err, ErrorMap[ErrWarningsNotAcknowledged]))
+	assert.Equal(t, []string{"msg_2"}, rejected.MessageIDs())
+	assert.Contains(t, err.Error(), "PMUSER_ORIGIN has no default value.")
+	assert.Empty(t, activation.AcknowledgeWarnings)
+	assert.Len(t, activation.Warnings, 2)
+}
+
+func TestActivation_Save_WithoutAcknowledging(t *testing.T) {
+	defer gock.Off()
+
+	mockActivationWarnings()
+
+	Init(config)
+
+	property := NewProperty(NewProperties())
+	property.PropertyID = "prp_123"
+	property.ContractID = "ctr_1"
+	property.GroupID = "grp_1"
+
+	activation := NewActivation(NewActivations())
+	activation.PropertyVersion = 2
+	activation.Network = NetworkStaging
+
+	err := activation.Save(property, false)
+
+	var apiError client.APIError
+	assert.True(t, errors.As(err, &apiError))
+	assert.Equal(t, 400, apiError.Status)
+	assert.Equal(t, []string{"msg_1", "msg_2"}, activation.Warnings.MessageIDs())
+}
@@ -0,0 +1,112 @@
+// Package behaviors provides typed PAPI behaviors for a subset of rule format
+// v2021-09-22
+//
+// Each behavior is a struct with a field per option, and enum options are
+// typed constants, so option names and values are checked by the compiler
+// instead of failing at papi.Rules.Save():
+//
+//	origin := &behaviors.Origin{
+//		OriginType:        behaviors.OriginOriginTypeCustomer,
+//		Hostname:          "origin.example.com",
+//		ForwardHostHeader: behaviors.OriginForwardHostHeaderRequestHostHeader,
+//		OriginSNI:         behaviors.Bool(true),
+//	}
+//	rule.MergeBehavior(origin.ToBehavior())
+//
+// Options left empty are omitted, so PAPI applies their defaults. Boolean and
+// number options are pointers so that false and 0 can be set.
+//
+// Only allowPost, caching, cpCode, downstreamCache, gzipResponse,
+// modifyOutgoingResponseHeader, origin, prefreshCache and setVariable are
+// covered. They are generated for RuleFormat by papi-v1/internal/gen from an
+// excerpt of its schema, see go:generate below. Other behaviors are built as
+// *papi.Behavior, and FromBehavior returns an error for them.
+package behaviors
+
+//go:generate go run ../internal/gen -kind behaviors -format v2021-09-22 -schema ../internal/gen/schemas/v2021-09-22-excerpt.json -out behaviors_gen.go
+
+import (
+	"encoding/json"
+	"fmt"
+
+	papi "github.com/akamai/AkamaiOPEN-edgegrid-golang/papi-v1"
+)
+
+// Behavior is a typed behavior
+type Behavior interface {
+	// Name returns the behavior name, e.g. origin
+	Name() string
+	// ToBehavior converts the behavior to a *papi.Behavior
+	ToBehavior() *papi.Behavior
+	// FromBehavior sets the options from a *papi.Behavior with the same name
+	FromBehavior(from *papi.Behavior) error
+}
+
+// FromBehavior converts a *papi.Behavior to its typed behavior
+//
+// Options unknown to RuleFormat, and the behavior's UUID and Locked, are not
+// kept.
+func FromBehavior(behavior *papi.Behavior) (Behavior, error) {
+	builder, ok := builders[behavior.Name]
+	if !ok {
+		return nil, fmt.Errorf("behavior %s is not generated for rule format %s", behavior.Name, RuleFormat)
+	}
+
+	typed := builder()
+	if err := typed.FromBehavior(behavior); err != nil {
+		return nil, err
+	}
+
+	return typed, nil
+}
+
+// Bool returns a pointer to value, for boolean options
+func Bool(value bool) *bool {
+	return &value
+}
+
+// Int returns a pointer to value, for integer options
+func Int(value int) *int {
+	return &value
+}
+
+// Float64 returns a pointer to value, for number options
+func Float64(value float64) *float64 {
+	return &value
+}
+
+// newBehavior creates a *papi.Behavior with the options of a typed behavior
+func newBehavior(name string, options interface{}) *papi.Behavior {
+	behavior := papi.NewBehavior()
+	behavior.Name = name
+
+	// Generated structs only hold JSON types, so this fails only if the
+	// generator is broken
+	data, err := json.Marshal(options)
+	if err != nil {
+		panic(fmt.Sprintf("behavior %s: %v", name, err))
+	}
+	if err := json.Unmarshal(data, &behavior.Options); err != nil {
+		panic(fmt.Sprintf("behavior %s: %v", name, err))
+	}
+
+	return behavior
+}
+
+// fromBehavior sets the options of a typed behavior from a *papi.Behavior
+func fromBehavior(behavior *papi.Behavior, name string, options interface{}) error {
+	if behavior.Name != name {
+		return fmt.Errorf("cannot convert behavior %s to %s", behavior.Name, name)
+	}
+
+	data, err := json.Marshal(behavior.Options)
+	if err != nil {
+		return err
+	}
+
+	if err := json.Unmarshal(data, options); err != nil {
+		return fmt.Errorf("behavior %s: %w", name, err)
+	}
+
+	return nil
+}
@@ -0,0 +1,500 @@
+// Code generated by papi-v1/internal/gen from rule format v2021-09-22. DO NOT EDIT.
+
+package behaviors
+
+import papi "github.com/akamai/AkamaiOPEN-edgegrid-golang/papi-v1"
+
+// RuleFormat is the rule format the behaviors were generated from
+const RuleFormat = "v2021-09-22"
+
+// AllowPost is the allowPost behavior
+type AllowPost struct {
+	AllowWithoutContentLength *bool `json:"allowWithoutContentLength,omitempty"`
+	Enabled                   *bool `json:"enabled,omitempty"`
+}
+
+// Name returns the behavior name, allowPost
+func (behavior *AllowPost) Name() string {
+	return "allowPost"
+}
+
+// ToBehavior converts the behavior to a *papi.Behavior
+func (behavior *AllowPost) ToBehavior() *papi.Behavior {
+	return newBehavior("allowPost", behavior)
+}
+
+// FromBehavior sets the options from a *papi.Behavior named allowPost
+func (behavior *AllowPost) FromBehavior(from *papi.Behavior) error {
+	return fromBehavior(from, "allowPost", behavior)
+}
+
+// Caching is the caching behavior
+type Caching struct {
+	Behavior             CachingBehaviorValue `json:"behavior,omitempty"`
+	DefaultTTL           string               `json:"defaultTtl,omitempty"`
+	EnhancedRfcSupport   *bool                `json:"enhancedRfcSupport,omitempty"`
+	HonorMaxAge          *bool                `json:"honorMaxAge,omitempty"`
+	HonorMustRevalidate  *bool                `json:"honorMustRevalidate,omitempty"`
+	HonorNoCache         *bool                `json:"honorNoCache,omitempty"`
+	HonorNoStore         *bool                `json:"honorNoStore,omitempty"`
+	HonorPrivate         *bool                `json:"honorPrivate,omitempty"`
+	HonorProxyRevalidate *bool                `json:"honorProxyRevalidate,omitempty"`
+	HonorSMaxage         *bool                `json:"honorSMaxage,omitempty"`
+	MustRevalidate       *bool                `json:"mustRevalidate,omitempty"`
+	TTL                  string               `json:"ttl,omitempty"`
+}
+
+// Name returns the behavior name, caching
+func (behavior *Caching) Name() string {
+	return "caching"
+}
+
+// ToBehavior converts the behavior to a *papi.Behavior
+func (behavior *Caching) ToBehavior() *papi.Behavior {
+	return newBehavior("caching", behavior)
+}
+
+// FromBehavior sets the options from a *papi.Behavior named caching
+func (behavior *Caching) FromBehavior(from *papi.Behavior) error {
+	return fromBehavior(from, "caching", behavior)
+}
+
+// CachingBehaviorValue is used to create an "enum" of possible Caching.Behavior values
+type CachingBehaviorValue string
+
+const (
+	// CachingBehaviorMaxAge Caching.Behavior value MAX_AGE
+	CachingBehaviorMaxAge CachingBehaviorValue = "MAX_AGE"
+	// CachingBehaviorNoStore Caching.Behavior value NO_STORE
+	CachingBehaviorNoStore CachingBehaviorValue = "NO_STORE"
+	// CachingBehaviorBypassCache Caching.Behavior value BYPASS_CACHE
+	CachingBehaviorBypassCache CachingBehaviorValue = "BYPASS_CACHE"
+	// CachingBehaviorCacheControlAndExpires Caching.Behavior value CACHE_CONTROL_AND_EXPIRES
+	CachingBehaviorCacheControlAndExpires CachingBehaviorValue = "CACHE_CONTROL_AND_EXPIRES"
+	// CachingBehaviorCacheControl Caching.Behavior value CACHE_CONTROL
+	CachingBehaviorCacheControl CachingBehaviorValue = "CACHE_CONTROL"
+	// CachingBehaviorExpires Caching.Behavior value EXPIRES
+	CachingBehaviorExpires CachingBehaviorValue = "EXPIRES"
+)
+
+// CpCode is the cpCode behavior
+type CpCode struct {
+	Value *CpCodeValue `json:"value,omitempty"`
+}
+
+// Name returns the behavior name, cpCode
+func (behavior *CpCode) Name() string {
+	return "cpCode"
+}
+
+// ToBehavior converts the behavior to a *papi.Behavior
+func (behavior *CpCode) ToBehavior() *papi.Behavior {
+	return newBehavior("cpCode", behavior)
+}
+
+// FromBehavior sets the options from a *papi.Behavior named cpCode
+func (behavior *CpCode) FromBehavior(from *papi.Behavior) error {
+	return fromBehavior(from, "cpCode", behavior)
+}
+
+// CpCodeValue is the value of CpCode.Value
+type CpCodeValue struct {
+	CreatedDate *int     `json:"createdDate,omitempty"`
+	Description string   `json:"description,omitempty"`
+	ID          *int     `json:"id,omitempty"`
+	Name        string   `json:"name,omitempty"`
+	Products    []string `json:"products,omitempty"`
+}
+
+// DownstreamCache is the downstreamCache behavior
+type DownstreamCache struct {
+	AllowBehavior DownstreamCacheAllowBehaviorValue `json:"allowBehavior,omitempty"`
+	Behavior      DownstreamCacheBehaviorValue      `json:"behavior,omitempty"`
+	SendHeaders   DownstreamCacheSendHeadersValue   `json:"sendHeaders,omitempty"`
+	SendPrivate   *bool                             `json:"sendPrivate,omitempty"`
+	TTL           string                            `json:"ttl,omitempty"`
+}
+
+// Name returns the behavior name, downstreamCache
+func (behavior *DownstreamCache) Name() string {
+	return "downstreamCache"
+}
+
+// ToBehavior converts the behavior to a *papi.Behavior
+func (behavior *DownstreamCache) ToBehavior() *papi.Behavior {
+	return newBehavior("downstreamCache", behavior)
+}
+
+// FromBehavior sets the options from a *papi.Behavior named downstreamCache
+func (behavior *DownstreamCache) FromBehavior(from *papi.Behavior) error {
+	return fromBehavior(from, "downstreamCache", behavior)
+}
+
+// DownstreamCacheAllowBehaviorValue is used to create an "enum" of possible DownstreamCache.AllowBehavior values
+type DownstreamCacheAllowBehaviorValue string
+
+const (
+	// DownstreamCacheAllowBehaviorLesser DownstreamCache.AllowBehavior value LESSER
+	DownstreamCacheAllowBehaviorLesser DownstreamCacheAllowBehaviorValue = "LESSER"
+	// DownstreamCacheAllowBehaviorGreater DownstreamCache.AllowBehavior value GREATER
+	DownstreamCacheAllowBehaviorGreater DownstreamCacheAllowBehaviorValue = "GREATER"
+	// DownstreamCacheAllowBehaviorRemainingLifetime DownstreamCache.AllowBehavior value REMAINING_LIFETIME
+	DownstreamCacheAllowBehaviorRemainingLifetime DownstreamCacheAllowBehaviorValue = "REMAINING_LIFETIME"
+	// DownstreamCacheAllowBehaviorFromMaxAge DownstreamCache.AllowBehavior value FROM_MAX_AGE
+	DownstreamCacheAllowBehaviorFromMaxAge DownstreamCacheAllowBehaviorValue = "FROM_MAX_AGE"
+	// DownstreamCacheAllowBehaviorFromValue DownstreamCache.AllowBehavior value FROM_VALUE
+	DownstreamCacheAllowBehaviorFromValue DownstreamCacheAllowBehaviorValue = "FROM_VALUE"
+	// DownstreamCacheAllowBehaviorPassOrigin DownstreamCache.AllowBehavior value PASS_ORIGIN
+	DownstreamCacheAllowBehaviorPassOrigin DownstreamCacheAllowBehaviorValue = "PASS_ORIGIN"
+)
+
+// DownstreamCacheBehaviorValue is used to create an "enum" of possible DownstreamCache.Behavior values
+type DownstreamCacheBehaviorValue string
+
+const (
+	// DownstreamCacheBehaviorAllow DownstreamCache.Behavior value ALLOW
+	DownstreamCacheBehaviorAllow DownstreamCacheBehaviorValue = "ALLOW"
+	// DownstreamCacheBehaviorMustRevalidate DownstreamCache.Behavior value MUST_REVALIDATE
+	DownstreamCacheBehaviorMustRevalidate DownstreamCacheBehaviorValue = "MUST_REVALIDATE"
+	// DownstreamCacheBehaviorBust DownstreamCache.Behavior value BUST
+	DownstreamCacheBehaviorBust DownstreamCacheBehaviorValue = "BUST"
+	// DownstreamCacheBehaviorTunnelOrigin DownstreamCache.Behavior value TUNNEL_ORIGIN
+	DownstreamCacheBehaviorTunnelOrigin DownstreamCacheBehaviorValue = "TUNNEL_ORIGIN"
+	// DownstreamCacheBehaviorNone DownstreamCache.Behavior value NONE
+	DownstreamCacheBehaviorNone DownstreamCacheBehaviorValue = "NONE"
+)
+
+// DownstreamCacheSendHeadersValue is used to create an "enum" of possible DownstreamCache.SendHeaders values
+type DownstreamCacheSendHeadersValue string
+
+const (
+	// DownstreamCacheSendHeadersCacheControlAndExpires DownstreamCache.SendHeaders value CACHE_CONTROL_AND_EXPIRES
+	DownstreamCacheSendHeadersCacheControlAndExpires DownstreamCacheSendHeadersValue = "CACHE_CONTROL_AND_EXPIRES"
+	// DownstreamCacheSendHeadersCacheControl DownstreamCache.SendHeaders value CACHE_CONTROL
+	DownstreamCacheSendHeadersCacheControl DownstreamCacheSendHeadersValue = "CACHE_CONTROL"
+	// DownstreamCacheSendHeadersExpires DownstreamCache.SendHeaders value EXPIRES
+	DownstreamCacheSendHeadersExpires DownstreamCacheSendHeadersValue = "EXPIRES"
+	// DownstreamCacheSendHeadersPassOrigin DownstreamCache.SendHeaders value PASS_ORIGIN
+	DownstreamCacheSendHeadersPassOrigin DownstreamCacheSendHeadersValue = "PASS_ORIGIN"
+)
+
+// GzipResponse is the gzipResponse behavior
+type GzipResponse struct {
+	Behavior GzipResponseBehaviorValue `json:"behavior,omitempty"`
+}
+
+// Name returns the behavior name, gzipResponse
+func (behavior *GzipResponse) Name() string {
+	return "gzipResponse"
+}
+
+// ToBehavior converts the behavior to a *papi.Behavior
+func (behavior *GzipResponse) ToBehavior() *papi.Behavior {
+	return newBehavior("gzipResponse", behavior)
+}
+
+// FromBehavior sets the options from a *papi.Behavior named gzipResponse
+func (behavior *GzipResponse) FromBehavior(from *papi.Behavior) error {
+	return fromBehavior(from, "gzipResponse", behavior)
+}
+
+// GzipResponseBehaviorValue is used to create an "enum" of possible GzipResponse.Behavior values
+type GzipResponseBehaviorValue string
+
+const (
+	// GzipResponseBehaviorOriginResponse GzipResponse.Behavior value ORIGIN_RESPONSE
+	GzipResponseBehaviorOriginResponse GzipResponseBehaviorValue = "ORIGIN_RESPONSE"
+	// GzipResponseBehaviorAlways GzipResponse.Behavior value ALWAYS
+	GzipResponseBehaviorAlways GzipResponseBehaviorValue = "ALWAYS"
+	// GzipResponseBehaviorNever GzipResponse.Behavior value NEVER
+	GzipResponseBehaviorNever GzipResponseBehaviorValue = "NEVER"
+)
+
+// ModifyOutgoingResponseHeader is the modifyOutgoingResponseHeader behavior
+type ModifyOutgoingResponseHeader struct {
+	Action                   ModifyOutgoingResponseHeaderActionValue                   `json:"action,omitempty"`
+	AvoidDuplicateHeaders    *bool                                                     `json:"avoidDuplicateHeaders,omitempty"`
+	CustomHeaderName         string                                                    `json:"customHeaderName,omitempty"`
+	HeaderValue              string                                                    `json:"headerValue,omitempty"`
+	NewHeaderValue           string                                                    `json:"newHeaderValue,omitempty"`
+	StandardAddHeaderName    ModifyOutgoingResponseHeaderStandardAddHeaderNameValue    `json:"standardAddHeaderName,omitempty"`
+	StandardDeleteHeaderName ModifyOutgoingResponseHeaderStandardDeleteHeaderNameValue `json:"standardDeleteHeaderName,omitempty"`
+}
+
+// Name returns the behavior name, modifyOutgoingResponseHeader
+func (behavior *ModifyOutgoingResponseHeader) Name() string {
+	return "modifyOutgoingResponseHeader"
+}
+
+// ToBehavior converts the behavior to a *papi.Behavior
+func (behavior *ModifyOutgoingResponseHeader) ToBehavior() *papi.Behavior {
+	return newBehavior("modifyOutgoingResponseHeader", behavior)
+}
+
+// FromBehavior sets the options from a *papi.Behavior named modifyOutgoingResponseHeader
+func (behavior *ModifyOutgoingResponseHeader) FromBehavior(from *papi.Behavior) error {
+	return fromBehavior(from, "modifyOutgoingResponseHeader", behavior)
+}
+
+// ModifyOutgoingResponseHeaderActionValue is used to create an "enum" of possible ModifyOutgoingResponseHeader.Action values
+type ModifyOutgoingResponseHeaderActionValue string
+
+const (
+	// ModifyOutgoingResponseHeaderActionAdd ModifyOutgoingResponseHeader.Action value ADD
+	ModifyOutgoingResponseHeaderActionAdd ModifyOutgoingResponseHeaderActionValue = "ADD"
+	// ModifyOutgoingResponseHeaderActionDelete ModifyOutgoingResponseHeader.Action value DELETE
+	ModifyOutgoingResponseHeaderActionDelete ModifyOutgoingResponseHeaderActionValue = "DELETE"
+	// ModifyOutgoingResponseHeaderActionModify ModifyOutgoingResponseHeader.Action value MODIFY
+	ModifyOutgoingResponseHeaderActionModify ModifyOutgoingResponseHeaderActionValue = "MODIFY"
+	// ModifyOutgoingResponseHeaderActionRegex ModifyOutgoingResponseHeader.Action value REGEX
+	ModifyOutgoingResponseHeaderActionRegex ModifyOutgoingResponseHeaderActionValue = "REGEX"
+)
+
+// ModifyOutgoingResponseHeaderStandardAddHeaderNameValue is used to create an "enum" of possible ModifyOutgoingResponseHeader.StandardAddHeaderName values
+type ModifyOutgoingResponseHeaderStandardAddHeaderNameValue string
+
+const (
+	// ModifyOutgoingResponseHeaderStandardAddHeaderNameCacheControl ModifyOutgoingResponseHeader.StandardAddHeaderName value CACHE_CONTROL
+	ModifyOutgoingResponseHeaderStandardAddHeaderNameCacheControl ModifyOutgoingResponseHeaderStandardAddHeaderNameValue = "CACHE_CONTROL"
+	// ModifyOutgoingResponseHeaderStandardAddHeaderNameContentType ModifyOutgoingResponseHeader.StandardAddHeaderName value CONTENT_TYPE
+	ModifyOutgoingResponseHeaderStandardAddHeaderNameContentType ModifyOutgoingResponseHeaderStandardAddHeaderNameValue = "CONTENT_TYPE"
+	// ModifyOutgoingResponseHeaderStandardAddHeaderNameEdgeControl ModifyOutgoingResponseHeader.StandardAddHeaderName value EDGE_CONTROL
+	ModifyOutgoingResponseHeaderStandardAddHeaderNameEdgeControl ModifyOutgoingResponseHeaderStandardAddHeaderNameValue = "EDGE_CONTROL"
+	// ModifyOutgoingResponseHeaderStandardAddHeaderNameExpires ModifyOutgoingResponseHeader.StandardAddHeaderName value EXPIRES
+	ModifyOutgoingResponseHeaderStandardAddHeaderNameExpires ModifyOutgoingResponseHeaderStandardAddHeaderNameValue = "EXPIRES"
+	// ModifyOutgoingResponseHeaderStandardAddHeaderNameLastModified ModifyOutgoingResponseHeader.StandardAddHeaderName value LAST_MODIFIED
+	ModifyOutgoingResponseHeaderStandardAddHeaderNameLastModified ModifyOutgoingResponseHeaderStandardAddHeaderNameValue = "LAST_MODIFIED"
+	// ModifyOutgoingResponseHeaderStandardAddHeaderNameOther ModifyOutgoingResponseHeader.StandardAddHeaderName value OTHER
+	ModifyOutgoingResponseHeaderStandardAddHeaderNameOther ModifyOutgoingResponseHeaderStandardAddHeaderNameValue = "OTHER"
+)
+
+// ModifyOutgoingResponseHeaderStandardDeleteHeaderNameValue is used to create an "enum" of possible ModifyOutgoingResponseHeader.StandardDeleteHeaderName values
+type ModifyOutgoingResponseHeaderStandardDeleteHeaderNameValue string
+
+const (
+	// ModifyOutgoingResponseHeaderStandardDeleteHeaderNameCacheControl ModifyOutgoingResponseHeader.StandardDeleteHeaderName value CACHE_CONTROL
+	ModifyOutgoingResponseHeaderStandardDeleteHeaderNameCacheControl ModifyOutgoingResponseHeaderStandardDeleteHeaderNameValue = "CACHE_CONTROL"
+	// ModifyOutgoingResponseHeaderStandardDeleteHeaderNameContentType ModifyOutgoingResponseHeader.StandardDeleteHeaderName value CONTENT_TYPE
+	ModifyOutgoingResponseHeaderStandardDeleteHeaderNameContentType ModifyOutgoingResponseHeaderStandardDeleteHeaderNameValue = "CONTENT_TYPE"
+	// ModifyOutgoingResponseHeaderStandardDeleteHeaderNameVary ModifyOutgoingResponseHeader.StandardDeleteHeaderName value VARY
+	ModifyOutgoingResponseHeaderStandardDeleteHeaderNameVary ModifyOutgoingResponseHeaderStandardDeleteHeaderNameValue = "VARY"
+	// ModifyOutgoingResponseHeaderStandardDeleteHeaderNameEdgeControl ModifyOutgoingResponseHeader.StandardDeleteHeaderName value EDGE_CONTROL
+	ModifyOutgoingResponseHeaderStandardDeleteHeaderNameEdgeControl ModifyOutgoingResponseHeaderStandardDeleteHeaderNameValue = "EDGE_CONTROL"
+	// ModifyOutgoingResponseHeaderStandardDeleteHeaderNameExpires ModifyOutgoingResponseHeader.StandardDeleteHeaderName value EXPIRES
+	ModifyOutgoingResponseHeaderStandardDeleteHeaderNameExpires ModifyOutgoingResponseHeaderStandardDeleteHeaderNameValue = "EXPIRES"
+	// ModifyOutgoingResponseHeaderStandardDeleteHeaderNameLastModified ModifyOutgoingResponseHeader.StandardDeleteHeaderName value LAST_MODIFIED
+	ModifyOutgoingResponseHeaderStandardDeleteHeaderNameLastModified ModifyOutgoingResponseHeaderStandardDeleteHeaderNameValue = "LAST_MODIFIED"
+	// ModifyOutgoingResponseHeaderStandardDeleteHeaderNameOther ModifyOutgoingResponseHeader.StandardDeleteHeaderName value OTHER
+	ModifyOutgoingResponseHeaderStandardDeleteHeaderNameOther ModifyOutgoingResponseHeaderStandardDeleteHeaderNameValue = "OTHER"
+)
+
+// Origin is the origin behavior
+type Origin struct {
+	CacheKeyHostname               OriginCacheKeyHostnameValue   `json:"cacheKeyHostname,omitempty"`
+	Compress                       *bool                         `json:"compress,omitempty"`
+	CustomForwardHostHeader        string                        `json:"customForwardHostHeader,omitempty"`
+	CustomValidCNValues            []string                      `json:"customValidCnValues,omitempty"`
+	EnableTrueClientIP             *bool                         `json:"enableTrueClientIp,omitempty"`
+	ForwardHostHeader              OriginForwardHostHeaderValue  `json:"forwardHostHeader,omitempty"`
+	Hostname                       string                        `json:"hostname,omitempty"`
+	HTTPPort                       *int                          `json:"httpPort,omitempty"`
+	HTTPSPort                      *int                          `json:"httpsPort,omitempty"`
+	IPVersion                      OriginIPVersionValue          `json:"ipVersion,omitempty"`
+	NetStorage                     *OriginNetStorage             `json:"netStorage,omitempty"`
+	OriginCertsToHonor             OriginOriginCertsToHonorValue `json:"originCertsToHonor,omitempty"`
+	OriginSNI                      *bool                         `json:"originSni,omitempty"`
+	OriginType                     OriginOriginTypeValue         `json:"originType,omitempty"`
+	StandardCertificateAuthorities []string                      `json:"standardCertificateAuthorities,omitempty"`
+	TrueClientIPClientSetting      *bool                         `json:"trueClientIpClientSetting,omitempty"`
+	TrueClientIPHeader             string                        `json:"trueClientIpHeader,omitempty"`
+	VerificationMode               OriginVerificationModeValue   `json:"verificationMode,omitempty"`
+}
+
+// Name returns the behavior name, origin
+func (behavior *Origin) Name() string {
+	return "origin"
+}
+
+// ToBehavior converts the behavior to a *papi.Behavior
+func (behavior *Origin) ToBehavior() *papi.Behavior {
+	return newBehavior("origin", behavior)
+}
+
+// FromBehavior sets the options from a *papi.Behavior named origin
+func (behavior *Origin) FromBehavior(from *papi.Behavior) error {
+	return fromBehavior(from, "origin", behavior)
+}
+
+// OriginCacheKeyHostnameValue is used to create an "enum" of possible Origin.CacheKeyHostname values
+type OriginCacheKeyHostnameValue string
+
+const (
+	// OriginCacheKeyHostnameRequestHostHeader Origin.CacheKeyHostname value REQUEST_HOST_HEADER
+	OriginCacheKeyHostnameRequestHostHeader OriginCacheKeyHostnameValue = "REQUEST_HOST_HEADER"
+	// OriginCacheKeyHostnameOriginHostname Origin.CacheKeyHostname value ORIGIN_HOSTNAME
+	OriginCacheKeyHostnameOriginHostname OriginCacheKeyHostnameValue = "ORIGIN_HOSTNAME"
+)
+
+// OriginForwardHostHeaderValue is used to create an "enum" of possible Origin.ForwardHostHeader values
+type OriginForwardHostHeaderValue string
+
+const (
+	// OriginForwardHostHeaderRequestHostHeader Origin.ForwardHostHeader value REQUEST_HOST_HEADER
+	OriginForwardHostHeaderRequestHostHeader OriginForwardHostHeaderValue = "REQUEST_HOST_HEADER"
+	// OriginForwardHostHeaderOriginHostname Origin.ForwardHostHeader value ORIGIN_HOSTNAME
+	OriginForwardHostHeaderOriginHostname OriginForwardHostHeaderValue = "ORIGIN_HOSTNAME"
+	// OriginForwardHostHeaderCustom Origin.ForwardHostHeader value CUSTOM
+	OriginForwardHostHeaderCustom OriginForwardHostHeaderValue = "CUSTOM"
+)
+
+// OriginIPVersionValue is used to create an "enum" of possible Origin.IPVersion values
+type OriginIPVersionValue string
+
+const (
+	// OriginIPVersionIpv4 Origin.IPVersion value IPV4
+	OriginIPVersionIpv4 OriginIPVersionValue = "IPV4"
+	// OriginIPVersionDualstack Origin.IPVersion value DUALSTACK
+	OriginIPVersionDualstack OriginIPVersionValue = "DUALSTACK"
+	// OriginIPVersionIpv6 Origin.IPVersion value IPV6
+	OriginIPVersionIpv6 OriginIPVersionValue = "IPV6"
+)
+
+// OriginNetStorage is the value of Origin.NetStorage
+type OriginNetStorage struct {
+	CpCodeList         []int  `json:"cpCodeList,omitempty"`
+	DownloadDomainName string `json:"downloadDomainName,omitempty"`
+	ID                 *int   `json:"id,omitempty"`
+	Name               string `json:"name,omitempty"`
+}
+
+// OriginOriginCertsToHonorValue is used to create an "enum" of possible Origin.OriginCertsToHonor values
+type OriginOriginCertsToHonorValue string
+
+const (
+	// OriginOriginCertsToHonorCombo Origin.OriginCertsToHonor value COMBO
+	OriginOriginCertsToHonorCombo OriginOriginCertsToHonorValue = "COMBO"
+	// OriginOriginCertsToHonorStandardCertificateAuthorities Origin.OriginCertsToHonor value STANDARD_CERTIFICATE_AUTHORITIES
+	OriginOriginCertsToHonorStandardCertificateAuthorities OriginOriginCertsToHonorValue = "STANDARD_CERTIFICATE_AUTHORITIES"
+	// OriginOriginCertsToHonorCustomCertificateAuthorities Origin.OriginCertsToHonor value CUSTOM_CERTIFICATE_AUTHORITIES
+	OriginOriginCertsToHonorCustomCertificateAuthorities OriginOriginCertsToHonorValue = "CUSTOM_CERTIFICATE_AUTHORITIES"
+	// OriginOriginCertsToHonorCustomCertificates Origin.OriginCertsToHonor value CUSTOM_CERTIFICATES
+	OriginOriginCertsToHonorCustomCertificates OriginOriginCertsToHonorValue = "CUSTOM_CERTIFICATES"
+)
+
+// OriginOriginTypeValue is used to create an "enum" of possible Origin.OriginType values
+type OriginOriginTypeValue string
+
+const (
+	// OriginOriginTypeCustomer Origin.OriginType value CUSTOMER
+	OriginOriginTypeCustomer OriginOriginTypeValue = "CUSTOMER"
+	// OriginOriginTypeNetStorage Origin.OriginType value NET_STORAGE
+	OriginOriginTypeNetStorage OriginOriginTypeValue = "NET_STORAGE"
+	// OriginOriginTypeMediaServiceLive Origin.OriginType value MEDIA_SERVICE_LIVE
+	OriginOriginTypeMediaServiceLive OriginOriginTypeValue = "MEDIA_SERVICE_LIVE"
+	// OriginOriginTypeEdgeLoadBalancingOriginGroup Origin.OriginType value EDGE_LOAD_BALANCING_ORIGIN_GROUP
+	OriginOriginTypeEdgeLoadBalancingOriginGroup OriginOriginTypeValue = "EDGE_LOAD_BALANCING_ORIGIN_GROUP"
+	// OriginOriginTypeSaasDynamicOrigin Origin.OriginType value SAAS_DYNAMIC_ORIGIN
+	OriginOriginTypeSaasDynamicOrigin OriginOriginTypeValue = "SAAS_DYNAMIC_ORIGIN"
+)
+
+// OriginVerificationModeValue is used to create an "enum" of possible Origin.VerificationMode values
+type OriginVerificationModeValue string
+
+const (
+	// OriginVerificationModePlatformSettings Origin.VerificationMode value PLATFORM_SETTINGS
+	OriginVerificationModePlatformSettings OriginVerificationModeValue = "PLATFORM_SETTINGS"
+	// OriginVerificationModeThirdParty Origin.VerificationMode value THIRD_PARTY
+	OriginVerificationModeThirdParty OriginVerificationModeValue = "THIRD_PARTY"
+	// OriginVerificationModeCustom Origin.VerificationMode value CUSTOM
+	OriginVerificationModeCustom OriginVerificationModeValue = "CUSTOM"
+)
+
+// PrefreshCache is the prefreshCache behavior
+type PrefreshCache struct {
+	Enabled     *bool `json:"enabled,omitempty"`
+	Prefreshval *int  `json:"prefreshval,omitempty"`
+}
+
+// Name returns the behavior name, prefreshCache
+func (behavior *PrefreshCache) Name() string {
+	return "prefreshCache"
+}
+
+// ToBehavior converts the behavior to a *papi.Behavior
+func (behavior *PrefreshCache) ToBehavior() *papi.Behavior {
+	return newBehavior("prefreshCache", behavior)
+}
+
+// FromBehavior sets the options from a *papi.Behavior named prefreshCache
+func (behavior *PrefreshCache) FromBehavior(from *papi.Behavior) error {
+	return fromBehavior(from, "prefreshCache", behavior)
+}
+
+// SetVariable is the setVariable behavior
+type SetVariable struct {
+	Transform     SetVariableTransformValue   `json:"transform,omitempty"`
+	ValueSource   SetVariableValueSourceValue `json:"valueSource,omitempty"`
+	VariableName  string                      `json:"variableName,omitempty"`
+	VariableValue string                      `json:"variableValue,omitempty"`
+}
+
+// Name returns the behavior name, setVariable
+func (behavior *SetVariable) Name() string {
+	return "setVariable"
+}
+
+// ToBehavior converts the behavior to a *papi.Behavior
+func (behavior *SetVariable) ToBehavior() *papi.Behavior {
+	return newBehavior("setVariable", behavior)
+}
+
+// FromBehavior sets the options from a *papi.Behavior named setVariable
+func (behavior *SetVariable) FromBehavior(from *papi.Behavior) error {
+	return fromBehavior(from, "setVariable", behavior)
+}
+
+// SetVariableTransformValue is used to create an "enum" of possible SetVariable.Transform values
+type SetVariableTransformValue string
+
+const (
+	// SetVariableTransformNone SetVariable.Transform value NONE
+	SetVariableTransformNone SetVariableTransformValue = "NONE"
+	// SetVariableTransformAdd SetVariable.Transform value ADD
+	SetVariableTransformAdd SetVariableTransformValue = "ADD"
+	// SetVariableTransformBase64Decode SetVariable.Transform value BASE_64_DECODE
+	SetVariableTransformBase64Decode SetVariableTransformValue = "BASE_64_DECODE"
+	// SetVariableTransformBase64Encode SetVariable.Transform value BASE_64_ENCODE
+	SetVariableTransformBase64Encode SetVariableTransformValue = "BASE_64_ENCODE"
+	// SetVariableTransformLower SetVariable.Transform value LOWER
+	SetVariableTransformLower SetVariableTransformValue = "LOWER"
+	// SetVariableTransformUpper SetVariable.Transform value UPPER
+	SetVariableTransformUpper SetVariableTransformValue = "UPPER"
+	// SetVariableTransformURLDecode SetVariable.Transform value URL_DECODE
+	SetVariableTransformURLDecode SetVariableTransformValue = "URL_DECODE"
+	// SetVariableTransformURLEncode SetVariable.Transform value URL_ENCODE
+	SetVariableTransformURLEncode SetVariableTransformValue = "URL_ENCODE"
+)
+
+// SetVariableValueSourceValue is used to create an "enum" of possible SetVariable.ValueSource values
+type SetVariableValueSourceValue string
+
+const (
+	// SetVariableValueSourceExpression SetVariable.ValueSource value EXPRESSION
+	SetVariableValueSourceExpression SetVariableValueSourceValue = "EXPRESSION"
+	// SetVariableValueSourceExtract SetVariable.ValueSource value EXTRACT
+	SetVariableValueSourceExtract SetVariableValueSourceValue = "EXTRACT"
+	// SetVariableValueSourceGenerate SetVariable.ValueSource value GENERATE
+	SetVariableValueSourceGenerate SetVariableValueSourceValue = "GENERATE"
+)
+
+var builders = map[string]func() Behavior{
+	"allowPost":                    func() Behavior { return &AllowPost{} },
+	"caching":                      func() Behavior { return &Caching{} },
+	"cpCode":                       func() Behavior { return &CpCode{} },
+	"downstreamCache":              func() Behavior { return &DownstreamCache{} },
+	"gzipResponse":                 func() Behavior { return &GzipResponse{} },
+	"modifyOutgoingResponseHeader": func() Behavior { return &ModifyOutgoingResponseHeader{} },
+	"origin":                       func() Behavior { return &Origin{} },
+	"prefreshCache":                func() Behavior { return &PrefreshCache{} },
+	"setVariable":                  func() Behavior { return &SetVariable{} },
+}
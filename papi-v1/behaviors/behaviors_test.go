@@ -0,0 +1,72 @@
+package behaviors
+
+import (
+	"testing"
+
+	papi "github.com/akamai/AkamaiOPEN-edgegrid-golang/papi-v1"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestOrigin_ToBehavior(t *testing.T) {
+	origin := &Origin{
+		OriginType:        OriginOriginTypeCustomer,
+		Hostname:          "origin.example.com",
+		ForwardHostHeader: OriginForwardHostHeaderRequestHostHeader,
+		OriginSNI:         Bool(false),
+		HTTPPort:          Int(80),
+	}
+
+	behavior := origin.ToBehavior()
+
+	assert.Equal(t, "origin", behavior.Name)
+	assert.Equal(t, papi.OptionValue{
+		"originType":        "CUSTOMER",
+		"hostname":          "origin.example.com",
+		"forwardHostHeader": "REQUEST_HOST_HEADER",
+		"originSni":         false,
+		"httpPort":          float64(80),
+	}, behavior.Options)
+}
+
+func TestFromBehavior(t *testing.T) {
+	behavior := papi.NewBehavior()
+	behavior.Name = "cpCode"
+	behavior.Options = papi.OptionValue{
+		"value": map[string]interface{}{
+			"id":       float64(12345),
+			"name":     "www.example.com",
+			"products": []interface{}{"SPM"},
+		},
+	}
+
+	typed, err := FromBehavior(behavior)
+	assert.NoError(t, err)
+
+	cpCode, ok := typed.(*CpCode)
+	assert.True(t, ok)
+	assert.Equal(t, 12345, *cpCode.Value.ID)
+	assert.Equal(t, "www.example.com", cpCode.Value.Name)
+	assert.Equal(t, []string{"SPM"}, cpCode.Value.Products)
+	assert.Equal(t, behavior.Options, cpCode.ToBehavior().Options)
+}
+
+func TestFromBehavior_Errors(t *testing.T) {
+	behavior := papi.NewBehavior()
+	behavior.Name = "notABehavior"
+
+	_, err := FromBehavior(behavior)
+	assert.EqualError(t, err, "behavior notABehavior is not generated for rule format v2021-09-22")
+
+	behavior.Name = "caching"
+	assert.EqualError(t, (&Origin{}).FromBehavior(behavior), "cannot convert behavior caching to origin")
+
+	behavior.Options = papi.OptionValue{"mustRevalidate": "yes"}
+	_, err = FromBehavior(behavior)
+	assert.Error(t, err)
+}
+
+func TestNewBehavior_Panics(t *testing.T) {
+	assert.PanicsWithValue(t, "behavior broken: json: unsupported type: chan int", func() {
+		newBehavior("broken", struct{ C chan int }{})
+	})
+}
@@ -0,0 +1,449 @@
+package papi
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/akamai/AkamaiOPEN-edgegrid-golang/client-v1"
+	edge "github.com/akamai/AkamaiOPEN-edgegrid-golang/edgegrid"
+	"github.com/akamai/AkamaiOPEN-edgegrid-golang/jsonpatch-v1"
+)
+
+// BulkStatusValue is used to create an "enum" of possible bulk job and bulk
+// item statuses
+type BulkStatusValue string
+
+// BulkSearchSyntaxValue is used to create an "enum" of possible
+// BulkSearchQuery.Syntax values
+type BulkSearchSyntaxValue string
+
+const (
+	// BulkStatusSubmitted bulk job or item status value SUBMITTED
+	BulkStatusSubmitted BulkStatusValue = "SUBMITTED"
+	// BulkStatusInProgress bulk job or item status value IN_PROGRESS
+	BulkStatusInProgress BulkStatusValue = "IN_PROGRESS"
+	// BulkStatusPending bulk item status value PENDING
+	BulkStatusPending BulkStatusValue = "PENDING"
+	// BulkStatusComplete bulk job or item status value COMPLETE
+	BulkStatusComplete BulkStatusValue = "COMPLETE"
+	// BulkStatusUpdated BulkPatch.Status value UPDATED
+	BulkStatusUpdated BulkStatusValue = "UPDATED"
+	// BulkStatusFailed bulk job or item status value FAILED
+	BulkStatusFailed BulkStatusValue = "FAILED"
+
+	// BulkSearchSyntaxJSONPath BulkSearchQuery.Syntax value JSONPATH
+	BulkSearchSyntaxJSONPath BulkSearchSyntaxValue = "JSONPATH"
+)
+
+// BulkPollInterval is how often bulk jobs are polled, unless PAPI suggests a
+// longer interval with Retry-After
+var BulkPollInterval = 5 * time.Second
+
+// IsDone reports whether a bulk job with this status has finished
+func (status BulkStatusValue) IsDone() bool {
+	return status == BulkStatusComplete || status == BulkStatusFailed
+}
+
+// BulkSearchQuery is a rule tree search across all properties
+type BulkSearchQuery struct {
+	Syntax BulkSearchSyntaxValue `json:"syntax"`
+	// Match is a JSONPath expression evaluated against each rule tree
+	Match string `json:"match"`
+	// BulkSearchQualifiers are JSONPath expressions that must also match for
+	// a rule tree to be included
+	BulkSearchQualifiers []string `json:"bulkSearchQualifiers,omitempty"`
+}
+
+// BulkSearchJob is a bulk rule tree search job
+type BulkSearchJob struct {
+	client.Resource
+	BulkSearchID       int                 `json:"bulkSearchId"`
+	SearchTargetStatus BulkStatusValue     `json:"searchTargetStatus"`
+	SearchSubmitDate   time.Time           `json:"searchSubmitDate"`
+	SearchUpdateDate   time.Time           `json:"searchUpdateDate"`
+	BulkSearchQuery    BulkSearchQuery     `json:"bulkSearchQuery"`
+	Results            []*BulkSearchResult `json:"results"`
+}
+
+// BulkSearchResult is a property version matched by a BulkSearchJob
+type BulkSearchResult struct {
+	client.Resource
+	PropertyID       string      `json:"propertyId"`
+	PropertyName     string      `json:"propertyName"`
+	PropertyVersion  int         `json:"propertyVersion"`
+	PropertyType     string      `json:"propertyType"`
+	IsLatest         bool        `json:"isLatest"`
+	IsLocked         bool        `json:"isLocked"`
+	IsSecure         bool        `json:"isSecure"`
+	AccountID        string      `json:"accountId"`
+	LastModifiedTime time.Time   `json:"lastModifiedTime"`
+	ProductionStatus StatusValue `json:"productionStatus"`
+	StagingStatus    StatusValue `json:"stagingStatus"`
+	// MatchLocations are JSON pointers to the matches within the rule tree
+	MatchLocations []string `json:"matchLocations"`
+}
+
+// BulkVersion is a property version to create from another version
+type BulkVersion struct {
+	client.Resource
+	PropertyID            string          `json:"propertyId"`
+	CreateFromVersion     int             `json:"createFromVersion"`
+	CreateFromVersionEtag string          `json:"createFromVersionEtag,omitempty"`
+	PropertyVersion       int             `json:"propertyVersion,omitempty"`
+	PropertyName          string          `json:"propertyName,omitempty"`
+	Status                BulkStatusValue `json:"status,omitempty"`
+	FailureCause          string          `json:"failureCause,omitempty"`
+}
+
+// BulkVersions is a bulk version creation job
+type BulkVersions struct {
+	client.Resource
+	BulkCreateVersionsID     int             `json:"bulkCreateVersionsId,omitempty"`
+	BulkCreateVersionsStatus BulkStatusValue `json:"bulkCreateVersionsStatus,omitempty"`
+	CreatePropertyVersions   []*BulkVersion  `json:"createPropertyVersions"`
+}
+
+// BulkPatch is a JSON Patch to apply to a property version's rule tree
+type BulkPatch struct {
+	client.Resource
+	PropertyID      string          `json:"propertyId"`
+	PropertyVersion int             `json:"propertyVersion"`
+	Etag            string          `json:"etag,omitempty"`
+	Patches         jsonpatch.Patch `json:"patches"`
+	PropertyName    string          `json:"propertyName,omitempty"`
+	Status          BulkStatusValue `json:"status,omitempty"`
+	FailureCause    string          `json:"failureCause,omitempty"`
+}
+
+// BulkPatches is a bulk rule tree patch job
+type BulkPatches struct {
+	client.Resource
+	BulkPatchID           int             `json:"bulkPatchId,omitempty"`
+	BulkPatchStatus       BulkStatusValue `json:"bulkPatchStatus,omitempty"`
+	PatchPropertyVersions []*BulkPatch    `json:"patchPropertyVersions"`
+}
+
+// BulkActivationSettings are the settings used for each BulkActivation that
+// does not set its own
+type BulkActivationSettings struct {
+	AcknowledgeAllWarnings bool     `json:"acknowledgeAllWarnings"`
+	UseFastFallback        bool     `json:"useFastFallback"`
+	FastPush               bool     `json:"fastPush"`
+	NotifyEmails           []string `json:"notifyEmails,omitempty"`
+}
+
+// BulkActivation is a property version to activate
+type BulkActivation struct {
+	client.Resource
+	PropertyID             string          `json:"propertyId"`
+	PropertyVersion        int             `json:"propertyVersion"`
+	Network                NetworkValue    `json:"network"`
+	Note                   string          `json:"note,omitempty"`
+	NotifyEmails           []string        `json:"notifyEmails,omitempty"`
+	AcknowledgeAllWarnings bool            `json:"acknowledgeAllWarnings,omitempty"`
+	PropertyName           string          `json:"propertyName,omitempty"`
+	ActivationID           string          `json:"activationId,omitempty"`
+	ActivationStatus       StatusValue     `json:"activationStatus,omitempty"`
+	TaskStatus             BulkStatusValue `json:"taskStatus,omitempty"`
+	FatalError             string          `json:"fatalError,omitempty"`
+}
+
+// BulkActivations is a bulk activation job
+type BulkActivations struct {
+	client.Resource
+	BulkActivationID          int                     `json:"bulkActivationId,omitempty"`
+	BulkActivationStatus      BulkStatusValue         `json:"bulkActivationStatus,omitempty"`
+	DefaultActivationSettings *BulkActivationSettings `json:"defaultActivationSettings,omitempty"`
+	ActivatePropertyVersions  []*BulkActivation       `json:"activatePropertyVersions"`
+}
+
+// BulkSearch searches the rule trees of all properties
+//
+// The search job is polled until it completes or ctx is done.
+//
+// API Docs: https://developer.akamai.com/api/luna/papi/resources.html#postbulksearch
+// Endpoint: POST /papi/v1/bulk/rules-search-requests
+func BulkSearch(ctx context.Context, query BulkSearchQuery, correlationid string) (*BulkSearchJob, error) {
+	if query.Syntax == "" {
+		query.Syntax = BulkSearchSyntaxJSONPath
+	}
+
+	search := &BulkSearchJob{}
+	search.Init()
+
+	err := runBulkJob(
+		ctx,
+		"/papi/v1/bulk/rules-search-requests",
+		map[string]interface{}{"bulkSearchQuery": query},
+		"bulkSearchLink",
+		search,
+		func() BulkStatusValue { return search.SearchTargetStatus },
+		correlationid,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	return search, nil
+}
+
+// Versions returns the matched property versions to create new versions from
+//
+// See: BulkCreateVersions()
+func (search *BulkSearchJob) Versions() []*BulkVersion {
+	var versions []*BulkVersion
+	for _, result := range search.Results {
+		version := &BulkVersion{
+			PropertyID:        result.PropertyID,
+			CreateFromVersion: result.PropertyVersion,
+		}
+		version.Init()
+		versions = append(versions, version)
+	}
+
+	return versions
+}
+
+// BulkCreateVersions creates a new version of each property
+//
+// The job is polled until it completes or ctx is done. The per-property
+// outcome is in BulkVersion.Status.
+//
+// API Docs: https://developer.akamai.com/api/luna/papi/resources.html#postbulkversioning
+// Endpoint: POST /papi/v1/bulk/property-version-creations
+func BulkCreateVersions(ctx context.Context, versions []*BulkVersion, correlationid string) (*BulkVersions, error) {
+	job := &BulkVersions{}
+	job.Init()
+
+	err := runBulkJob(
+		ctx,
+		"/papi/v1/bulk/property-version-creations",
+		map[string]interface{}{"createPropertyVersions": versions},
+		"bulkCreateVersionLink",
+		job,
+		func() BulkStatusValue { return job.BulkCreateVersionsStatus },
+		correlationid,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	return job, nil
+}
+
+// Failed returns the versions that could not be created
+func (job *BulkVersions) Failed() []*BulkVersion {
+	var failed []*BulkVersion
+	for _, version := range job.CreatePropertyVersions {
+		if version.Status == BulkStatusFailed {
+			failed = append(failed, version)
+		}
+	}
+
+	return failed
+}
+
+// Patches returns a BulkPatch applying patch to each created version
+//
+// See: BulkPatchRules()
+func (job *BulkVersions) Patches(patch jsonpatch.Patch) []*BulkPatch {
+	var patches []*BulkPatch
+	for _, version := range job.CreatePropertyVersions {
+		if version.Status == BulkStatusFailed || version.PropertyVersion == 0 {
+			continue
+		}
+
+		bulkPatch := &BulkPatch{
+			PropertyID:      version.PropertyID,
+			PropertyVersion: version.PropertyVersion,
+			Patches:         patch,
+		}
+		bulkPatch.Init()
+		patches = append(patches, bulkPatch)
+	}
+
+	return patches
+}
+
+// BulkPatchRules applies a JSON Patch to the rule tree of each property
+// version
+//
+// The job is polled until it completes or ctx is done. The per-property
+// outcome is in BulkPatch.Status.
+//
+// API Docs: https://developer.akamai.com/api/luna/papi/resources.html#postbulkpatch
+// Endpoint: POST /papi/v1/bulk/rules-patch-requests
+func BulkPatchRules(ctx context.Context, patches []*BulkPatch, correlationid string) (*BulkPatches, error) {
+	job := &BulkPatches{}
+	job.Init()
+
+	err := runBulkJob(
+		ctx,
+		"/papi/v1/bulk/rules-patch-requests",
+		map[string]interface{}{"patchPropertyVersions": patches},
+		"bulkPatchLink",
+		job,
+		func() BulkStatusValue { return job.BulkPatchStatus },
+		correlationid,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	return job, nil
+}
+
+// Failed returns the patches that could not be applied
+func (job *BulkPatches) Failed() []*BulkPatch {
+	var failed []*BulkPatch
+	for _, patch := range job.PatchPropertyVersions {
+		if patch.Status == BulkStatusFailed {
+			failed = append(failed, patch)
+		}
+	}
+
+	return failed
+}
+
+// Activations returns a BulkActivation on network for each patched version
+//
+// See: BulkActivate()
+func (job *BulkPatches) Activations(network NetworkValue) []*BulkActivation {
+	var activations []*BulkActivation
+	for _, patch := range job.PatchPropertyVersions {
+		if patch.Status == BulkStatusFailed {
+			continue
+		}
+
+		activation := &BulkActivation{
+			PropertyID:      patch.PropertyID,
+			PropertyVersion: patch.PropertyVersion,
+			Network:         network,
+		}
+		activation.Init()
+		activations = append(activations, activation)
+	}
+
+	return activations
+}
+
+// BulkActivate activates each property version
+//
+// The job is polled until every activation has been submitted or ctx is
+// done; use Activation.Wait to wait for the activations themselves. The
+// per-property outcome is in BulkActivation.TaskStatus.
+//
+// API Docs: https://developer.akamai.com/api/luna/papi/resources.html#postbulkactivations
+// Endpoint: POST /papi/v1/bulk/activations
+func BulkActivate(ctx context.Context, activations []*BulkActivation, settings *BulkActivationSettings, correlationid string) (*BulkActivations, error) {
+	job := &BulkActivations{}
+	job.Init()
+
+	body := map[string]interface{}{"activatePropertyVersions": activations}
+	if settings != nil {
+		body["defaultActivationSettings"] = settings
+	}
+
+	err := runBulkJob(
+		ctx,
+		"/papi/v1/bulk/activations",
+		body,
+		"bulkActivationLink",
+		job,
+		func() BulkStatusValue { return job.BulkActivationStatus },
+		correlationid,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	return job, nil
+}
+
+// Failed returns the activations that could not be submitted
+func (job *BulkActivations) Failed() []*BulkActivation {
+	var failed []*BulkActivation
+	for _, activation := range job.ActivatePropertyVersions {
+		if activation.TaskStatus == BulkStatusFailed {
+			failed = append(failed, activation)
+		}
+	}
+
+	return failed
+}
+
+// runBulkJob submits a bulk job and polls the link returned in linkField
+// into result until status reports it is done
+//
+// Results are decoded into a new value rather than the submitted items, so
+// the caller's items are left as they were.
+func runBulkJob(ctx context.Context, path string, body interface{}, linkField string, result interface{}, status func() BulkStatusValue, correlationid string) error {
+	req, err := client.NewJSONRequest(Config, "POST", path, body)
+	if err != nil {
+		return err
+	}
+
+	req = req.WithContext(ctx)
+
+	edge.PrintHttpRequestCorrelation(req, true, correlationid)
+
+	res, err := client.Do(Config, req)
+	if err != nil {
+		return err
+	}
+
+	edge.PrintHttpResponseCorrelation(res, true, correlationid)
+
+	if client.IsError(res) {
+		return client.NewAPIError(res)
+	}
+
+	var location client.JSONBody
+	if err = client.BodyJSON(res, &location); err != nil {
+		return err
+	}
+
+	link, ok := location[linkField].(string)
+	if !ok {
+		return fmt.Errorf("bulk job response has no %s", linkField)
+	}
+
+	for {
+		req, err = client.NewRequest(Config, "GET", link, nil)
+		if err != nil {
+			return err
+		}
+
+		req = req.WithContext(ctx)
+
+		edge.PrintHttpRequestCorrelation(req, true, correlationid)
+
+		res, err = client.Do(Config, req)
+		if err != nil {
+			return err
+		}
+
+		edge.PrintHttpResponseCorrelation(res, true, correlationid)
+
+		if client.IsError(res) {
+			return client.NewAPIError(res)
+		}
+
+		if err = client.BodyJSON(res, result); err != nil {
+			return err
+		}
+
+		if status().IsDone() {
+			return nil
+		}
+
+		timer := time.NewTimer(retryAfter(res.Header.Get("Retry-After"), BulkPollInterval))
+		select {
+		case <-timer.C:
+		case <-ctx.Done():
+			timer.Stop()
+			return fmt.Errorf("bulk job %s still %s: %w", link, status(), ctx.Err())
+		}
+	}
+}
@@ -0,0 +1,209 @@
+package papi
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/akamai/AkamaiOPEN-edgegrid-golang/jsonpatch-v1"
+	"github.com/stretchr/testify/assert"
+	"gopkg.in/h2non/gock.v1"
+)
+
+func TestBulkSearch(t *testing.T) {
+	defer gock.Off()
+	defer func(interval time.Duration) { BulkPollInterval = interval }(BulkPollInterval)
+	BulkPollInterval = time.Millisecond
+
+	gock.New("https://akaa-baseurl-xxxxxxxxxxx-xxxxxxxxxxxxx.luna.akamaiapis.net").
+		Post("/papi/v1/bulk/rules-search-requests").
+		MatchType("json").
+		JSON(map[string]interface{}{
+			"bulkSearchQuery": map[string]interface{}{
+				"syntax": "JSONPATH",
+				"match":  "$..behaviors[?(@.name == 'modifyOutgoingResponseHeader')]",
+			},
+		}).
+		Reply(202).
+		SetHeader("Content-Type", "application/json").
+		BodyString(`{"bulkSearchLink": "/papi/v1/bulk/rules-search-requests/5"}`)
+
+	gock.New("https://akaa-baseurl-xxxxxxxxxxx-xxxxxxxxxxxxx.luna.akamaiapis.net").
+		Get("/papi/v1/bulk/rules-search-requests/5").
+		Reply(200).
+		SetHeader("Content-Type", "application/json").
+		SetHeader("Retry-After", "0").
+		BodyString(`{"bulkSearchId": 5, "searchTargetStatus": "IN_PROGRESS", "results": []}`)
+
+	gock.New("https://akaa-baseurl-xxxxxxxxxxx-xxxxxxxxxxxxx.luna.akamaiapis.net").
+		Get("/papi/v1/bulk/rules-search-requests/5").
+		Reply(200).
+		SetHeader("Content-Type", "application/json").
+		BodyString(`{
+			"bulkSearchId": 5,
+			"searchTargetStatus": "COMPLETE",
+			"results": [
+				{"propertyId": "prp_1", "propertyName": "a.example.com", "propertyVersion": 3, "isLatest": true, "productionStatus": "ACTIVE", "stagingStatus": "INACTIVE", "matchLocations": ["/rules/children/0/behaviors/1"]},
+				{"propertyId": "prp_2", "propertyName": "b.example.com", "propertyVersion": 7, "isLatest": true, "productionStatus": "ACTIVE", "stagingStatus": "ACTIVE", "matchLocations": ["/rules/behaviors/4"]}
+			]
+		}`)
+
+	Init(config)
+
+	search, err := BulkSearch(context.Background(), BulkSearchQuery{
+		Match: "$..behaviors[?(@.name == 'modifyOutgoingResponseHeader')]",
+	}, "")
+	assert.NoError(t, err)
+	assert.Equal(t, BulkStatusComplete, search.SearchTargetStatus)
+	assert.Len(t, search.Results, 2)
+	assert.Equal(t, StatusActive, search.Results[0].ProductionStatus)
+	assert.Equal(t, []string{"/rules/children/0/behaviors/1"}, search.Results[0].MatchLocations)
+
+	versions := search.Versions()
+	assert.Len(t, versions, 2)
+	assert.Equal(t, "prp_2", versions[1].PropertyID)
+	assert.Equal(t, 7, versions[1].CreateFromVersion)
+	assert.True(t, gock.IsDone())
+}
+
+func TestBulkCreateVersions_PatchAndActivate(t *testing.T) {
+	defer gock.Off()
+	defer func(interval time.Duration) { BulkPollInterval = interval }(BulkPollInterval)
+	BulkPollInterval = time.Millisecond
+
+	gock.New("https://akaa-baseurl-xxxxxxxxxxx-xxxxxxxxxxxxx.luna.akamaiapis.net").
+		Post("/papi/v1/bulk/property-version-creations").
+		Reply(202).
+		SetHeader("Content-Type", "application/json").
+		BodyString(`{"bulkCreateVersionLink": "/papi/v1/bulk/property-version-creations/6"}`)
+
+	gock.New("https://akaa-baseurl-xxxxxxxxxxx-xxxxxxxxxxxxx.luna.akamaiapis.net").
+		Get("/papi/v1/bulk/property-version-creations/6").
+		Reply(200).
+		SetHeader("Content-Type", "application/json").
+		BodyString(`{
+			"bulkCreateVersionsId": 6,
+			"bulkCreateVersionsStatus": "COMPLETE",
+			"createPropertyVersions": [
+				{"propertyId": "prp_1", "createFromVersion": 3, "propertyVersion": 4, "status": "COMPLETE"},
+				{"propertyId": "prp_2", "createFromVersion": 7, "status": "FAILED", "failureCause": "version 7 is locked"}
+			]
+		}`)
+
+	gock.New("https://akaa-baseurl-xxxxxxxxxxx-xxxxxxxxxxxxx.luna.akamaiapis.net").
+		Post("/papi/v1/bulk/rules-patch-requests").
+		MatchType("json").
+		JSON(map[string]interface{}{
+			"patchPropertyVersions": []interface{}{
+				map[string]interface{}{
+					"propertyId":      "prp_1",
+					"propertyVersion": 4,
+					"patches": []interface{}{
+						map[string]interface{}{"op": "replace", "path": "/rules/children/0/behaviors/1/options/value", "value": "DENY"},
+					},
+				},
+			},
+		}).
+		Reply(202).
+		SetHeader("Content-Type", "application/json").
+		BodyString(`{"bulkPatchLink": "/papi/v1/bulk/rules-patch-requests/7"}`)
+
+	gock.New("https://akaa-baseurl-xxxxxxxxxxx-xxxxxxxxxxxxx.luna.akamaiapis.net").
+		Get("/papi/v1/bulk/rules-patch-requests/7").
+		Reply(200).
+		SetHeader("Content-Type", "application/json").
+		BodyString(`{
+			"bulkPatchId": 7,
+			"bulkPatchStatus": "COMPLETE",
+			"patchPropertyVersions": [
+				{"propertyId": "prp_1", "propertyVersion": 4, "status": "UPDATED", "etag": "e4"}
+			]
+		}`)
+
+	gock.New("https://akaa-baseurl-xxxxxxxxxxx-xxxxxxxxxxxxx.luna.akamaiapis.net").
+		Post("/papi/v1/bulk/activations").
+		MatchType("json").
+		JSON(map[string]interface{}{
+			"defaultActivationSettings": map[string]interface{}{
+				"acknowledgeAllWarnings": true,
+				"useFastFallback":        false,
+				"fastPush":               true,
+				"notifyEmails":           []string{"ops@example.com"},
+			},
+			"activatePropertyVersions": []interface{}{
+				map[string]interface{}{"propertyId": "prp_1", "propertyVersion": 4, "network": "STAGING"},
+			},
+		}).
+		Reply(202).
+		SetHeader("Content-Type", "application/json").
+		BodyString(`{"bulkActivationLink": "/papi/v1/bulk/activations/8"}`)
+
+	gock.New("https://akaa-baseurl-xxxxxxxxxxx-xxxxxxxxxxxxx.luna.akamaiapis.net").
+		Get("/papi/v1/bulk/activations/8").
+		Reply(200).
+		SetHeader("Content-Type", "application/json").
+		BodyString(`{
+			"bulkActivationId": 8,
+			"bulkActivationStatus": "COMPLETE",
+			"activatePropertyVersions": [
+				{"propertyId": "prp_1", "propertyVersion": 4, "network": "STAGING", "taskStatus": "COMPLETE", "activationId": "atv_1", "activationStatus": "PENDING"}
+			]
+		}`)
+
+	Init(config)
+
+	input := []*BulkVersion{
+		{PropertyID: "prp_1", CreateFromVersion: 3},
+		{PropertyID: "prp_2", CreateFromVersion: 7},
+	}
+	versions, err := BulkCreateVersions(context.Background(), input, "")
+	assert.NoError(t, err)
+	assert.Equal(t, 0, input[0].PropertyVersion)
+	assert.Len(t, versions.Failed(), 1)
+	assert.Equal(t, "version 7 is locked", versions.Failed()[0].FailureCause)
+
+	patch := jsonpatch.Patch{{Op: jsonpatch.OpReplace, Path: "/rules/children/0/behaviors/1/options/value", Value: "DENY"}}
+	patches, err := BulkPatchRules(context.Background(), versions.Patches(patch), "")
+	assert.NoError(t, err)
+	assert.Empty(t, patches.Failed())
+	assert.Equal(t, BulkStatusUpdated, patches.PatchPropertyVersions[0].Status)
+
+	activations, err := BulkActivate(context.Background(), patches.Activations(NetworkStaging), &BulkActivationSettings{
+		AcknowledgeAllWarnings: true,
+		FastPush:               true,
+		NotifyEmails:           []string{"ops@example.com"},
+	}, "")
+	assert.NoError(t, err)
+	assert.Empty(t, activations.Failed())
+	assert.Equal(t, "atv_1", activations.ActivatePropertyVersions[0].ActivationID)
+	assert.Equal(t, StatusPending, activations.ActivatePropertyVersions[0].ActivationStatus)
+	assert.True(t, gock.IsDone())
+}
+
+func TestBulkSearch_ContextDone(t *testing.T) {
+	defer gock.Off()
+
+	gock.New("https://akaa-baseurl-xxxxxxxxxxx-xxxxxxxxxxxxx.luna.akamaiapis.net").
+		Post("/papi/v1/bulk/rules-search-requests").
+		Reply(202).
+		SetHeader("Content-Type", "application/json").
+		BodyString(`{"bulkSearchLink": "/papi/v1/bulk/rules-search-requests/5"}`)
+
+	gock.New("https://akaa-baseurl-xxxxxxxxxxx-xxxxxxxxxxxxx.luna.akamaiapis.net").
+		Get("/papi/v1/bulk/rules-search-requests/5").
+		Reply(200).
+		SetHeader("Content-Type", "application/json").
+		SetHeader("Retry-After", "60").
+		BodyString(`{"bulkSearchId": 5, "searchTargetStatus": "SUBMITTED"}`)
+
+	Init(config)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+
+	_, err := BulkSearch(ctx, BulkSearchQuery{Match: "$..origin"}, "")
+	assert.Error(t, err)
+	assert.True(t, errors.Is(err, context.DeadlineExceeded))
+	assert.Contains(t, err.Error(), "still SUBMITTED")
+}
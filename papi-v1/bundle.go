@@ -0,0 +1,312 @@
+package papi
+
+import (
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"sort"
+
+	"github.com/akamai/AkamaiOPEN-edgegrid-golang/jsonhooks-v1"
+)
+
+const (
+	// BundlePropertyFile holds the property metadata of a bundle
+	BundlePropertyFile = "property.json"
+	// BundleRulesFile holds the rule tree of a bundle, without variables
+	BundleRulesFile = "rules.json"
+	// BundleVariablesFile holds the property variables of a bundle
+	BundleVariablesFile = "variables.json"
+	// BundleHostnamesFile holds the property hostnames of a bundle
+	BundleHostnamesFile = "hostnames.json"
+)
+
+// PropertyBundle is a property version in a form suitable for storing in Git
+//
+// A bundle holds no IDs, etags, dates or other values that change between
+// versions or accounts, so it can be diffed and applied to another property.
+//
+// See: ExportProperty(), ImportProperty()
+type PropertyBundle struct {
+	PropertyName string      `json:"propertyName"`
+	ProductID    string      `json:"productId"`
+	RuleFormat   string      `json:"ruleFormat"`
+	Rule         *Rule       `json:"-"`
+	Variables    []*Variable `json:"-"`
+	Hostnames    []*Hostname `json:"-"`
+}
+
+// GetPropertyBundle fetches a bundle for a property version
+//
+// If version is 0 the latest version is used.
+func GetPropertyBundle(property *Property, version int, correlationid string) (*PropertyBundle, error) {
+	if property.PropertyName == "" || property.ProductID == "" {
+		if err := property.GetProperty(correlationid); err != nil {
+			return nil, err
+		}
+	}
+
+	if version == 0 {
+		version = property.LatestVersion
+	}
+	if version == 0 {
+		latest, err := property.GetLatestVersion("", correlationid)
+		if err != nil {
+			return nil, err
+		}
+		version = latest.PropertyVersion
+	}
+
+	versionProperty := NewProperty(NewProperties())
+	versionProperty.PropertyID = property.PropertyID
+	versionProperty.ContractID = property.ContractID
+	versionProperty.GroupID = property.GroupID
+	versionProperty.Contract.ContractID = property.ContractID
+	versionProperty.Group.GroupID = property.GroupID
+	versionProperty.LatestVersion = version
+
+	rules, err := versionProperty.GetRules(correlationid)
+	if err != nil {
+		return nil, err
+	}
+
+	hostnames, err := versionProperty.GetHostnames(&Version{PropertyVersion: version}, correlationid)
+	if err != nil {
+		return nil, err
+	}
+
+	bundle := &PropertyBundle{
+		PropertyName: property.PropertyName,
+		ProductID:    property.ProductID,
+		RuleFormat:   rules.RuleFormat,
+		Rule:         rules.Rule,
+	}
+
+	if bundle.Rule != nil {
+		bundle.Variables = bundle.Rule.Variables
+		bundle.Rule.Variables = nil
+	}
+
+	// Only the fields that describe the hostname, leaving out edge hostname IDs
+	// and certificate status
+	for _, hostname := range hostnames.Hostnames.Items {
+		bundleHostname := NewHostname(nil)
+		bundleHostname.CnameType = hostname.CnameType
+		bundleHostname.CnameFrom = hostname.CnameFrom
+		bundleHostname.CnameTo = hostname.CnameTo
+		bundleHostname.CertEnrollmentId = hostname.CertEnrollmentId
+		bundleHostname.CertProvisioningType = hostname.CertProvisioningType
+		bundle.Hostnames = append(bundle.Hostnames, bundleHostname)
+	}
+
+	return bundle, nil
+}
+
+// ExportProperty writes a bundle for a property version to dir
+//
+// If version is 0 the latest version is used. Files are written with sorted
+// keys, variables sorted by name and hostnames sorted by cnameFrom, so the
+// same configuration always produces the same bytes.
+func ExportProperty(property *Property, version int, dir string, correlationid string) error {
+	bundle, err := GetPropertyBundle(property, version, correlationid)
+	if err != nil {
+		return err
+	}
+
+	return bundle.Write(dir)
+}
+
+// Write writes the bundle to dir
+func (bundle *PropertyBundle) Write(dir string) error {
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return err
+	}
+
+	variables := append([]*Variable{}, bundle.Variables...)
+	sort.SliceStable(variables, func(i, j int) bool {
+		return variables[i].Name < variables[j].Name
+	})
+
+	hostnames := append([]*Hostname{}, bundle.Hostnames...)
+	sort.SliceStable(hostnames, func(i, j int) bool {
+		return hostnames[i].CnameFrom < hostnames[j].CnameFrom
+	})
+
+	files := map[string]interface{}{
+		BundlePropertyFile:  bundle,
+		BundleRulesFile:     map[string]interface{}{"rules": bundle.Rule},
+		BundleVariablesFile: variables,
+		BundleHostnamesFile: hostnames,
+	}
+
+	for file, value := range files {
+		if err := writeBundleFile(filepath.Join(dir, file), value); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// writeBundleFile writes value as indented JSON with sorted keys
+func writeBundleFile(path string, value interface{}) error {
+	data, err := jsonhooks.Marshal(value)
+	if err != nil {
+		return err
+	}
+
+	document, err := decodeSnippet(data)
+	if err != nil {
+		return err
+	}
+
+	return writeSnippet(path, document)
+}
+
+// ReadPropertyBundle reads a bundle written by ExportProperty
+func ReadPropertyBundle(dir string) (*PropertyBundle, error) {
+	bundle := &PropertyBundle{}
+	if err := readBundleFile(filepath.Join(dir, BundlePropertyFile), bundle); err != nil {
+		return nil, err
+	}
+
+	rules := NewRules()
+	if err := readBundleFile(filepath.Join(dir, BundleRulesFile), rules); err != nil {
+		return nil, err
+	}
+	bundle.Rule = rules.Rule
+
+	if err := readBundleFile(filepath.Join(dir, BundleVariablesFile), &bundle.Variables); err != nil {
+		return nil, err
+	}
+
+	if err := readBundleFile(filepath.Join(dir, BundleHostnamesFile), &bundle.Hostnames); err != nil {
+		return nil, err
+	}
+
+	return bundle, nil
+}
+
+func readBundleFile(path string, value interface{}) error {
+	data, err := ioutil.ReadFile(path)
+	if err != nil {
+		return err
+	}
+
+	if err = jsonhooks.Unmarshal(data, value); err != nil {
+		return fmt.Errorf("%s: %s", path, err)
+	}
+
+	return nil
+}
+
+// ImportProperty applies a bundle in dir to the property of the same name in
+// a contract and group, creating the property if it does not exist
+//
+// See: PropertyBundle.Apply()
+func ImportProperty(dir string, contract *Contract, group *Group, correlationid string) (*Property, error) {
+	bundle, err := ReadPropertyBundle(dir)
+	if err != nil {
+		return nil, err
+	}
+
+	properties := NewProperties()
+	if err = properties.GetProperties(contract, group, correlationid); err != nil {
+		return nil, err
+	}
+
+	var property *Property
+	for _, existing := range properties.Properties.Items {
+		if existing.PropertyName == bundle.PropertyName {
+			property = existing
+			break
+		}
+	}
+
+	if property == nil {
+		property = NewProperty(properties)
+		property.Contract = contract
+		property.Group = group
+		property.PropertyName = bundle.PropertyName
+		property.ProductID = bundle.ProductID
+		property.RuleFormat = bundle.RuleFormat
+		if err = property.Save(correlationid); err != nil {
+			return nil, err
+		}
+	}
+
+	property.ContractID = contract.ContractID
+	property.GroupID = group.GroupID
+
+	if _, err = bundle.Apply(property, correlationid); err != nil {
+		return nil, err
+	}
+
+	return property, nil
+}
+
+// Apply saves the bundle's rules, variables and hostnames to a property
+//
+// The latest version is updated if it has never been activated, otherwise a
+// new version is created from it. The version written to is returned.
+func (bundle *PropertyBundle) Apply(property *Property, correlationid string) (int, error) {
+	versions := NewVersions()
+	versions.PropertyID = property.PropertyID
+	versions.ContractID = property.ContractID
+	versions.GroupID = property.GroupID
+
+	version, err := versions.GetLatestVersion("", correlationid)
+	if err != nil {
+		return 0, err
+	}
+
+	if isActivatedStatus(version.StagingStatus) || isActivatedStatus(version.ProductionStatus) {
+		version = versions.NewVersion(version, true, correlationid)
+		if err = version.Save(correlationid); err != nil {
+			return 0, err
+		}
+	}
+
+	rules := NewRules()
+	rules.PropertyID = property.PropertyID
+	rules.PropertyVersion = version.PropertyVersion
+	rules.ContractID = property.ContractID
+	rules.GroupID = property.GroupID
+	rules.RuleFormat = bundle.RuleFormat
+	if bundle.Rule != nil {
+		rule := *bundle.Rule
+		rule.Variables = bundle.Variables
+		rules.Rule = &rule
+	}
+
+	if bundle.RuleFormat != "" && bundle.RuleFormat != "latest" {
+		err = rules.Freeze(bundle.RuleFormat)
+	} else {
+		err = rules.Save(correlationid)
+	}
+	if err != nil {
+		return 0, err
+	}
+
+	hostnames := NewHostnames()
+	hostnames.PropertyID = property.PropertyID
+	hostnames.PropertyVersion = version.PropertyVersion
+	hostnames.ContractID = property.ContractID
+	hostnames.GroupID = property.GroupID
+	hostnames.Hostnames.Items = bundle.Hostnames
+	if hostnames.Hostnames.Items == nil {
+		hostnames.Hostnames.Items = []*Hostname{}
+	}
+
+	if err = hostnames.Save(); err != nil {
+		return 0, err
+	}
+
+	return version.PropertyVersion, nil
+}
+
+// isActivatedStatus reports whether a version network status means the
+// version has been activated and can no longer be edited
+func isActivatedStatus(status StatusValue) bool {
+	return status != "" && status != StatusInactive
+}
@@ -0,0 +1,203 @@
+package papi
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strconv"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"gopkg.in/h2non/gock.v1"
+)
+
+func mockBundleVersion(version string) {
+	gock.New("https://akaa-baseurl-xxxxxxxxxxx-xxxxxxxxxxxxx.luna.akamaiapis.net").
+		Get("/papi/v1/properties/prp_1/versions/"+version+"/rules").
+		Reply(200).
+		SetHeader("Content-Type", "application/json").
+		SetHeader("Etag", `"etag-`+version+`"`).
+		BodyString(`{
+			"propertyId": "prp_1",
+			"propertyVersion": ` + version + `,
+			"etag": "etag-` + version + `",
+			"ruleFormat": "v2018-02-27",
+			"rules": {
+				"name": "default",
+				"options": {"is_secure": false},
+				"variables": [
+					{"name": "PMUSER_B", "value": "b", "description": "", "hidden": false, "sensitive": false},
+					{"name": "PMUSER_A", "value": "a", "description": "", "hidden": false, "sensitive": false}
+				],
+				"behaviors": [{"name": "cpCode", "options": {"value": {"id": 1}}}]
+			}
+		}`)
+
+	gock.New("https://akaa-baseurl-xxxxxxxxxxx-xxxxxxxxxxxxx.luna.akamaiapis.net").
+		Get("/papi/v1/properties/prp_1/versions/"+version+"/hostnames").
+		Reply(200).
+		SetHeader("Content-Type", "application/json").
+		BodyString(`{
+			"propertyId": "prp_1",
+			"propertyVersion": ` + version + `,
+			"etag": "etag-` + version + `",
+			"hostnames": {"items": [
+				{"cnameType": "EDGE_HOSTNAME", "edgeHostnameId": "ehn_2", "cnameFrom": "www.example.com", "cnameTo": "www.example.com.edgesuite.net", "certStatus": {"staging": [{"status": "PENDING"}]}},
+				{"cnameType": "EDGE_HOSTNAME", "edgeHostnameId": "ehn_1", "cnameFrom": "api.example.com", "cnameTo": "api.example.com.edgesuite.net"}
+			]}
+		}`)
+}
+
+func exportTestBundle(t *testing.T, version int) string {
+	mockBundleVersion(strconv.Itoa(version))
+
+	dir, err := ioutil.TempDir("", "papi-bundle")
+	assert.NoError(t, err)
+
+	property := NewProperty(NewProperties())
+	property.PropertyID = "prp_1"
+	property.PropertyName = "example.com"
+	property.ProductID = "prd_Fresca"
+	property.LatestVersion = 9
+
+	assert.NoError(t, ExportProperty(property, version, dir, ""))
+
+	return dir
+}
+
+func TestExportProperty(t *testing.T) {
+	defer gock.Off()
+	Init(config)
+
+	dir := exportTestBundle(t, 3)
+	defer os.RemoveAll(dir)
+
+	propertyFile, err := ioutil.ReadFile(filepath.Join(dir, BundlePropertyFile))
+	assert.NoError(t, err)
+	assert.Equal(t, `{
+  "productId": "prd_Fresca",
+  "propertyName": "example.com",
+  "ruleFormat": "v2018-02-27"
+}
+`, string(propertyFile))
+
+	rulesFile, err := ioutil.ReadFile(filepath.Join(dir, BundleRulesFile))
+	assert.NoError(t, err)
+	assert.NotContains(t, string(rulesFile), "etag")
+	assert.NotContains(t, string(rulesFile), "PMUSER")
+	assert.Contains(t, string(rulesFile), `"name": "cpCode"`)
+
+	variablesFile, err := ioutil.ReadFile(filepath.Join(dir, BundleVariablesFile))
+	assert.NoError(t, err)
+	assert.Regexp(t, `(?s)PMUSER_A.*PMUSER_B`, string(variablesFile))
+
+	hostnamesFile, err := ioutil.ReadFile(filepath.Join(dir, BundleHostnamesFile))
+	assert.NoError(t, err)
+	assert.Equal(t, `[
+  {
+    "cnameFrom": "api.example.com",
+    "cnameTo": "api.example.com.edgesuite.net",
+    "cnameType": "EDGE_HOSTNAME"
+  },
+  {
+    "cnameFrom": "www.example.com",
+    "cnameTo": "www.example.com.edgesuite.net",
+    "cnameType": "EDGE_HOSTNAME"
+  }
+]
+`, string(hostnamesFile))
+
+	assert.True(t, gock.IsDone())
+}
+
+func TestExportProperty_Stable(t *testing.T) {
+	defer gock.Off()
+	Init(config)
+
+	first := exportTestBundle(t, 3)
+	defer os.RemoveAll(first)
+	second := exportTestBundle(t, 4)
+	defer os.RemoveAll(second)
+
+	for _, file := range []string{BundlePropertyFile, BundleRulesFile, BundleVariablesFile, BundleHostnamesFile} {
+		a, err := ioutil.ReadFile(filepath.Join(first, file))
+		assert.NoError(t, err)
+		b, err := ioutil.ReadFile(filepath.Join(second, file))
+		assert.NoError(t, err)
+		assert.Equal(t, string(a), string(b), file)
+	}
+}
+
+func TestReadPropertyBundle(t *testing.T) {
+	defer gock.Off()
+	Init(config)
+
+	dir := exportTestBundle(t, 3)
+	defer os.RemoveAll(dir)
+
+	bundle, err := ReadPropertyBundle(dir)
+	assert.NoError(t, err)
+	assert.Equal(t, "example.com", bundle.PropertyName)
+	assert.Equal(t, "prd_Fresca", bundle.ProductID)
+	assert.Equal(t, "v2018-02-27", bundle.RuleFormat)
+	assert.Equal(t, "default", bundle.Rule.Name)
+	assert.Nil(t, bundle.Rule.Variables)
+	assert.Len(t, bundle.Variables, 2)
+	assert.Equal(t, "PMUSER_A", bundle.Variables[0].Name)
+	assert.Len(t, bundle.Hostnames, 2)
+	assert.Equal(t, "api.example.com", bundle.Hostnames[0].CnameFrom)
+	assert.Empty(t, bundle.Hostnames[0].EdgeHostnameID)
+}
+
+func TestPropertyBundle_Apply(t *testing.T) {
+	defer gock.Off()
+	Init(config)
+
+	dir := exportTestBundle(t, 3)
+	defer os.RemoveAll(dir)
+
+	bundle, err := ReadPropertyBundle(dir)
+	assert.NoError(t, err)
+
+	gock.New("https://akaa-baseurl-xxxxxxxxxxx-xxxxxxxxxxxxx.luna.akamaiapis.net").
+		Get("/papi/v1/properties/prp_1/versions/latest").
+		Reply(200).
+		SetHeader("Content-Type", "application/json").
+		BodyString(`{"propertyId": "prp_1", "versions": {"items": [{"propertyVersion": 5, "etag": "e5", "stagingStatus": "ACTIVE", "productionStatus": "INACTIVE"}]}}`)
+
+	gock.New("https://akaa-baseurl-xxxxxxxxxxx-xxxxxxxxxxxxx.luna.akamaiapis.net").
+		Post("/papi/v1/properties/prp_1/versions").
+		Reply(201).
+		SetHeader("Content-Type", "application/json").
+		BodyString(`{"versionLink": "/papi/v1/properties/prp_1/versions/6"}`)
+
+	gock.New("https://akaa-baseurl-xxxxxxxxxxx-xxxxxxxxxxxxx.luna.akamaiapis.net").
+		Get("/papi/v1/properties/prp_1/versions/6").
+		Reply(200).
+		SetHeader("Content-Type", "application/json").
+		BodyString(`{"propertyId": "prp_1", "versions": {"items": [{"propertyVersion": 6, "etag": "e6"}]}}`)
+
+	gock.New("https://akaa-baseurl-xxxxxxxxxxx-xxxxxxxxxxxxx.luna.akamaiapis.net").
+		Put("/papi/v1/properties/prp_1/versions/6/rules").
+		MatchHeader("Content-Type", `application/vnd\.akamai\.papirules\.v2018-02-27\+json`).
+		Reply(200).
+		SetHeader("Content-Type", "application/json").
+		BodyString(`{"propertyId": "prp_1", "propertyVersion": 6, "rules": {"name": "default"}}`)
+
+	gock.New("https://akaa-baseurl-xxxxxxxxxxx-xxxxxxxxxxxxx.luna.akamaiapis.net").
+		Put("/papi/v1/properties/prp_1/versions/6/hostnames").
+		Reply(200).
+		SetHeader("Content-Type", "application/json").
+		BodyString(`{"propertyId": "prp_1", "propertyVersion": 6, "hostnames": {"items": []}}`)
+
+	property := NewProperty(NewProperties())
+	property.PropertyID = "prp_1"
+	property.ContractID = "ctr_1"
+	property.GroupID = "grp_1"
+
+	version, err := bundle.Apply(property, "")
+	assert.NoError(t, err)
+	assert.Equal(t, 6, version)
+	assert.Nil(t, bundle.Rule.Variables)
+	assert.True(t, gock.IsDone())
+}
@@ -0,0 +1,243 @@
+package papi
+
+import (
+	"encoding/json"
+	"io/ioutil"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/akamai/AkamaiOPEN-edgegrid-golang/client-v1"
+	edge "github.com/akamai/AkamaiOPEN-edgegrid-golang/edgegrid"
+	"github.com/akamai/AkamaiOPEN-edgegrid-golang/jsonhooks-v1"
+	"github.com/patrickmn/go-cache"
+)
+
+// Cache stores PAPI responses for lookups that rarely change, such as
+// groups, contracts, products, CP codes and edge hostnames
+//
+// Keys are made of the API host, the path and the sorted query string,
+// including contractId, groupId, options and accountSwitchKey, so responses
+// are never shared between accounts, contracts or groups.
+//
+// See: ResponseCache, NewMemoryCache(), NewFileCache(), NoCache
+type Cache interface {
+	// Get returns the response stored under key, if it has not expired
+	Get(key string) ([]byte, bool)
+	// Set stores a response under key for ttl
+	Set(key string, value []byte, ttl time.Duration)
+	// Invalidate removes every response whose key starts with prefix
+	Invalidate(prefix string)
+}
+
+var (
+	// ResponseCache is the Cache used for PAPI lookups. Set it to NoCache{}
+	// to disable caching
+	ResponseCache Cache = NewMemoryCache()
+	// CacheTTL is how long responses are kept in ResponseCache
+	CacheTTL = 5 * time.Minute
+)
+
+// MemoryCache is an in-memory Cache
+type MemoryCache struct {
+	cache *cache.Cache
+}
+
+// NewMemoryCache creates a new MemoryCache
+func NewMemoryCache() *MemoryCache {
+	return &MemoryCache{cache: cache.New(CacheTTL, 10*time.Minute)}
+}
+
+// Get returns the response stored under key
+func (memoryCache *MemoryCache) Get(key string) ([]byte, bool) {
+	value, found := memoryCache.cache.Get(key)
+	if !found {
+		return nil, false
+	}
+
+	return value.([]byte), true
+}
+
+// Set stores a response under key
+func (memoryCache *MemoryCache) Set(key string, value []byte, ttl time.Duration) {
+	memoryCache.cache.Set(key, value, ttl)
+}
+
+// Invalidate removes every response whose key starts with prefix
+func (memoryCache *MemoryCache) Invalidate(prefix string) {
+	for key := range memoryCache.cache.Items() {
+		if strings.HasPrefix(key, prefix) {
+			memoryCache.cache.Delete(key)
+		}
+	}
+}
+
+// FileCache is a Cache kept in a JSON file, so that responses are reused
+// across runs of a command line tool
+type FileCache struct {
+	path    string
+	mutex   sync.Mutex
+	entries map[string]fileCacheEntry
+}
+
+type fileCacheEntry struct {
+	Value   []byte    `json:"value"`
+	Expires time.Time `json:"expires"`
+}
+
+// NewFileCache creates a FileCache stored at path
+//
+// A missing or unreadable file is treated as an empty cache.
+func NewFileCache(path string) *FileCache {
+	return &FileCache{path: path}
+}
+
+// Get returns the response stored under key
+func (fileCache *FileCache) Get(key string) ([]byte, bool) {
+	fileCache.mutex.Lock()
+	defer fileCache.mutex.Unlock()
+
+	fileCache.load()
+
+	entry, found := fileCache.entries[key]
+	if !found || time.Now().After(entry.Expires) {
+		return nil, false
+	}
+
+	return entry.Value, true
+}
+
+// Set stores a response under key, writing the file
+func (fileCache *FileCache) Set(key string, value []byte, ttl time.Duration) {
+	fileCache.mutex.Lock()
+	defer fileCache.mutex.Unlock()
+
+	fileCache.load()
+	fileCache.entries[key] = fileCacheEntry{Value: value, Expires: time.Now().Add(ttl)}
+	fileCache.save()
+}
+
+// Invalidate removes every response whose key starts with prefix, writing
+// the file
+func (fileCache *FileCache) Invalidate(prefix string) {
+	fileCache.mutex.Lock()
+	defer fileCache.mutex.Unlock()
+
+	fileCache.load()
+	for key := range fileCache.entries {
+		if strings.HasPrefix(key, prefix) {
+			delete(fileCache.entries, key)
+		}
+	}
+	fileCache.save()
+}
+
+// load reads the file the first time the cache is used
+func (fileCache *FileCache) load() {
+	if fileCache.entries != nil {
+		return
+	}
+
+	fileCache.entries = map[string]fileCacheEntry{}
+	data, err := ioutil.ReadFile(fileCache.path)
+	if err != nil {
+		return
+	}
+
+	json.Unmarshal(data, &fileCache.entries)
+	if fileCache.entries == nil {
+		fileCache.entries = map[string]fileCacheEntry{}
+	}
+}
+
+// save writes the unexpired entries to the file
+//
+// Errors are ignored, as a cache that cannot be written only means more
+// requests are made.
+func (fileCache *FileCache) save() {
+	now := time.Now()
+	for key, entry := range fileCache.entries {
+		if now.After(entry.Expires) {
+			delete(fileCache.entries, key)
+		}
+	}
+
+	data, err := json.Marshal(fileCache.entries)
+	if err != nil {
+		return
+	}
+
+	ioutil.WriteFile(fileCache.path, data, 0600)
+}
+
+// NoCache is a Cache that stores nothing
+type NoCache struct{}
+
+// Get never finds a response
+func (NoCache) Get(key string) ([]byte, bool) {
+	return nil, false
+}
+
+// Set does nothing
+func (NoCache) Set(key string, value []byte, ttl time.Duration) {}
+
+// Invalidate does nothing
+func (NoCache) Invalidate(prefix string) {}
+
+// cacheKey returns the ResponseCache key for a request
+func cacheKey(req *http.Request) string {
+	return req.URL.Host + req.URL.Path + "?" + req.URL.Query().Encode()
+}
+
+// invalidateCache removes cached responses for path, for every query
+func invalidateCache(path string) error {
+	req, err := client.NewRequest(Config, "GET", path, nil)
+	if err != nil {
+		return err
+	}
+
+	ResponseCache.Invalidate(req.URL.Host + req.URL.Path)
+
+	return nil
+}
+
+// getCached performs a GET request, answering it from ResponseCache when
+// possible, and unmarshals the response into value
+func getCached(path string, value interface{}, correlationid string) error {
+	req, err := client.NewRequest(Config, "GET", path, nil)
+	if err != nil {
+		return err
+	}
+
+	key := cacheKey(req)
+	if body, found := ResponseCache.Get(key); found {
+		return jsonhooks.Unmarshal(body, value)
+	}
+
+	edge.PrintHttpRequestCorrelation(req, true, correlationid)
+
+	res, err := client.Do(Config, req)
+	if err != nil {
+		return err
+	}
+
+	edge.PrintHttpResponseCorrelation(res, true, correlationid)
+
+	if client.IsError(res) {
+		return client.NewAPIError(res)
+	}
+
+	body, err := ioutil.ReadAll(res.Body)
+	if err != nil {
+		return err
+	}
+
+	if err = jsonhooks.Unmarshal(body, value); err != nil {
+		return err
+	}
+
+	ResponseCache.Set(key, body, CacheTTL)
+
+	return nil
+}
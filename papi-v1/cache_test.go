@@ -0,0 +1,123 @@
+package papi
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"gopkg.in/h2non/gock.v1"
+)
+
+func mockEdgeHostnames(contractID string, edgeHostnameID string) {
+	gock.New("https://akaa-baseurl-xxxxxxxxxxx-xxxxxxxxxxxxx.luna.akamaiapis.net").
+		Get("/papi/v1/edgehostnames").
+		MatchParam("contractId", contractID).
+		MatchParam("groupId", "grp_1").
+		Reply(200).
+		SetHeader("Content-Type", "application/json").
+		BodyString(`{"contractId": "` + contractID + `", "groupId": "grp_1", "edgeHostnames": {"items": [
+			{"edgeHostnameId": "` + edgeHostnameID + `", "edgeHostnameDomain": "www.example.com.edgesuite.net", "domainPrefix": "www.example.com", "domainSuffix": "edgesuite.net"}
+		]}}`)
+}
+
+func getTestEdgeHostnames(t *testing.T, contractID string) *EdgeHostnames {
+	contract := NewContract(NewContracts())
+	contract.ContractID = contractID
+	group := NewGroup(NewGroups())
+	group.GroupID = "grp_1"
+
+	edgeHostnames := NewEdgeHostnames()
+	assert.NoError(t, edgeHostnames.GetEdgeHostnames(contract, group, "", ""))
+
+	return edgeHostnames
+}
+
+func TestResponseCache_ScopedByContract(t *testing.T) {
+	defer gock.Off()
+	ResponseCache = NewMemoryCache()
+
+	mockEdgeHostnames("ctr_1", "ehn_1")
+	mockEdgeHostnames("ctr_2", "ehn_2")
+
+	Init(config)
+
+	assert.Equal(t, "ehn_1", getTestEdgeHostnames(t, "ctr_1").EdgeHostnames.Items[0].EdgeHostnameID)
+	assert.Equal(t, "ehn_2", getTestEdgeHostnames(t, "ctr_2").EdgeHostnames.Items[0].EdgeHostnameID)
+	assert.True(t, gock.IsDone())
+
+	// Served from the cache
+	assert.Equal(t, "ehn_1", getTestEdgeHostnames(t, "ctr_1").EdgeHostnames.Items[0].EdgeHostnameID)
+}
+
+func TestResponseCache_InvalidatedBySave(t *testing.T) {
+	defer gock.Off()
+	ResponseCache = NewMemoryCache()
+
+	mockEdgeHostnames("ctr_1", "ehn_1")
+
+	gock.New("https://akaa-baseurl-xxxxxxxxxxx-xxxxxxxxxxxxx.luna.akamaiapis.net").
+		Post("/papi/v1/edgehostnames/").
+		Reply(201).
+		SetHeader("Content-Type", "application/json").
+		BodyString(`{"edgeHostnameLink": "/papi/v1/edgehostnames/ehn_9?contractId=ctr_1&groupId=grp_1"}`)
+
+	mockEdgeHostnames("ctr_1", "ehn_9")
+
+	Init(config)
+
+	edgeHostnames := getTestEdgeHostnames(t, "ctr_1")
+	edgeHostnames.ContractID = "ctr_1"
+	edgeHostnames.GroupID = "grp_1"
+
+	edgeHostname := edgeHostnames.NewEdgeHostname()
+	edgeHostname.DomainPrefix = "api.example.com"
+	edgeHostname.DomainSuffix = "edgesuite.net"
+	assert.NoError(t, edgeHostname.Save("", ""))
+
+	assert.Equal(t, "ehn_9", getTestEdgeHostnames(t, "ctr_1").EdgeHostnames.Items[0].EdgeHostnameID)
+	assert.True(t, gock.IsDone())
+}
+
+func TestResponseCache_NoCache(t *testing.T) {
+	defer gock.Off()
+	ResponseCache = NoCache{}
+	defer func() { ResponseCache = NewMemoryCache() }()
+
+	mockEdgeHostnames("ctr_1", "ehn_1")
+	mockEdgeHostnames("ctr_1", "ehn_2")
+
+	Init(config)
+
+	assert.Equal(t, "ehn_1", getTestEdgeHostnames(t, "ctr_1").EdgeHostnames.Items[0].EdgeHostnameID)
+	assert.Equal(t, "ehn_2", getTestEdgeHostnames(t, "ctr_1").EdgeHostnames.Items[0].EdgeHostnameID)
+	assert.True(t, gock.IsDone())
+}
+
+func TestFileCache(t *testing.T) {
+	dir, err := ioutil.TempDir("", "papi-cache")
+	assert.NoError(t, err)
+	defer os.RemoveAll(dir)
+
+	path := filepath.Join(dir, "cache.json")
+
+	fileCache := NewFileCache(path)
+	fileCache.Set("host/papi/v1/groups?", []byte(`{"groups": {}}`), time.Minute)
+	fileCache.Set("host/papi/v1/cpcodes?contractId=ctr_1", []byte(`{"cpcodes": {}}`), time.Minute)
+	fileCache.Set("host/papi/v1/products?contractId=ctr_1", []byte(`{}`), -time.Minute)
+
+	// A new run reads the file
+	fileCache = NewFileCache(path)
+	value, found := fileCache.Get("host/papi/v1/groups?")
+	assert.True(t, found)
+	assert.Equal(t, `{"groups": {}}`, string(value))
+
+	_, found = fileCache.Get("host/papi/v1/products?contractId=ctr_1")
+	assert.False(t, found)
+
+	fileCache.Invalidate("host/papi/v1/cpcodes")
+	_, found = NewFileCache(path).Get("host/papi/v1/cpcodes?contractId=ctr_1")
+	assert.False(t, found)
+}
@@ -1,12 +1,10 @@
 package papi
 
 import (
-	"encoding/json"
 	"fmt"
 
 	"github.com/akamai/AkamaiOPEN-edgegrid-golang/client-v1"
 	edge "github.com/akamai/AkamaiOPEN-edgegrid-golang/edgegrid"
-	"github.com/patrickmn/go-cache"
 )
 
 // Contracts represents a collection of property manager contracts
@@ -48,47 +46,7 @@ func (contracts *Contracts) PostUnmarshalJSON() error {
 // API Docs: https://developer.akamai.com/api/luna/papi/resources.html#listcontracts
 // Endpoint: GET /papi/v1/contracts
 func (contracts *Contracts) GetContracts(correlationid string) error {
-
-	cachecontracts, found := Profilecache.Get("contracts")
-	if found {
-		json.Unmarshal(cachecontracts.([]byte), contracts)
-		return nil
-	} else {
-
-		req, err := client.NewRequest(
-			Config,
-			"GET",
-			"/papi/v1/contracts",
-			nil,
-		)
-		if err != nil {
-			return err
-		}
-
-		edge.PrintHttpRequestCorrelation(req, true, correlationid)
-
-		res, err := client.Do(Config, req)
-		if err != nil {
-			return err
-		}
-
-		edge.PrintHttpResponseCorrelation(res, true, correlationid)
-
-		if client.IsError(res) {
-			return client.NewAPIError(res)
-		}
-
-		if err = client.BodyJSON(res, contracts); err != nil {
-			return err
-		}
-
-		if err != nil {
-			return err
-		}
-		byt, _ := json.Marshal(contracts)
-		Profilecache.Set("contracts", byt, cache.DefaultExpiration)
-		return nil
-	}
+	return getCached("/papi/v1/contracts", contracts, correlationid)
 }
 
 // FindContract finds a specific contract by ID
@@ -137,11 +95,11 @@ func (contract *Contract) GetContract() error {
 		if c.ContractID == contract.ContractID {
 			contract.parent = c.parent
 			contract.ContractTypeName = c.ContractTypeName
-			contract.Complete <- true
+			signalComplete(contract.Complete, true)
 			return nil
 		}
 	}
-	contract.Complete <- false
+	signalComplete(contract.Complete, false)
 	return fmt.Errorf("contract \"%s\" not found", contract.ContractID)
 }
 
@@ -0,0 +1,149 @@
+package papi
+
+import (
+	"fmt"
+
+	"github.com/akamai/AkamaiOPEN-edgegrid-golang/client-v1"
+	edge "github.com/akamai/AkamaiOPEN-edgegrid-golang/edgegrid"
+)
+
+// CpCodeDetails represents a CP Code in the CP Code and Reporting Group API
+//
+// PAPI can only list and create CP Codes; renaming and other updates are made
+// through this API.
+//
+// See: CpCode.GetDetails()
+// API Docs: https://developer.akamai.com/api/core_features/cp_codes_reporting_groups/v1.html
+type CpCodeDetails struct {
+	CpcodeID         int               `json:"cpcodeId"`
+	CpcodeName       string            `json:"cpcodeName"`
+	AccountID        string            `json:"accountId,omitempty"`
+	Purgeable        bool              `json:"purgeable"`
+	DefaultTimezone  string            `json:"defaultTimezone,omitempty"`
+	OverrideTimezone *CpCodeTimezone   `json:"overrideTimezone,omitempty"`
+	Type             string            `json:"type,omitempty"`
+	Contracts        []*CpCodeContract `json:"contracts"`
+	Products         []*CpCodeProduct  `json:"products"`
+}
+
+// CpCodeTimezone is the timezone used to report on a CP Code
+type CpCodeTimezone struct {
+	TimezoneID    string `json:"timezoneId"`
+	TimezoneValue string `json:"timezoneValue,omitempty"`
+}
+
+// CpCodeContract is a contract a CP Code belongs to
+type CpCodeContract struct {
+	ContractID string `json:"contractId"`
+	Status     string `json:"status,omitempty"`
+}
+
+// CpCodeProduct is a product a CP Code is assigned to
+type CpCodeProduct struct {
+	ProductID   string `json:"productId"`
+	ProductName string `json:"productName,omitempty"`
+}
+
+// ProductIDs returns the IDs of the products the CP Code is assigned to
+func (details *CpCodeDetails) ProductIDs() []string {
+	productIDs := make([]string, 0, len(details.Products))
+	for _, product := range details.Products {
+		productIDs = append(productIDs, product.ProductID)
+	}
+
+	return productIDs
+}
+
+// GetDetails retrieves the CP Code from the CP Code and Reporting Group API,
+// including its products and their names
+//
+// API Docs: https://developer.akamai.com/api/core_features/cp_codes_reporting_groups/v1.html#getcpcode
+// Endpoint: GET /cprg/v1/cpcodes/{cpcodeId}
+func (cpcode *CpCode) GetDetails(correlationid string) (*CpCodeDetails, error) {
+	req, err := client.NewRequest(
+		Config,
+		"GET",
+		fmt.Sprintf("/cprg/v1/cpcodes/%d", cpcode.ID()),
+		nil,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	edge.PrintHttpRequestCorrelation(req, true, correlationid)
+
+	res, err := client.Do(Config, req)
+	if err != nil {
+		return nil, err
+	}
+
+	edge.PrintHttpResponseCorrelation(res, true, correlationid)
+
+	if client.IsError(res) {
+		return nil, client.NewAPIError(res)
+	}
+
+	details := &CpCodeDetails{}
+	if err = client.BodyJSON(res, details); err != nil {
+		return nil, err
+	}
+
+	return details, nil
+}
+
+// Update replaces the CP Code with details, which should be retrieved with
+// GetDetails() and then modified. The CpCode is updated with the new name and
+// products.
+//
+// API Docs: https://developer.akamai.com/api/core_features/cp_codes_reporting_groups/v1.html#putcpcode
+// Endpoint: PUT /cprg/v1/cpcodes/{cpcodeId}
+func (cpcode *CpCode) Update(details *CpCodeDetails, correlationid string) error {
+	req, err := client.NewJSONRequest(
+		Config,
+		"PUT",
+		fmt.Sprintf("/cprg/v1/cpcodes/%d", cpcode.ID()),
+		details,
+	)
+	if err != nil {
+		return err
+	}
+
+	edge.PrintHttpRequestCorrelation(req, true, correlationid)
+
+	res, err := client.Do(Config, req)
+	if err != nil {
+		return err
+	}
+
+	edge.PrintHttpResponseCorrelation(res, true, correlationid)
+
+	if client.IsError(res) {
+		return client.NewAPIError(res)
+	}
+
+	if err = invalidateCache("/papi/v1/cpcodes"); err != nil {
+		return err
+	}
+
+	updated := &CpCodeDetails{}
+	if err = client.BodyJSON(res, updated); err != nil {
+		return err
+	}
+
+	cpcode.CpcodeName = updated.CpcodeName
+	cpcode.ProductIDs = updated.ProductIDs()
+
+	return nil
+}
+
+// Rename changes the name of the CP Code, leaving its other details as they are
+func (cpcode *CpCode) Rename(name string, correlationid string) error {
+	details, err := cpcode.GetDetails(correlationid)
+	if err != nil {
+		return err
+	}
+
+	details.CpcodeName = name
+
+	return cpcode.Update(details, correlationid)
+}
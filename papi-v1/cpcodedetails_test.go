@@ -0,0 +1,49 @@
+package papi
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"gopkg.in/h2non/gock.v1"
+)
+
+func TestCpCode_Rename(t *testing.T) {
+	defer gock.Off()
+
+	gock.New("https://akaa-baseurl-xxxxxxxxxxx-xxxxxxxxxxxxx.luna.akamaiapis.net").
+		Get("/cprg/v1/cpcodes/12345").
+		Reply(200).
+		SetHeader("Content-Type", "application/json").
+		BodyString(`{
+			"cpcodeId": 12345,
+			"cpcodeName": "old-name",
+			"purgeable": true,
+			"overrideTimezone": {"timezoneId": "0", "timezoneValue": "GMT 0 (Greenwich Mean Time)"},
+			"contracts": [{"contractId": "C-1", "status": "ongoing"}],
+			"products": [{"productId": "SPM", "productName": "Ion Premier"}]
+		}`)
+
+	gock.New("https://akaa-baseurl-xxxxxxxxxxx-xxxxxxxxxxxxx.luna.akamaiapis.net").
+		Put("/cprg/v1/cpcodes/12345").
+		BodyString(`"cpcodeName":"new-name".*"purgeable":true`).
+		Reply(200).
+		SetHeader("Content-Type", "application/json").
+		BodyString(`{
+			"cpcodeId": 12345,
+			"cpcodeName": "new-name",
+			"purgeable": true,
+			"contracts": [{"contractId": "C-1", "status": "ongoing"}],
+			"products": [{"productId": "SPM", "productName": "Ion Premier"}]
+		}`)
+
+	Init(config)
+
+	cpcode := NewCpCode(NewCpCodes(nil, nil))
+	cpcode.CpcodeID = "cpc_12345"
+	cpcode.CpcodeName = "old-name"
+
+	assert.NoError(t, cpcode.Rename("new-name", ""))
+	assert.True(t, gock.IsDone())
+	assert.Equal(t, "new-name", cpcode.CpcodeName)
+	assert.Equal(t, []string{"SPM"}, cpcode.ProductIDs)
+}
@@ -0,0 +1,219 @@
+package papi
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+)
+
+// CpCodeInventoryOptions chooses what GetCpCodeInventory includes
+type CpCodeInventoryOptions struct {
+	// Usage scans the rule trees of the properties in each scope for the
+	// properties using each CP Code
+	Usage bool
+	// Networks is passed to GetCpCodeUsage when Usage is set
+	Networks []NetworkValue
+	// Parallelism is the maximum number of concurrent requests. Defaults to
+	// DefaultLoadParallelism
+	Parallelism int
+}
+
+// CpCodeInventoryEntry is a CP Code in a contract and group
+type CpCodeInventoryEntry struct {
+	CpcodeID    string    `json:"cpcodeId"`
+	CpcodeName  string    `json:"cpcodeName"`
+	ContractID  string    `json:"contractId"`
+	GroupID     string    `json:"groupId"`
+	ProductIDs  []string  `json:"productIds"`
+	CreatedDate time.Time `json:"createdDate"`
+	// Usage is the rules using the CP Code, set when
+	// CpCodeInventoryOptions.Usage is set
+	Usage []*CpCodeUsage `json:"usage,omitempty"`
+	// PropertyIDs is the properties using the CP Code, sorted
+	PropertyIDs []string `json:"propertyIds,omitempty"`
+	// IncludeIDs is the includes using the CP Code, sorted
+	IncludeIDs []string `json:"includeIds,omitempty"`
+	// UsageComplete is set when Usage was scanned and every rule tree in the
+	// scopes could be fetched
+	UsageComplete bool `json:"usageComplete,omitempty"`
+}
+
+// CpCodeInventory is the CP Codes of a set of contracts and groups
+//
+// See: GetCpCodeInventory()
+type CpCodeInventory []*CpCodeInventoryEntry
+
+// GetCpCodeInventory lists the CP Codes of every scope, sorted by contract,
+// group and CP Code ID
+//
+// A CP Code is listed once for each scope it is found in. With options.Usage
+// the rule trees of the properties and includes in the scopes are scanned as
+// by GetCpCodeUsage, and the CP Codes not used by any of them are returned by
+// CpCodeInventory.Unused(). Usage in properties and includes outside the
+// scopes is not seen, so scopes should cover every group of the contracts
+// being tidied.
+//
+// Errors are returned as LoadErrors together with the entries that could be
+// fetched. When a rule tree could not be scanned, UsageComplete is not set and
+// Unused() fails.
+//
+//	inventory, err := papi.GetCpCodeInventory(scopes, &papi.CpCodeInventoryOptions{Usage: true}, "")
+//	unused, err := inventory.Unused()
+func GetCpCodeInventory(scopes []PropertyScope, options *CpCodeInventoryOptions, correlationid string) (CpCodeInventory, error) {
+	if options == nil {
+		options = &CpCodeInventoryOptions{}
+	}
+
+	loader := newLoader(options.Parallelism)
+
+	for _, scope := range scopes {
+		if scope.Group == nil {
+			return nil, errors.New("You must provide a group for each scope")
+		}
+	}
+
+	var mutex sync.Mutex
+	inventory := CpCodeInventory{}
+	for key, scope := range scopes {
+		scope := scope
+		loader.run(fmt.Sprintf("cpcodes:%d", key), func() error {
+			contract, err := scope.contract()
+			if err != nil {
+				return err
+			}
+
+			cpcodes := NewCpCodes(contract, scope.Group)
+			if err := cpcodes.GetCpCodes(correlationid); err != nil {
+				return fmt.Errorf("cpcodes of %s: %w", scope.Group.GroupID, err)
+			}
+
+			mutex.Lock()
+			defer mutex.Unlock()
+			for _, cpcode := range cpcodes.CpCodes.Items {
+				inventory = append(inventory, &CpCodeInventoryEntry{
+					CpcodeID:    cpcode.CpcodeID,
+					CpcodeName:  cpcode.CpcodeName,
+					ContractID:  cpcodes.ContractID,
+					GroupID:     cpcodes.GroupID,
+					ProductIDs:  cpcode.ProductIDs,
+					CreatedDate: cpcode.CreatedDate,
+				})
+			}
+			return nil
+		})
+	}
+	loader.wait()
+
+	sort.SliceStable(inventory, func(i, j int) bool {
+		if inventory[i].ContractID != inventory[j].ContractID {
+			return inventory[i].ContractID < inventory[j].ContractID
+		}
+		if inventory[i].GroupID != inventory[j].GroupID {
+			return inventory[i].GroupID < inventory[j].GroupID
+		}
+		return inventory[i].id() < inventory[j].id()
+	})
+
+	if !options.Usage {
+		return inventory, loader.err()
+	}
+
+	failed := loader.errorCount()
+
+	properties, err := loader.scopeProperties(scopes, correlationid)
+	if err != nil {
+		return nil, err
+	}
+
+	usages := loader.cpcodeUsage(scopes, properties, options.Networks, correlationid)
+	complete := loader.errorCount() == failed
+	for _, entry := range inventory {
+		cpcode := &CpCode{CpcodeID: entry.CpcodeID}
+		entry.Usage = usages[cpcode.ID()]
+		entry.PropertyIDs = usages.PropertyIDs(cpcode)
+		entry.IncludeIDs = usages.IncludeIDs(cpcode)
+		entry.UsageComplete = complete
+	}
+
+	return inventory, loader.err()
+}
+
+// id returns the integer ID of the entry's CP Code
+func (entry *CpCodeInventoryEntry) id() int {
+	return (&CpCode{CpcodeID: entry.CpcodeID}).ID()
+}
+
+// Unused returns the CP Codes not used by any scanned property or include
+// rule tree
+//
+// Only the latest and active versions are scanned, so a CP Code may still be
+// used by an older version that could be activated again. ErrUsageIncomplete
+// is returned if the inventory was fetched without
+// CpCodeInventoryOptions.Usage or a rule tree could not be scanned.
+func (inventory CpCodeInventory) Unused() (CpCodeInventory, error) {
+	unused := CpCodeInventory{}
+	for _, entry := range inventory {
+		if !entry.UsageComplete {
+			return nil, fmt.Errorf("%w: CP Code %s", ErrorMap[ErrUsageIncomplete], entry.CpcodeID)
+		}
+		if len(entry.Usage) == 0 {
+			unused = append(unused, entry)
+		}
+	}
+
+	return unused, nil
+}
+
+// WriteJSON writes the inventory as an indented JSON array
+func (inventory CpCodeInventory) WriteJSON(w io.Writer) error {
+	encoder := json.NewEncoder(w)
+	encoder.SetIndent("", "  ")
+
+	return encoder.Encode(inventory)
+}
+
+// cpcodeInventoryCSVHeader is the header row written by WriteCSV
+var cpcodeInventoryCSVHeader = []string{
+	"cpcodeId",
+	"cpcodeName",
+	"contractId",
+	"groupId",
+	"productIds",
+	"createdDate",
+	"propertyIds",
+	"includeIds",
+}
+
+// WriteCSV writes the inventory as CSV with a header row. Dates are RFC 3339,
+// and product, property and include IDs are separated by spaces.
+func (inventory CpCodeInventory) WriteCSV(w io.Writer) error {
+	writer := csv.NewWriter(w)
+	if err := writer.Write(cpcodeInventoryCSVHeader); err != nil {
+		return err
+	}
+
+	for _, entry := range inventory {
+		err := writer.Write([]string{
+			entry.CpcodeID,
+			entry.CpcodeName,
+			entry.ContractID,
+			entry.GroupID,
+			strings.Join(entry.ProductIDs, " "),
+			formatActivationDate(entry.CreatedDate),
+			strings.Join(entry.PropertyIDs, " "),
+			strings.Join(entry.IncludeIDs, " "),
+		})
+		if err != nil {
+			return err
+		}
+	}
+
+	writer.Flush()
+	return writer.Error()
+}
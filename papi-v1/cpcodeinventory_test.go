@@ -0,0 +1,228 @@
+package papi
+
+import (
+	"bytes"
+	"encoding/csv"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"gopkg.in/h2non/gock.v1"
+)
+
+func mockCpCodeInventory() []PropertyScope {
+	gock.New("https://akaa-baseurl-xxxxxxxxxxx-xxxxxxxxxxxxx.luna.akamaiapis.net").
+		Get("/papi/v1/cpcodes").
+		MatchParam("groupId", "grp_1").
+		Reply(200).
+		SetHeader("Content-Type", "application/json").
+		BodyString(`{"accountId": "act_1", "contractId": "ctr_1", "groupId": "grp_1", "cpcodes": {"items": [
+			{"cpcodeId": "cpc_300", "cpcodeName": "api", "productIds": ["prd_SPM"], "createdDate": "2021-03-01T10:00:00Z"},
+			{"cpcodeId": "cpc_100", "cpcodeName": "www", "productIds": ["prd_SPM", "prd_Site_Accel"], "createdDate": "2021-01-01T10:00:00Z"},
+			{"cpcodeId": "cpc_200", "cpcodeName": "orphan", "productIds": ["prd_SPM"], "createdDate": "2021-02-01T10:00:00Z"},
+			{"cpcodeId": "cpc_400", "cpcodeName": "failover", "productIds": ["prd_SPM"], "createdDate": "2021-04-01T10:00:00Z"},
+			{"cpcodeId": "cpc_500", "cpcodeName": "shared", "productIds": ["prd_SPM"], "createdDate": "2021-05-01T10:00:00Z"}
+		]}}`)
+
+	gock.New("https://akaa-baseurl-xxxxxxxxxxx-xxxxxxxxxxxxx.luna.akamaiapis.net").
+		Get("/papi/v1/includes").
+		MatchParam("groupId", "grp_1").
+		Reply(200).
+		SetHeader("Content-Type", "application/json").
+		BodyString(`{"includes": {"items": [
+			{"includeId": "inc_1", "includeName": "shared", "contractId": "ctr_1", "groupId": "grp_1", "latestVersion": 2, "productionVersion": 2}
+		]}}`)
+
+	gock.New("https://akaa-baseurl-xxxxxxxxxxx-xxxxxxxxxxxxx.luna.akamaiapis.net").
+		Get("/papi/v1/includes/inc_1/versions/2/rules").
+		Reply(200).
+		SetHeader("Content-Type", "application/json").
+		BodyString(`{"includeId": "inc_1", "includeVersion": 2, "rules": {
+			"name": "default",
+			"behaviors": [{"name": "cpCode", "options": {"value": {"id": 500, "name": "shared"}}}]
+		}}`)
+
+	gock.New("https://akaa-baseurl-xxxxxxxxxxx-xxxxxxxxxxxxx.luna.akamaiapis.net").
+		Get("/papi/v1/properties").
+		MatchParam("groupId", "grp_1").
+		Reply(200).
+		SetHeader("Content-Type", "application/json").
+		BodyString(`{"properties": {"items": [
+			{"propertyId": "prp_1", "propertyName": "www.example.com", "contractId": "ctr_1", "groupId": "grp_1", "latestVersion": 3}
+		]}}`)
+
+	gock.New("https://akaa-baseurl-xxxxxxxxxxx-xxxxxxxxxxxxx.luna.akamaiapis.net").
+		Get("/papi/v1/properties/prp_1/activations").
+		Reply(200).
+		SetHeader("Content-Type", "application/json").
+		BodyString(`{"activations": {"items": [
+			{"activationId": "atv_1", "propertyVersion": 2, "network": "PRODUCTION", "status": "ACTIVE", "activationType": "ACTIVATE"},
+			{"activationId": "atv_2", "propertyVersion": 3, "network": "STAGING", "status": "ACTIVE", "activationType": "ACTIVATE"}
+		]}}`)
+
+	mockCustomUsageRules("prp_1", "3", `{
+		"name": "default",
+		"behaviors": [{"name": "cpCode", "options": {"value": {"id": 100, "name": "www"}}}],
+		"children": [{
+			"name": "API",
+			"behaviors": [
+				{"name": "cpCode", "options": {"value": {"id": 300, "name": "api"}}},
+				{"name": "failAction", "options": {"enabled": true, "cpCode": {"id": 400}}}
+			]
+		}]
+	}`)
+
+	mockCustomUsageRules("prp_1", "2", `{
+		"name": "default",
+		"behaviors": [{"name": "cpCode", "options": {"value": {"id": 100, "name": "www"}}}]
+	}`)
+
+	contract := NewContract(NewContracts())
+	contract.ContractID = "ctr_1"
+	group := NewGroup(NewGroups())
+	group.GroupID = "grp_1"
+
+	return []PropertyScope{{Contract: contract, Group: group}}
+}
+
+func TestGetCpCodeInventory(t *testing.T) {
+	defer gock.Off()
+	ResponseCache = NewMemoryCache()
+
+	scopes := mockCpCodeInventory()
+
+	Init(config)
+
+	inventory, err := GetCpCodeInventory(scopes, &CpCodeInventoryOptions{Usage: true}, "")
+	assert.NoError(t, err)
+	assert.True(t, gock.IsDone())
+
+	var cpcodeIDs []string
+	for _, entry := range inventory {
+		cpcodeIDs = append(cpcodeIDs, entry.CpcodeID)
+	}
+	assert.Equal(t, []string{"cpc_100", "cpc_200", "cpc_300", "cpc_400", "cpc_500"}, cpcodeIDs)
+
+	www := inventory[0]
+	assert.Equal(t, "ctr_1", www.ContractID)
+	assert.Equal(t, "grp_1", www.GroupID)
+	assert.Equal(t, []string{"prd_SPM", "prd_Site_Accel"}, www.ProductIDs)
+	assert.Equal(t, []string{"prp_1"}, www.PropertyIDs)
+	assert.Len(t, www.Usage, 2)
+
+	failover := inventory[3]
+	assert.Len(t, failover.Usage, 1)
+	assert.Equal(t, "failAction", failover.Usage[0].Behavior)
+	assert.Equal(t, "default/API", failover.Usage[0].Path)
+	assert.Equal(t, 3, failover.Usage[0].PropertyVersion)
+
+	shared := inventory[4]
+	assert.Empty(t, shared.PropertyIDs)
+	assert.Equal(t, []string{"inc_1"}, shared.IncludeIDs)
+	assert.Equal(t, "shared", shared.Usage[0].IncludeName)
+
+	unused, err := inventory.Unused()
+	assert.NoError(t, err)
+	assert.Len(t, unused, 1)
+	assert.Equal(t, "orphan", unused[0].CpcodeName)
+}
+
+func TestGetCpCodeInventory_Incomplete(t *testing.T) {
+	defer gock.Off()
+	ResponseCache = NewMemoryCache()
+
+	gock.New("https://akaa-baseurl-xxxxxxxxxxx-xxxxxxxxxxxxx.luna.akamaiapis.net").
+		Get("/papi/v1/cpcodes").
+		MatchParam("groupId", "grp_1").
+		Reply(200).
+		SetHeader("Content-Type", "application/json").
+		BodyString(`{"contractId": "ctr_1", "groupId": "grp_1", "cpcodes": {"items": [
+			{"cpcodeId": "cpc_100", "cpcodeName": "www", "productIds": ["prd_SPM"], "createdDate": "2021-01-01T10:00:00Z"}
+		]}}`)
+
+	gock.New("https://akaa-baseurl-xxxxxxxxxxx-xxxxxxxxxxxxx.luna.akamaiapis.net").
+		Get("/papi/v1/properties").
+		MatchParam("groupId", "grp_1").
+		ReplyError(errors.New("connection reset"))
+
+	gock.New("https://akaa-baseurl-xxxxxxxxxxx-xxxxxxxxxxxxx.luna.akamaiapis.net").
+		Get("/papi/v1/includes").
+		MatchParam("groupId", "grp_1").
+		Reply(200).
+		SetHeader("Content-Type", "application/json").
+		BodyString(`{"includes": {"items": []}}`)
+
+	Init(config)
+
+	contract := NewContract(NewContracts())
+	contract.ContractID = "ctr_1"
+	group := NewGroup(NewGroups())
+	group.GroupID = "grp_1"
+
+	inventory, err := GetCpCodeInventory([]PropertyScope{{Contract: contract, Group: group}}, &CpCodeInventoryOptions{Usage: true}, "")
+	assert.Contains(t, err.Error(), "properties of grp_1:")
+	assert.Len(t, inventory, 1)
+	assert.False(t, inventory[0].UsageComplete)
+
+	_, err = inventory.Unused()
+	assert.True(t, errors.Is(err, ErrorMap[ErrUsageIncomplete]))
+}
+
+func TestGetCpCodeInventory_NoContract(t *testing.T) {
+	defer gock.Off()
+	ResponseCache = NewMemoryCache()
+
+	Init(config)
+
+	group := NewGroup(NewGroups())
+	group.GroupID = "grp_1"
+
+	inventory, err := GetCpCodeInventory([]PropertyScope{{Group: group}}, &CpCodeInventoryOptions{Usage: true}, "")
+	assert.Error(t, err)
+	assert.Contains(t, err.Error(), "You must provide a contract for group grp_1")
+	assert.Empty(t, inventory)
+}
+
+func TestGetCpCodeUsage(t *testing.T) {
+	defer gock.Off()
+	ResponseCache = NewMemoryCache()
+
+	scopes := mockCpCodeInventory()
+
+	Init(config)
+
+	usages, err := GetCpCodeUsage(scopes, &CpCodeUsageOptions{Networks: []NetworkValue{NetworkProduction}}, "")
+	assert.NoError(t, err)
+
+	cpcode := &CpCode{CpcodeID: "cpc_100"}
+	assert.Equal(t, []string{"prp_1"}, usages.PropertyIDs(cpcode))
+	assert.Len(t, usages[100], 2)
+	assert.Len(t, usages[300], 1)
+	assert.Empty(t, usages[200])
+}
+
+func TestCpCodeInventory_WriteCSV(t *testing.T) {
+	inventory := CpCodeInventory{
+		{
+			CpcodeID:    "cpc_100",
+			CpcodeName:  "www",
+			ContractID:  "ctr_1",
+			GroupID:     "grp_1",
+			ProductIDs:  []string{"prd_SPM", "prd_Site_Accel"},
+			CreatedDate: time.Date(2021, 1, 1, 10, 0, 0, 0, time.UTC),
+			PropertyIDs: []string{"prp_1", "prp_2"},
+		},
+	}
+
+	var buffer bytes.Buffer
+	assert.NoError(t, inventory.WriteCSV(&buffer))
+
+	records, err := csv.NewReader(&buffer).ReadAll()
+	assert.NoError(t, err)
+	assert.Len(t, records, 2)
+	assert.Equal(t, "cpcodeId", records[0][0])
+	assert.Equal(t, []string{
+		"cpc_100", "www", "ctr_1", "grp_1", "prd_SPM prd_Site_Accel", "2021-01-01T10:00:00Z", "prp_1 prp_2", "",
+	}, records[1])
+}
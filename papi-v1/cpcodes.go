@@ -1,7 +1,6 @@
 package papi
 
 import (
-	"encoding/json"
 	"fmt"
 
 	"strconv"
@@ -10,7 +9,6 @@ import (
 
 	"github.com/akamai/AkamaiOPEN-edgegrid-golang/client-v1"
 	edge "github.com/akamai/AkamaiOPEN-edgegrid-golang/edgegrid"
-	"github.com/patrickmn/go-cache"
 )
 
 // CpCodes represents a collection of CP Codes
@@ -38,8 +36,8 @@ func NewCpCodes(contract *Contract, group *Group) *CpCodes {
 }
 
 // PostUnmarshalJSON is called after UnmarshalJSON to setup the
-// structs internal state. The cpcodes.Complete channel is utilized
-// to communicate full completion.
+// structs internal state. Only the IDs of the Contract and Group are
+// set; use CpCodes.Load() to fetch them.
 func (cpcodes *CpCodes) PostUnmarshalJSON() error {
 	cpcodes.Init()
 
@@ -49,14 +47,7 @@ func (cpcodes *CpCodes) PostUnmarshalJSON() error {
 	cpcodes.Group = NewGroup(NewGroups())
 	cpcodes.Group.GroupID = cpcodes.GroupID
 
-	go cpcodes.Group.GetGroup()
-	go cpcodes.Contract.GetContract()
-
-	go (func(cpcodes *CpCodes) {
-		contractComplete := <-cpcodes.Contract.Complete
-		groupComplete := <-cpcodes.Group.Complete
-		cpcodes.Complete <- (contractComplete && groupComplete)
-	})(cpcodes)
+	signalComplete(cpcodes.Complete, true)
 
 	for key, cpcode := range cpcodes.CpCodes.Items {
 		cpcodes.CpCodes.Items[key].parent = cpcodes
@@ -74,50 +65,20 @@ func (cpcodes *CpCodes) PostUnmarshalJSON() error {
 // API Docs: https://developer.akamai.com/api/luna/papi/resources.html#listcpcodes
 // Endpoint: GET /papi/v1/cpcodes/{?contractId,groupId}
 func (cpcodes *CpCodes) GetCpCodes(correlationid string) error {
-	cachecpcodes, found := Profilecache.Get("cpcodes")
-	if found {
-		json.Unmarshal(cachecpcodes.([]byte), cpcodes)
-		return nil
-	} else {
-		if cpcodes.Contract == nil {
-			cpcodes.Contract = NewContract(NewContracts())
-			cpcodes.Contract.ContractID = cpcodes.Group.ContractIDs[0]
-		}
-
-		req, err := client.NewRequest(
-			Config,
-			"GET",
-			fmt.Sprintf(
-				"/papi/v1/cpcodes?groupId=%s&contractId=%s",
-				cpcodes.Group.GroupID,
-				cpcodes.Contract.ContractID,
-			),
-			nil,
-		)
-		if err != nil {
-			return err
-		}
-
-		edge.PrintHttpRequestCorrelation(req, true, correlationid)
-
-		res, err := client.Do(Config, req)
-		if err != nil {
-			return err
-		}
-
-		edge.PrintHttpResponseCorrelation(res, true, correlationid)
-
-		if client.IsError(res) {
-			return client.NewAPIError(res)
-		}
-
-		if err = client.BodyJSON(res, cpcodes); err != nil {
-			return err
-		}
-		byt, _ := json.Marshal(cpcodes)
-		Profilecache.Set("cpcodes", byt, cache.DefaultExpiration)
-		return nil
+	if cpcodes.Contract == nil {
+		cpcodes.Contract = NewContract(NewContracts())
+		cpcodes.Contract.ContractID = cpcodes.Group.ContractIDs[0]
 	}
+
+	return getCached(
+		fmt.Sprintf(
+			"/papi/v1/cpcodes?groupId=%s&contractId=%s",
+			cpcodes.Group.GroupID,
+			cpcodes.Contract.ContractID,
+		),
+		cpcodes,
+		correlationid,
+	)
 }
 
 func (cpcodes *CpCodes) FindCpCode(nameOrId string, correlationid string) (*CpCode, error) {
@@ -245,8 +206,8 @@ func (cpcode *CpCode) ID() int {
 	return id
 }
 
-// Save will create a new CP Code. You cannot update a CP Code;
-// trying to do so will result in an error.
+// Save will create a new CP Code. PAPI cannot update a CP Code; use
+// CpCode.Rename() or CpCode.Update() instead.
 //
 // API Docs: https://developer.akamai.com/api/luna/papi/resources.html#createanewcpcode
 // Endpoint: POST /papi/v1/cpcodes/{?contractId,groupId}
@@ -278,6 +239,10 @@ func (cpcode *CpCode) Save(correlationid string) error {
 		return client.NewAPIError(res)
 	}
 
+	if err = invalidateCache("/papi/v1/cpcodes"); err != nil {
+		return err
+	}
+
 	var location client.JSONBody
 	if err = client.BodyJSON(res, &location); err != nil {
 		return err
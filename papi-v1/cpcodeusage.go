@@ -0,0 +1,182 @@
+package papi
+
+import (
+	"sort"
+	"strings"
+)
+
+// CpCodeUsageOptions chooses the rule trees scanned by GetCpCodeUsage
+type CpCodeUsageOptions struct {
+	// Networks scans the property and include versions active on the
+	// networks as well as the latest versions. Defaults to both networks, so
+	// that a CP Code is only reported unused when no version that is or may
+	// be activated uses it
+	Networks []NetworkValue
+	// Parallelism is the maximum number of concurrent requests. Defaults to
+	// DefaultLoadParallelism
+	Parallelism int
+}
+
+// CpCodeUsage is a rule using a CP Code
+//
+// The rule is in a property rule tree, or, when IncludeID is set, in an
+// include rule tree. PropertyVersion is then the include version.
+type CpCodeUsage struct {
+	PropertyID      string `json:"propertyId,omitempty"`
+	PropertyName    string `json:"propertyName,omitempty"`
+	IncludeID       string `json:"includeId,omitempty"`
+	IncludeName     string `json:"includeName,omitempty"`
+	ContractID      string `json:"contractId"`
+	GroupID         string `json:"groupId"`
+	PropertyVersion int    `json:"propertyVersion"`
+	// Path is the path of the rule, as passed to a RuleWalkFunc
+	Path string `json:"path"`
+	// Behavior is the name of the behavior referencing the CP Code
+	Behavior string `json:"behavior"`
+}
+
+// CpCodeUsages maps integer CP Code IDs, as returned by CpCode.ID(), to the
+// rules using them
+//
+// See: GetCpCodeUsage()
+type CpCodeUsages map[int][]*CpCodeUsage
+
+// PropertyIDs returns the IDs of the properties using a CP Code, sorted
+func (usages CpCodeUsages) PropertyIDs(cpcode *CpCode) []string {
+	return usages.ids(cpcode, func(usage *CpCodeUsage) string { return usage.PropertyID })
+}
+
+// IncludeIDs returns the IDs of the includes using a CP Code, sorted
+func (usages CpCodeUsages) IncludeIDs(cpcode *CpCode) []string {
+	return usages.ids(cpcode, func(usage *CpCodeUsage) string { return usage.IncludeID })
+}
+
+// ids returns the distinct, non-empty IDs of the usages of a CP Code, sorted
+func (usages CpCodeUsages) ids(cpcode *CpCode, id func(usage *CpCodeUsage) string) []string {
+	seen := map[string]bool{}
+	ids := []string{}
+	for _, usage := range usages[cpcode.ID()] {
+		if value := id(usage); value != "" && !seen[value] {
+			seen[value] = true
+			ids = append(ids, value)
+		}
+	}
+	sort.Strings(ids)
+
+	return ids
+}
+
+// GetCpCodeUsage scans the rule trees of every property and include in the
+// scopes for behaviors referencing CP Codes
+//
+// The latest version of each property and include is scanned, along with the
+// versions active on options.Networks. Older versions that are not active are
+// not scanned. A CP Code is referenced by the value option of the cpCode
+// behavior, and by any behavior option whose name contains "cpCode", such as
+// the cpCode option of failAction.
+//
+// Requests are made with at most options.Parallelism at a time. Errors are
+// returned as LoadErrors together with the usage found in the rule trees that
+// could be fetched.
+//
+//	usages, err := papi.GetCpCodeUsage(scopes, nil, "")
+//	fmt.Println(usages.PropertyIDs(cpcode))
+func GetCpCodeUsage(scopes []PropertyScope, options *CpCodeUsageOptions, correlationid string) (CpCodeUsages, error) {
+	if options == nil {
+		options = &CpCodeUsageOptions{}
+	}
+
+	loader := newLoader(options.Parallelism)
+
+	properties, err := loader.scopeProperties(scopes, correlationid)
+	if err != nil {
+		return nil, err
+	}
+
+	usages := loader.cpcodeUsage(scopes, properties, options.Networks, correlationid)
+
+	return usages, loader.err()
+}
+
+// cpcodeUsage scans the rule trees of properties, and of the includes of
+// scopes, for CP Codes
+func (loader *loader) cpcodeUsage(scopes []PropertyScope, properties []*Property, networks []NetworkValue, correlationid string) CpCodeUsages {
+	if len(networks) == 0 {
+		networks = []NetworkValue{NetworkStaging, NetworkProduction}
+	}
+	scan := ruleScan{latest: true, networks: networks}
+
+	usages := CpCodeUsages{}
+	loader.scanRules(properties, scan, correlationid, func(property *Property, version int, path string, rule *Rule) {
+		usages.add(rule, &CpCodeUsage{
+			PropertyID:      property.PropertyID,
+			PropertyName:    property.PropertyName,
+			ContractID:      property.ContractID,
+			GroupID:         property.GroupID,
+			PropertyVersion: version,
+			Path:            path,
+		})
+	})
+
+	includes := loader.scopeIncludes(scopes, correlationid)
+	loader.scanIncludeRules(includes, scan, correlationid, func(include *Include, version int, path string, rule *Rule) {
+		usages.add(rule, &CpCodeUsage{
+			IncludeID:       include.IncludeID,
+			IncludeName:     include.IncludeName,
+			ContractID:      include.ContractID,
+			GroupID:         include.GroupID,
+			PropertyVersion: version,
+			Path:            path,
+		})
+	})
+
+	return usages
+}
+
+// add records the CP Codes referenced by the behaviors of rule, each with a
+// copy of usage naming the behavior
+func (usages CpCodeUsages) add(rule *Rule, usage *CpCodeUsage) {
+	for _, behavior := range rule.Behaviors {
+		seen := map[int]bool{}
+		for _, id := range behaviorCpCodes(behavior) {
+			if seen[id] {
+				continue
+			}
+			seen[id] = true
+
+			behaviorUsage := *usage
+			behaviorUsage.Behavior = behavior.Name
+			usages[id] = append(usages[id], &behaviorUsage)
+		}
+	}
+}
+
+// behaviorCpCodes returns the IDs of the CP Codes referenced by a behavior
+func behaviorCpCodes(behavior *Behavior) []int {
+	var ids []int
+	for name, value := range behavior.Options {
+		if behavior.Name == "cpCode" && name == "value" || strings.Contains(strings.ToLower(name), "cpcode") {
+			if id, ok := optionCpCode(value); ok {
+				ids = append(ids, id)
+			}
+		}
+	}
+	sort.Ints(ids)
+
+	return ids
+}
+
+// optionCpCode returns the ID of a CP Code option value, {"id": 12345, ...}
+func optionCpCode(value interface{}) (int, bool) {
+	cpcode, ok := value.(map[string]interface{})
+	if !ok {
+		return 0, false
+	}
+
+	id, ok := cpcode["id"].(float64)
+	if !ok || id == 0 {
+		return 0, false
+	}
+
+	return int(id), true
+}
@@ -0,0 +1,110 @@
+// Package criteria provides typed PAPI criteria for a subset of rule format
+// v2021-09-22
+//
+// Each criterion is a struct with a field per option, and enum options are
+// typed constants, so option names and values are checked by the compiler
+// instead of failing at papi.Rules.Save():
+//
+//	path := &criteria.Path{
+//		MatchOperator: criteria.PathMatchOperatorMatchesOneOf,
+//		Values:        []string{"/static/*"},
+//	}
+//	rule.MergeCriteria(path.ToCriteria())
+//
+// Options left empty are omitted, so PAPI applies their defaults. Boolean and
+// number options are pointers so that false and 0 can be set.
+//
+// Only contentType, fileExtension, hostname, matchVariable, path,
+// requestMethod and requestProtocol are covered. They are generated for
+// RuleFormat by papi-v1/internal/gen from an excerpt of its schema, see
+// go:generate below. Other criteria are built as *papi.Criteria, and
+// FromCriteria returns an error for them.
+package criteria
+
+//go:generate go run ../internal/gen -kind criteria -format v2021-09-22 -schema ../internal/gen/schemas/v2021-09-22-excerpt.json -out criteria_gen.go
+
+import (
+	"encoding/json"
+	"fmt"
+
+	papi "github.com/akamai/AkamaiOPEN-edgegrid-golang/papi-v1"
+)
+
+// Criteria is a typed criterion
+type Criteria interface {
+	// Name returns the criterion name, e.g. path
+	Name() string
+	// ToCriteria converts the criterion to a *papi.Criteria
+	ToCriteria() *papi.Criteria
+	// FromCriteria sets the options from a *papi.Criteria with the same name
+	FromCriteria(from *papi.Criteria) error
+}
+
+// FromCriteria converts a *papi.Criteria to its typed criterion
+//
+// Options unknown to RuleFormat, and the criterion's UUID and Locked, are not
+// kept.
+func FromCriteria(criteria *papi.Criteria) (Criteria, error) {
+	builder, ok := builders[criteria.Name]
+	if !ok {
+		return nil, fmt.Errorf("criteria %s is not generated for rule format %s", criteria.Name, RuleFormat)
+	}
+
+	typed := builder()
+	if err := typed.FromCriteria(criteria); err != nil {
+		return nil, err
+	}
+
+	return typed, nil
+}
+
+// Bool returns a pointer to value, for boolean options
+func Bool(value bool) *bool {
+	return &value
+}
+
+// Int returns a pointer to value, for integer options
+func Int(value int) *int {
+	return &value
+}
+
+// Float64 returns a pointer to value, for number options
+func Float64(value float64) *float64 {
+	return &value
+}
+
+// newCriteria creates a *papi.Criteria with the options of a typed criterion
+func newCriteria(name string, options interface{}) *papi.Criteria {
+	criteria := papi.NewCriteria()
+	criteria.Name = name
+
+	// Generated structs only hold JSON types, so this fails only if the
+	// generator is broken
+	data, err := json.Marshal(options)
+	if err != nil {
+		panic(fmt.Sprintf("criteria %s: %v", name, err))
+	}
+	if err := json.Unmarshal(data, &criteria.Options); err != nil {
+		panic(fmt.Sprintf("criteria %s: %v", name, err))
+	}
+
+	return criteria
+}
+
+// fromCriteria sets the options of a typed criterion from a *papi.Criteria
+func fromCriteria(criteria *papi.Criteria, name string, options interface{}) error {
+	if criteria.Name != name {
+		return fmt.Errorf("cannot convert criteria %s to %s", criteria.Name, name)
+	}
+
+	data, err := json.Marshal(criteria.Options)
+	if err != nil {
+		return err
+	}
+
+	if err := json.Unmarshal(data, options); err != nil {
+		return fmt.Errorf("criteria %s: %w", name, err)
+	}
+
+	return nil
+}
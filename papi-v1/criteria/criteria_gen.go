@@ -0,0 +1,285 @@
+// Code generated by papi-v1/internal/gen from rule format v2021-09-22. DO NOT EDIT.
+
+package criteria
+
+import papi "github.com/akamai/AkamaiOPEN-edgegrid-golang/papi-v1"
+
+// RuleFormat is the rule format the criteria were generated from
+const RuleFormat = "v2021-09-22"
+
+// ContentType is the contentType criteria
+type ContentType struct {
+	MatchCaseSensitive *bool                         `json:"matchCaseSensitive,omitempty"`
+	MatchOperator      ContentTypeMatchOperatorValue `json:"matchOperator,omitempty"`
+	MatchWildcard      *bool                         `json:"matchWildcard,omitempty"`
+	Values             []string                      `json:"values,omitempty"`
+}
+
+// Name returns the criteria name, contentType
+func (criteria *ContentType) Name() string {
+	return "contentType"
+}
+
+// ToCriteria converts the criteria to a *papi.Criteria
+func (criteria *ContentType) ToCriteria() *papi.Criteria {
+	return newCriteria("contentType", criteria)
+}
+
+// FromCriteria sets the options from a *papi.Criteria named contentType
+func (criteria *ContentType) FromCriteria(from *papi.Criteria) error {
+	return fromCriteria(from, "contentType", criteria)
+}
+
+// ContentTypeMatchOperatorValue is used to create an "enum" of possible ContentType.MatchOperator values
+type ContentTypeMatchOperatorValue string
+
+const (
+	// ContentTypeMatchOperatorIsOneOf ContentType.MatchOperator value IS_ONE_OF
+	ContentTypeMatchOperatorIsOneOf ContentTypeMatchOperatorValue = "IS_ONE_OF"
+	// ContentTypeMatchOperatorIsNotOneOf ContentType.MatchOperator value IS_NOT_ONE_OF
+	ContentTypeMatchOperatorIsNotOneOf ContentTypeMatchOperatorValue = "IS_NOT_ONE_OF"
+)
+
+// FileExtension is the fileExtension criteria
+type FileExtension struct {
+	MatchCaseSensitive *bool                           `json:"matchCaseSensitive,omitempty"`
+	MatchOperator      FileExtensionMatchOperatorValue `json:"matchOperator,omitempty"`
+	Values             []string                        `json:"values,omitempty"`
+}
+
+// Name returns the criteria name, fileExtension
+func (criteria *FileExtension) Name() string {
+	return "fileExtension"
+}
+
+// ToCriteria converts the criteria to a *papi.Criteria
+func (criteria *FileExtension) ToCriteria() *papi.Criteria {
+	return newCriteria("fileExtension", criteria)
+}
+
+// FromCriteria sets the options from a *papi.Criteria named fileExtension
+func (criteria *FileExtension) FromCriteria(from *papi.Criteria) error {
+	return fromCriteria(from, "fileExtension", criteria)
+}
+
+// FileExtensionMatchOperatorValue is used to create an "enum" of possible FileExtension.MatchOperator values
+type FileExtensionMatchOperatorValue string
+
+const (
+	// FileExtensionMatchOperatorIsOneOf FileExtension.MatchOperator value IS_ONE_OF
+	FileExtensionMatchOperatorIsOneOf FileExtensionMatchOperatorValue = "IS_ONE_OF"
+	// FileExtensionMatchOperatorIsNotOneOf FileExtension.MatchOperator value IS_NOT_ONE_OF
+	FileExtensionMatchOperatorIsNotOneOf FileExtensionMatchOperatorValue = "IS_NOT_ONE_OF"
+)
+
+// Hostname is the hostname criteria
+type Hostname struct {
+	MatchOperator HostnameMatchOperatorValue `json:"matchOperator,omitempty"`
+	Values        []string                   `json:"values,omitempty"`
+}
+
+// Name returns the criteria name, hostname
+func (criteria *Hostname) Name() string {
+	return "hostname"
+}
+
+// ToCriteria converts the criteria to a *papi.Criteria
+func (criteria *Hostname) ToCriteria() *papi.Criteria {
+	return newCriteria("hostname", criteria)
+}
+
+// FromCriteria sets the options from a *papi.Criteria named hostname
+func (criteria *Hostname) FromCriteria(from *papi.Criteria) error {
+	return fromCriteria(from, "hostname", criteria)
+}
+
+// HostnameMatchOperatorValue is used to create an "enum" of possible Hostname.MatchOperator values
+type HostnameMatchOperatorValue string
+
+const (
+	// HostnameMatchOperatorIsOneOf Hostname.MatchOperator value IS_ONE_OF
+	HostnameMatchOperatorIsOneOf HostnameMatchOperatorValue = "IS_ONE_OF"
+	// HostnameMatchOperatorIsNotOneOf Hostname.MatchOperator value IS_NOT_ONE_OF
+	HostnameMatchOperatorIsNotOneOf HostnameMatchOperatorValue = "IS_NOT_ONE_OF"
+)
+
+// MatchVariable is the matchVariable criteria
+type MatchVariable struct {
+	LowerBound         string                          `json:"lowerBound,omitempty"`
+	MatchCaseSensitive *bool                           `json:"matchCaseSensitive,omitempty"`
+	MatchOperator      MatchVariableMatchOperatorValue `json:"matchOperator,omitempty"`
+	MatchWildcard      *bool                           `json:"matchWildcard,omitempty"`
+	UpperBound         string                          `json:"upperBound,omitempty"`
+	VariableExpression string                          `json:"variableExpression,omitempty"`
+	VariableName       string                          `json:"variableName,omitempty"`
+	VariableValues     []string                        `json:"variableValues,omitempty"`
+}
+
+// Name returns the criteria name, matchVariable
+func (criteria *MatchVariable) Name() string {
+	return "matchVariable"
+}
+
+// ToCriteria converts the criteria to a *papi.Criteria
+func (criteria *MatchVariable) ToCriteria() *papi.Criteria {
+	return newCriteria("matchVariable", criteria)
+}
+
+// FromCriteria sets the options from a *papi.Criteria named matchVariable
+func (criteria *MatchVariable) FromCriteria(from *papi.Criteria) error {
+	return fromCriteria(from, "matchVariable", criteria)
+}
+
+// MatchVariableMatchOperatorValue is used to create an "enum" of possible MatchVariable.MatchOperator values
+type MatchVariableMatchOperatorValue string
+
+const (
+	// MatchVariableMatchOperatorIs MatchVariable.MatchOperator value IS
+	MatchVariableMatchOperatorIs MatchVariableMatchOperatorValue = "IS"
+	// MatchVariableMatchOperatorIsNot MatchVariable.MatchOperator value IS_NOT
+	MatchVariableMatchOperatorIsNot MatchVariableMatchOperatorValue = "IS_NOT"
+	// MatchVariableMatchOperatorIsOneOf MatchVariable.MatchOperator value IS_ONE_OF
+	MatchVariableMatchOperatorIsOneOf MatchVariableMatchOperatorValue = "IS_ONE_OF"
+	// MatchVariableMatchOperatorIsNotOneOf MatchVariable.MatchOperator value IS_NOT_ONE_OF
+	MatchVariableMatchOperatorIsNotOneOf MatchVariableMatchOperatorValue = "IS_NOT_ONE_OF"
+	// MatchVariableMatchOperatorIsEmpty MatchVariable.MatchOperator value IS_EMPTY
+	MatchVariableMatchOperatorIsEmpty MatchVariableMatchOperatorValue = "IS_EMPTY"
+	// MatchVariableMatchOperatorIsNotEmpty MatchVariable.MatchOperator value IS_NOT_EMPTY
+	MatchVariableMatchOperatorIsNotEmpty MatchVariableMatchOperatorValue = "IS_NOT_EMPTY"
+	// MatchVariableMatchOperatorIsBetween MatchVariable.MatchOperator value IS_BETWEEN
+	MatchVariableMatchOperatorIsBetween MatchVariableMatchOperatorValue = "IS_BETWEEN"
+	// MatchVariableMatchOperatorIsNotBetween MatchVariable.MatchOperator value IS_NOT_BETWEEN
+	MatchVariableMatchOperatorIsNotBetween MatchVariableMatchOperatorValue = "IS_NOT_BETWEEN"
+	// MatchVariableMatchOperatorIsGreaterThan MatchVariable.MatchOperator value IS_GREATER_THAN
+	MatchVariableMatchOperatorIsGreaterThan MatchVariableMatchOperatorValue = "IS_GREATER_THAN"
+	// MatchVariableMatchOperatorIsGreaterThanOrEqualTo MatchVariable.MatchOperator value IS_GREATER_THAN_OR_EQUAL_TO
+	MatchVariableMatchOperatorIsGreaterThanOrEqualTo MatchVariableMatchOperatorValue = "IS_GREATER_THAN_OR_EQUAL_TO"
+	// MatchVariableMatchOperatorIsLessThan MatchVariable.MatchOperator value IS_LESS_THAN
+	MatchVariableMatchOperatorIsLessThan MatchVariableMatchOperatorValue = "IS_LESS_THAN"
+	// MatchVariableMatchOperatorIsLessThanOrEqualTo MatchVariable.MatchOperator value IS_LESS_THAN_OR_EQUAL_TO
+	MatchVariableMatchOperatorIsLessThanOrEqualTo MatchVariableMatchOperatorValue = "IS_LESS_THAN_OR_EQUAL_TO"
+)
+
+// Path is the path criteria
+type Path struct {
+	MatchCaseSensitive *bool                  `json:"matchCaseSensitive,omitempty"`
+	MatchOperator      PathMatchOperatorValue `json:"matchOperator,omitempty"`
+	Normalize          *bool                  `json:"normalize,omitempty"`
+	Values             []string               `json:"values,omitempty"`
+}
+
+// Name returns the criteria name, path
+func (criteria *Path) Name() string {
+	return "path"
+}
+
+// ToCriteria converts the criteria to a *papi.Criteria
+func (criteria *Path) ToCriteria() *papi.Criteria {
+	return newCriteria("path", criteria)
+}
+
+// FromCriteria sets the options from a *papi.Criteria named path
+func (criteria *Path) FromCriteria(from *papi.Criteria) error {
+	return fromCriteria(from, "path", criteria)
+}
+
+// PathMatchOperatorValue is used to create an "enum" of possible Path.MatchOperator values
+type PathMatchOperatorValue string
+
+const (
+	// PathMatchOperatorMatchesOneOf Path.MatchOperator value MATCHES_ONE_OF
+	PathMatchOperatorMatchesOneOf PathMatchOperatorValue = "MATCHES_ONE_OF"
+	// PathMatchOperatorDoesNotMatchOneOf Path.MatchOperator value DOES_NOT_MATCH_ONE_OF
+	PathMatchOperatorDoesNotMatchOneOf PathMatchOperatorValue = "DOES_NOT_MATCH_ONE_OF"
+)
+
+// RequestMethod is the requestMethod criteria
+type RequestMethod struct {
+	MatchOperator RequestMethodMatchOperatorValue `json:"matchOperator,omitempty"`
+	Value         RequestMethodValue              `json:"value,omitempty"`
+}
+
+// Name returns the criteria name, requestMethod
+func (criteria *RequestMethod) Name() string {
+	return "requestMethod"
+}
+
+// ToCriteria converts the criteria to a *papi.Criteria
+func (criteria *RequestMethod) ToCriteria() *papi.Criteria {
+	return newCriteria("requestMethod", criteria)
+}
+
+// FromCriteria sets the options from a *papi.Criteria named requestMethod
+func (criteria *RequestMethod) FromCriteria(from *papi.Criteria) error {
+	return fromCriteria(from, "requestMethod", criteria)
+}
+
+// RequestMethodMatchOperatorValue is used to create an "enum" of possible RequestMethod.MatchOperator values
+type RequestMethodMatchOperatorValue string
+
+const (
+	// RequestMethodMatchOperatorIs RequestMethod.MatchOperator value IS
+	RequestMethodMatchOperatorIs RequestMethodMatchOperatorValue = "IS"
+	// RequestMethodMatchOperatorIsNot RequestMethod.MatchOperator value IS_NOT
+	RequestMethodMatchOperatorIsNot RequestMethodMatchOperatorValue = "IS_NOT"
+)
+
+// RequestMethodValue is used to create an "enum" of possible RequestMethod.Value values
+type RequestMethodValue string
+
+const (
+	// RequestMethodValueGet RequestMethod.Value value GET
+	RequestMethodValueGet RequestMethodValue = "GET"
+	// RequestMethodValuePost RequestMethod.Value value POST
+	RequestMethodValuePost RequestMethodValue = "POST"
+	// RequestMethodValueHead RequestMethod.Value value HEAD
+	RequestMethodValueHead RequestMethodValue = "HEAD"
+	// RequestMethodValuePut RequestMethod.Value value PUT
+	RequestMethodValuePut RequestMethodValue = "PUT"
+	// RequestMethodValuePatch RequestMethod.Value value PATCH
+	RequestMethodValuePatch RequestMethodValue = "PATCH"
+	// RequestMethodValueHTTPDelete RequestMethod.Value value HTTP_DELETE
+	RequestMethodValueHTTPDelete RequestMethodValue = "HTTP_DELETE"
+	// RequestMethodValueOptions RequestMethod.Value value OPTIONS
+	RequestMethodValueOptions RequestMethodValue = "OPTIONS"
+)
+
+// RequestProtocol is the requestProtocol criteria
+type RequestProtocol struct {
+	Value RequestProtocolValue `json:"value,omitempty"`
+}
+
+// Name returns the criteria name, requestProtocol
+func (criteria *RequestProtocol) Name() string {
+	return "requestProtocol"
+}
+
+// ToCriteria converts the criteria to a *papi.Criteria
+func (criteria *RequestProtocol) ToCriteria() *papi.Criteria {
+	return newCriteria("requestProtocol", criteria)
+}
+
+// FromCriteria sets the options from a *papi.Criteria named requestProtocol
+func (criteria *RequestProtocol) FromCriteria(from *papi.Criteria) error {
+	return fromCriteria(from, "requestProtocol", criteria)
+}
+
+// RequestProtocolValue is used to create an "enum" of possible RequestProtocol.Value values
+type RequestProtocolValue string
+
+const (
+	// RequestProtocolValueHTTP RequestProtocol.Value value HTTP
+	RequestProtocolValueHTTP RequestProtocolValue = "HTTP"
+	// RequestProtocolValueHTTPS RequestProtocol.Value value HTTPS
+	RequestProtocolValueHTTPS RequestProtocolValue = "HTTPS"
+)
+
+var builders = map[string]func() Criteria{
+	"contentType":     func() Criteria { return &ContentType{} },
+	"fileExtension":   func() Criteria { return &FileExtension{} },
+	"hostname":        func() Criteria { return &Hostname{} },
+	"matchVariable":   func() Criteria { return &MatchVariable{} },
+	"path":            func() Criteria { return &Path{} },
+	"requestMethod":   func() Criteria { return &RequestMethod{} },
+	"requestProtocol": func() Criteria { return &RequestProtocol{} },
+}
@@ -0,0 +1,37 @@
+package criteria
+
+import (
+	"testing"
+
+	papi "github.com/akamai/AkamaiOPEN-edgegrid-golang/papi-v1"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestPath_ToCriteria(t *testing.T) {
+	path := &Path{
+		MatchOperator: PathMatchOperatorMatchesOneOf,
+		Values:        []string{"/static/*"},
+	}
+
+	criteria := path.ToCriteria()
+
+	assert.Equal(t, "path", criteria.Name)
+	assert.Equal(t, papi.OptionValue{
+		"matchOperator": "MATCHES_ONE_OF",
+		"values":        []interface{}{"/static/*"},
+	}, criteria.Options)
+}
+
+func TestFromCriteria(t *testing.T) {
+	criteria := papi.NewCriteria()
+	criteria.Name = "requestMethod"
+	criteria.Options = papi.OptionValue{"matchOperator": "IS", "value": "HTTP_DELETE"}
+
+	typed, err := FromCriteria(criteria)
+	assert.NoError(t, err)
+	assert.Equal(t, &RequestMethod{MatchOperator: RequestMethodMatchOperatorIs, Value: RequestMethodValueHTTPDelete}, typed)
+
+	criteria.Name = "notACriteria"
+	_, err = FromCriteria(criteria)
+	assert.EqualError(t, err, "criteria notACriteria is not generated for rule format v2021-09-22")
+}
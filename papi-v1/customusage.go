@@ -0,0 +1,113 @@
+package papi
+
+import "sort"
+
+// CustomUsageOptions chooses the rule trees scanned by GetCustomUsage
+type CustomUsageOptions struct {
+	// Network scans the version active on the network instead of the latest
+	// version. Properties not active on the network are skipped
+	Network NetworkValue
+	// Parallelism is the maximum number of concurrent requests. Defaults to
+	// DefaultLoadParallelism
+	Parallelism int
+}
+
+// CustomUsage is a rule using a custom behavior or custom override
+type CustomUsage struct {
+	PropertyID      string `json:"propertyId"`
+	PropertyName    string `json:"propertyName"`
+	ContractID      string `json:"contractId"`
+	GroupID         string `json:"groupId"`
+	PropertyVersion int    `json:"propertyVersion"`
+	// Path is the path of the rule, as passed to a RuleWalkFunc
+	Path string `json:"path"`
+}
+
+// CustomUsages maps custom behavior or custom override IDs to the rules
+// using them
+type CustomUsages map[string][]*CustomUsage
+
+// PropertyIDs returns the IDs of the properties using a custom behavior or
+// custom override, sorted
+func (usages CustomUsages) PropertyIDs(id string) []string {
+	seen := map[string]bool{}
+	propertyIDs := []string{}
+	for _, usage := range usages[id] {
+		if !seen[usage.PropertyID] {
+			seen[usage.PropertyID] = true
+			propertyIDs = append(propertyIDs, usage.PropertyID)
+		}
+	}
+	sort.Strings(propertyIDs)
+
+	return propertyIDs
+}
+
+// CustomUsageReport is where custom behaviors and custom overrides are used
+//
+// See: GetCustomUsage()
+type CustomUsageReport struct {
+	// Behaviors is keyed by CustomBehavior.BehaviorID
+	Behaviors CustomUsages `json:"behaviors"`
+	// Overrides is keyed by CustomOverride.OverrideID
+	Overrides CustomUsages `json:"overrides"`
+	// Scanned is the number of rule trees scanned
+	Scanned int `json:"scanned"`
+}
+
+// GetCustomUsage scans the rule trees of every property in the scopes for
+// customBehavior behaviors and custom overrides
+//
+// The latest version of each property is scanned, or the version active on
+// options.Network. Requests are made with at most options.Parallelism at a
+// time. Errors are returned as LoadErrors together with the usage found in
+// the rule trees that could be fetched.
+//
+//	report, err := papi.GetCustomUsage(scopes, nil, "")
+//	for _, usage := range report.Behaviors[behavior.BehaviorID] {
+//		fmt.Println(usage.PropertyName, usage.Path)
+//	}
+func GetCustomUsage(scopes []PropertyScope, options *CustomUsageOptions, correlationid string) (*CustomUsageReport, error) {
+	if options == nil {
+		options = &CustomUsageOptions{}
+	}
+
+	loader := newLoader(options.Parallelism)
+
+	properties, err := loader.scopeProperties(scopes, correlationid)
+	if err != nil {
+		return nil, err
+	}
+
+	scan := ruleScan{latest: true}
+	if options.Network != "" {
+		scan = ruleScan{networks: []NetworkValue{options.Network}}
+	}
+
+	report := &CustomUsageReport{Behaviors: CustomUsages{}, Overrides: CustomUsages{}}
+	report.Scanned = loader.scanRules(properties, scan, correlationid, func(property *Property, version int, path string, rule *Rule) {
+		usage := &CustomUsage{
+			PropertyID:      property.PropertyID,
+			PropertyName:    property.PropertyName,
+			ContractID:      property.ContractID,
+			GroupID:         property.GroupID,
+			PropertyVersion: version,
+			Path:            path,
+		}
+
+		if rule.CustomOverride != nil && rule.CustomOverride.OverrideID != "" {
+			report.Overrides[rule.CustomOverride.OverrideID] = append(report.Overrides[rule.CustomOverride.OverrideID], usage)
+		}
+
+		for _, behavior := range rule.Behaviors {
+			if behavior.Name != "customBehavior" {
+				continue
+			}
+			if behaviorID, ok := behavior.Options["behaviorId"].(string); ok {
+				report.Behaviors[behaviorID] = append(report.Behaviors[behaviorID], usage)
+			}
+		}
+	})
+
+	return report, loader.err()
+}
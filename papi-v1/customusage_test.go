@@ -0,0 +1,151 @@
+package papi
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"gopkg.in/h2non/gock.v1"
+)
+
+func mockCustomUsage() []PropertyScope {
+	gock.New("https://akaa-baseurl-xxxxxxxxxxx-xxxxxxxxxxxxx.luna.akamaiapis.net").
+		Get("/papi/v1/properties").
+		MatchParam("groupId", "grp_1").
+		Reply(200).
+		SetHeader("Content-Type", "application/json").
+		BodyString(`{"properties": {"items": [
+			{"propertyId": "prp_1", "propertyName": "www.example.com", "contractId": "ctr_1", "groupId": "grp_1", "latestVersion": 3},
+			{"propertyId": "prp_2", "propertyName": "www.example.org", "contractId": "ctr_1", "groupId": "grp_1", "latestVersion": 5},
+			{"propertyId": "prp_3", "propertyName": "api.example.com", "contractId": "ctr_1", "groupId": "grp_1", "latestVersion": 1}
+		]}}`)
+
+	contract := NewContract(NewContracts())
+	contract.ContractID = "ctr_1"
+	group := NewGroup(NewGroups())
+	group.GroupID = "grp_1"
+
+	return []PropertyScope{{Contract: contract, Group: group}}
+}
+
+func mockCustomUsageRules(propertyID string, version string, rules string) {
+	gock.New("https://akaa-baseurl-xxxxxxxxxxx-xxxxxxxxxxxxx.luna.akamaiapis.net").
+		Get("/papi/v1/properties/"+propertyID+"/versions/"+version+"/rules").
+		Reply(200).
+		SetHeader("Content-Type", "application/json").
+		BodyString(`{"propertyId": "` + propertyID + `", "rules": ` + rules + `}`)
+}
+
+func TestGetCustomUsage(t *testing.T) {
+	defer gock.Off()
+
+	scopes := mockCustomUsage()
+
+	mockCustomUsageRules("prp_1", "3", `{
+		"name": "default",
+		"customOverride": {"overrideId": "cbo_1", "name": "mdc"},
+		"children": [{
+			"name": "Legacy",
+			"behaviors": [
+				{"name": "customBehavior", "options": {"behaviorId": "cbe_1"}},
+				{"name": "caching", "options": {"behavior": "NO_STORE"}}
+			]
+		}]
+	}`)
+
+	mockCustomUsageRules("prp_2", "5", `{
+		"name": "default",
+		"behaviors": [{"name": "customBehavior", "options": {"behaviorId": "cbe_1"}}],
+		"children": [{
+			"name": "API",
+			"behaviors": [{"name": "customBehavior", "options": {"behaviorId": "cbe_2"}}]
+		}]
+	}`)
+
+	gock.New("https://akaa-baseurl-xxxxxxxxxxx-xxxxxxxxxxxxx.luna.akamaiapis.net").
+		Get("/papi/v1/properties/prp_3/versions/1/rules").
+		Reply(500).
+		SetHeader("Content-Type", "application/problem+json").
+		BodyString(`{"title": "Internal Server Error", "status": 500}`)
+
+	Init(config)
+
+	report, err := GetCustomUsage(scopes, &CustomUsageOptions{Parallelism: 2}, "")
+
+	var loadErrors LoadErrors
+	assert.True(t, errors.As(err, &loadErrors))
+	assert.Len(t, loadErrors, 1)
+	assert.Contains(t, err.Error(), "property prp_3: version 1: rules")
+
+	assert.Equal(t, 2, report.Scanned)
+	assert.Equal(t, []string{"prp_1", "prp_2"}, report.Behaviors.PropertyIDs("cbe_1"))
+	assert.Equal(t, []string{"prp_2"}, report.Behaviors.PropertyIDs("cbe_2"))
+	assert.Equal(t, []string{}, report.Behaviors.PropertyIDs("cbe_3"))
+
+	usage := report.Behaviors["cbe_2"][0]
+	assert.Equal(t, "www.example.org", usage.PropertyName)
+	assert.Equal(t, 5, usage.PropertyVersion)
+	assert.Equal(t, "default/API", usage.Path)
+
+	assert.Len(t, report.Overrides["cbo_1"], 1)
+	assert.Equal(t, "default", report.Overrides["cbo_1"][0].Path)
+}
+
+func TestGetCustomUsage_Network(t *testing.T) {
+	defer gock.Off()
+
+	scopes := mockCustomUsage()
+
+	gock.New("https://akaa-baseurl-xxxxxxxxxxx-xxxxxxxxxxxxx.luna.akamaiapis.net").
+		Get("/papi/v1/properties/prp_1/activations").
+		Reply(200).
+		SetHeader("Content-Type", "application/json").
+		BodyString(`{"activations": {"items": [
+			{"activationId": "atv_1", "propertyVersion": 2, "network": "PRODUCTION", "status": "ACTIVE", "activationType": "ACTIVATE"}
+		]}}`)
+
+	for _, propertyID := range []string{"prp_2", "prp_3"} {
+		gock.New("https://akaa-baseurl-xxxxxxxxxxx-xxxxxxxxxxxxx.luna.akamaiapis.net").
+			Get("/papi/v1/properties/"+propertyID+"/activations").
+			Reply(200).
+			SetHeader("Content-Type", "application/json").
+			BodyString(`{"activations": {"items": []}}`)
+	}
+
+	mockCustomUsageRules("prp_1", "2", `{
+		"name": "default",
+		"behaviors": [{"name": "customBehavior", "options": {"behaviorId": "cbe_1"}}]
+	}`)
+
+	Init(config)
+
+	report, err := GetCustomUsage(scopes, &CustomUsageOptions{Network: NetworkProduction}, "")
+
+	assert.NoError(t, err)
+	assert.True(t, gock.IsDone())
+	assert.Equal(t, 1, report.Scanned)
+	assert.Equal(t, 2, report.Behaviors["cbe_1"][0].PropertyVersion)
+}
+
+func TestGetCustomUsage_PropertiesError(t *testing.T) {
+	defer gock.Off()
+
+	gock.New("https://akaa-baseurl-xxxxxxxxxxx-xxxxxxxxxxxxx.luna.akamaiapis.net").
+		Get("/papi/v1/properties").
+		MatchParam("groupId", "grp_1").
+		ReplyError(errors.New("connection reset"))
+
+	Init(config)
+
+	contract := NewContract(NewContracts())
+	contract.ContractID = "ctr_1"
+	group := NewGroup(NewGroups())
+	group.GroupID = "grp_1"
+
+	report, err := GetCustomUsage([]PropertyScope{{Contract: contract, Group: group}}, &CustomUsageOptions{Parallelism: 4}, "")
+
+	var loadErrors LoadErrors
+	assert.True(t, errors.As(err, &loadErrors))
+	assert.Contains(t, err.Error(), "properties of grp_1:")
+	assert.Equal(t, 0, report.Scanned)
+}
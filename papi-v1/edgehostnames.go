@@ -1,7 +1,6 @@
 package papi
 
 import (
-	"encoding/json"
 	"errors"
 	"fmt"
 	"net/url"
@@ -10,7 +9,6 @@ import (
 
 	"github.com/akamai/AkamaiOPEN-edgegrid-golang/client-v1"
 	edge "github.com/akamai/AkamaiOPEN-edgegrid-golang/edgegrid"
-	"github.com/patrickmn/go-cache"
 )
 
 // EdgeHostnames is a collection for PAPI Edge Hostname resources
@@ -62,62 +60,29 @@ func (edgeHostnames *EdgeHostnames) NewEdgeHostname() *EdgeHostname {
 // API Docs: https://developer.akamai.com/api/luna/papi/resources.html#listedgehostnames
 // Endpoint: GET /papi/v1/edgehostnames/{?contractId,groupId,options}
 func (edgeHostnames *EdgeHostnames) GetEdgeHostnames(contract *Contract, group *Group, options string, correlationid string) error {
-
 	if contract == nil && group == nil {
 		return errors.New("function requires at least \"group\" argument")
 	}
 
-	cacheedgehostnames, found := Profilecache.Get("edgehostnames")
-	if found {
-		json.Unmarshal(cacheedgehostnames.([]byte), edgeHostnames)
-		return nil
-	} else {
-
-		if contract == nil && group != nil {
-			contract = NewContract(NewContracts())
-			contract.ContractID = group.ContractIDs[0]
-		}
-
-		if options != "" {
-			options = fmt.Sprintf("&options=%s", options)
-		}
-
-		req, err := client.NewRequest(
-			Config,
-			"GET",
-			fmt.Sprintf(
-				"/papi/v1/edgehostnames?groupId=%s&contractId=%s%s",
-				group.GroupID,
-				contract.ContractID,
-				options,
-			),
-			nil,
-		)
-		if err != nil {
-			return err
-		}
-
-		edge.PrintHttpRequestCorrelation(req, true, correlationid)
-
-		res, err := client.Do(Config, req)
-		if err != nil {
-			return err
-		}
-
-		edge.PrintHttpResponseCorrelation(res, true, correlationid)
-
-		if client.IsError(res) {
-			return client.NewAPIError(res)
-		}
-
-		if err = client.BodyJSON(res, edgeHostnames); err != nil {
-			return err
-		}
+	if contract == nil && group != nil {
+		contract = NewContract(NewContracts())
+		contract.ContractID = group.ContractIDs[0]
+	}
 
-		byt, _ := json.Marshal(edgeHostnames)
-		Profilecache.Set("edgehostnames", byt, cache.DefaultExpiration)
-		return nil
+	if options != "" {
+		options = fmt.Sprintf("&options=%s", options)
 	}
+
+	return getCached(
+		fmt.Sprintf(
+			"/papi/v1/edgehostnames?groupId=%s&contractId=%s%s",
+			group.GroupID,
+			contract.ContractID,
+			options,
+		),
+		edgeHostnames,
+		correlationid,
+	)
 }
 
 func (edgeHostnames *EdgeHostnames) FindEdgeHostname(edgeHostname *EdgeHostname) (*EdgeHostname, error) {
@@ -326,6 +291,9 @@ func (edgeHostname *EdgeHostname) Save(options string, correlationid string) err
 	}
 
 	edgeHostname.parent.AddEdgeHostname(edgeHostname)
+	if err = invalidateCache("/papi/v1/edgehostnames"); err != nil {
+		return err
+	}
 
 	return nil
 }
@@ -10,15 +10,25 @@ const (
 	ErrVariableNotFound
 	ErrRuleNotFound
 	ErrInvalidRules
+	ErrInvalidSelector
+	ErrActivationFailed
+	ErrHostnameActive
+	ErrWarningsNotAcknowledged
+	ErrUsageIncomplete
 )
 
 var (
 	ErrorMap = map[int]error{
-		ErrInvalidPath:      errors.New("Invalid Path"),
-		ErrCriteriaNotFound: errors.New("Criteria not found"),
-		ErrBehaviorNotFound: errors.New("Behavior not found"),
-		ErrVariableNotFound: errors.New("Variable not found"),
-		ErrRuleNotFound:     errors.New("Rule not found"),
-		ErrInvalidRules:     errors.New("Rule validation failed. See papi.Rules.Errors for details"),
+		ErrInvalidPath:             errors.New("Invalid Path"),
+		ErrCriteriaNotFound:        errors.New("Criteria not found"),
+		ErrBehaviorNotFound:        errors.New("Behavior not found"),
+		ErrVariableNotFound:        errors.New("Variable not found"),
+		ErrRuleNotFound:            errors.New("Rule not found"),
+		ErrInvalidRules:            errors.New("Rule validation failed. See papi.Rules.Errors for details"),
+		ErrInvalidSelector:         errors.New("Invalid selector"),
+		ErrActivationFailed:        errors.New("Activation failed"),
+		ErrHostnameActive:          errors.New("Hostname is active in production"),
+		ErrWarningsNotAcknowledged: errors.New("Activation warnings were not acknowledged"),
+		ErrUsageIncomplete:         errors.New("Usage was not scanned completely"),
 	}
 )
@@ -1,12 +1,9 @@
 package papi
 
 import (
-	"encoding/json"
 	"fmt"
 
 	"github.com/akamai/AkamaiOPEN-edgegrid-golang/client-v1"
-	edge "github.com/akamai/AkamaiOPEN-edgegrid-golang/edgegrid"
-	"github.com/patrickmn/go-cache"
 )
 
 // Groups represents a collection of PAPI groups
@@ -47,41 +44,7 @@ func (groups *Groups) PostUnmarshalJSON() error {
 // API Docs: https://developer.akamai.com/api/luna/papi/resources.html#listgroups
 // Endpoint: GET /papi/v1/groups/
 func (groups *Groups) GetGroups(correlationid string) error {
-	cachegroups, found := Profilecache.Get("groups")
-	if found {
-		json.Unmarshal(cachegroups.([]byte), groups)
-		return nil
-	} else {
-		req, err := client.NewRequest(
-			Config,
-			"GET",
-			"/papi/v1/groups",
-			nil,
-		)
-		if err != nil {
-			return err
-		}
-
-		edge.PrintHttpRequestCorrelation(req, true, correlationid)
-
-		res, err := client.Do(Config, req)
-		if err != nil {
-			return err
-		}
-
-		edge.PrintHttpResponseCorrelation(res, true, correlationid)
-
-		if client.IsError(res) {
-			return client.NewAPIError(res)
-		}
-
-		if err = client.BodyJSON(res, groups); err != nil {
-			return err
-		}
-		byt, _ := json.Marshal(groups)
-		Profilecache.Set("groups", byt, cache.DefaultExpiration)
-		return nil
-	}
+	return getCached("/papi/v1/groups", groups, correlationid)
 }
 
 // AddGroup adds a group to a Groups collection
@@ -151,6 +114,8 @@ err:
 }
 
 // FindGroupsByName finds groups by name
+//
+// See: GroupTree.FindGroupByPath() to find a group by its full path
 func (groups *Groups) FindGroupsByName(name string) ([]*Group, error) {
 	var group *Group
 	var foundGroups []*Group
@@ -207,12 +172,12 @@ func (group *Group) GetGroup() {
 			group.ContractIDs = g.ContractIDs
 			group.GroupName = g.GroupName
 			group.ParentGroupID = g.ParentGroupID
-			group.Complete <- true
+			signalComplete(group.Complete, true)
 			return
 		}
 	}
 
-	group.Complete <- false
+	signalComplete(group.Complete, false)
 }
 
 // GetProperties retrieves all properties associated with a given group and contract
@@ -0,0 +1,229 @@
+package papi
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// GroupPathSeparator separates group names in a group path, e.g.
+// Acme > Web > Marketing
+const GroupPathSeparator = " > "
+
+// SkipGroup is returned by a GroupWalkFunc to skip the children of the group
+var SkipGroup = errors.New("skip this group")
+
+// GroupWalkFunc is called by GroupTree.Walk and GroupNode.Walk for each group
+type GroupWalkFunc func(node *GroupNode) error
+
+// GroupTree is the group hierarchy of a Groups collection
+//
+// Groups whose parent is not in the collection, because the account only
+// has access to part of the hierarchy, are roots.
+//
+// See: Groups.Tree()
+type GroupTree struct {
+	Roots []*GroupNode
+	nodes map[string]*GroupNode
+}
+
+// GroupNode is a group within a GroupTree
+type GroupNode struct {
+	Group    *Group
+	Parent   *GroupNode
+	Children []*GroupNode
+}
+
+// Tree builds the group hierarchy from the groups' ParentGroupID. Roots and
+// children are sorted by name.
+func (groups *Groups) Tree() *GroupTree {
+	tree := &GroupTree{nodes: map[string]*GroupNode{}}
+
+	for _, group := range groups.Groups.Items {
+		tree.nodes[group.GroupID] = &GroupNode{Group: group}
+	}
+
+	for _, group := range groups.Groups.Items {
+		node := tree.nodes[group.GroupID]
+		parent, ok := tree.nodes[group.ParentGroupID]
+		if !ok || group.ParentGroupID == "" || parent.isDescendantOf(node) {
+			tree.Roots = append(tree.Roots, node)
+			continue
+		}
+
+		node.Parent = parent
+		parent.Children = append(parent.Children, node)
+	}
+
+	sortGroupNodes(tree.Roots)
+	for _, node := range tree.nodes {
+		sortGroupNodes(node.Children)
+	}
+
+	return tree
+}
+
+func sortGroupNodes(nodes []*GroupNode) {
+	sort.SliceStable(nodes, func(i, j int) bool {
+		if nodes[i].Group.GroupName != nodes[j].Group.GroupName {
+			return nodes[i].Group.GroupName < nodes[j].Group.GroupName
+		}
+		return nodes[i].Group.GroupID < nodes[j].Group.GroupID
+	})
+}
+
+// FindGroup finds the node of a group by ID
+func (tree *GroupTree) FindGroup(id string) (*GroupNode, error) {
+	node, ok := tree.nodes[id]
+	if !ok {
+		return nil, fmt.Errorf("Unable to find group: \"%s\"", id)
+	}
+
+	return node, nil
+}
+
+// FindGroupByPath finds a group by the names of the groups leading to it,
+// starting at a root and separated by GroupPathSeparator, e.g.
+// "Acme > Web > Marketing"
+//
+// Whitespace around names is ignored, and names may contain ">" without
+// spaces around it. An error is returned if no group, or more than one group,
+// has the path.
+func (tree *GroupTree) FindGroupByPath(path string) (*GroupNode, error) {
+	names := strings.Split(path, GroupPathSeparator)
+	for key := range names {
+		names[key] = strings.TrimSpace(names[key])
+	}
+
+	candidates := tree.Roots
+	var found []*GroupNode
+	for depth, name := range names {
+		found = nil
+		for _, node := range candidates {
+			if node.Group.GroupName == name {
+				found = append(found, node)
+			}
+		}
+
+		if len(found) == 0 {
+			return nil, fmt.Errorf("Unable to find group: \"%s\"", strings.Join(names[:depth+1], GroupPathSeparator))
+		}
+
+		candidates = nil
+		for _, node := range found {
+			candidates = append(candidates, node.Children...)
+		}
+	}
+
+	if len(found) > 1 {
+		ids := make([]string, 0, len(found))
+		for _, node := range found {
+			ids = append(ids, node.Group.GroupID)
+		}
+		return nil, fmt.Errorf("Group path \"%s\" is ambiguous: %s", path, strings.Join(ids, ", "))
+	}
+
+	return found[0], nil
+}
+
+// Walk calls fn for every group in the tree, parents before their children
+//
+// If fn returns SkipGroup the group's children are skipped. Any other error
+// stops the walk and is returned.
+func (tree *GroupTree) Walk(fn GroupWalkFunc) error {
+	for _, root := range tree.Roots {
+		if err := root.Walk(fn); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// Walk calls fn for the group and every group below it, parents before
+// their children
+//
+// See: GroupTree.Walk()
+func (node *GroupNode) Walk(fn GroupWalkFunc) error {
+	err := fn(node)
+	if err == SkipGroup {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+
+	for _, child := range node.Children {
+		if err := child.Walk(fn); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// Subtree returns the group and every group below it, parents before their
+// children
+func (node *GroupNode) Subtree() []*GroupNode {
+	var nodes []*GroupNode
+	node.Walk(func(node *GroupNode) error {
+		nodes = append(nodes, node)
+		return nil
+	})
+
+	return nodes
+}
+
+// Ancestors returns the groups above the group, starting at its root
+func (node *GroupNode) Ancestors() []*GroupNode {
+	var ancestors []*GroupNode
+	for parent := node.Parent; parent != nil; parent = parent.Parent {
+		ancestors = append([]*GroupNode{parent}, ancestors...)
+	}
+
+	return ancestors
+}
+
+// Names returns the names of the groups from the root to the group
+func (node *GroupNode) Names() []string {
+	var names []string
+	for _, ancestor := range node.Ancestors() {
+		names = append(names, ancestor.Group.GroupName)
+	}
+
+	return append(names, node.Group.GroupName)
+}
+
+// Path returns the display path of the group, e.g. "Acme > Web > Marketing"
+func (node *GroupNode) Path() string {
+	return strings.Join(node.Names(), GroupPathSeparator)
+}
+
+// ContractIDs returns the contracts available to the group: its own,
+// followed by those inherited from its ancestors, nearest first
+func (node *GroupNode) ContractIDs() []string {
+	seen := map[string]bool{}
+	var contractIDs []string
+	for current := node; current != nil; current = current.Parent {
+		for _, contractID := range current.Group.ContractIDs {
+			if !seen[contractID] {
+				seen[contractID] = true
+				contractIDs = append(contractIDs, contractID)
+			}
+		}
+	}
+
+	return contractIDs
+}
+
+// isDescendantOf reports whether node is ancestor, or a group below it
+func (node *GroupNode) isDescendantOf(ancestor *GroupNode) bool {
+	for current := node; current != nil; current = current.Parent {
+		if current == ancestor {
+			return true
+		}
+	}
+
+	return false
+}
@@ -0,0 +1,124 @@
+package papi
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/akamai/AkamaiOPEN-edgegrid-golang/jsonhooks-v1"
+	"github.com/stretchr/testify/assert"
+)
+
+func newTestGroupTree(t *testing.T) *GroupTree {
+	groups := NewGroups()
+	err := jsonhooks.Unmarshal([]byte(`{"groups": {"items": [
+		{"groupId": "grp_5", "groupName": "Marketing", "parentGroupId": "grp_2", "contractIds": ["ctr_2"]},
+		{"groupId": "grp_1", "groupName": "Acme", "contractIds": ["ctr_1"]},
+		{"groupId": "grp_2", "groupName": "Web", "parentGroupId": "grp_1", "contractIds": ["ctr_1"]},
+		{"groupId": "grp_3", "groupName": "API", "parentGroupId": "grp_1", "contractIds": []},
+		{"groupId": "grp_6", "groupName": "Marketing", "parentGroupId": "grp_3"},
+		{"groupId": "grp_7", "groupName": "Shared", "parentGroupId": "grp_99", "contractIds": ["ctr_3"]}
+	]}}`), groups)
+	assert.NoError(t, err)
+
+	return groups.Tree()
+}
+
+func TestGroups_Tree(t *testing.T) {
+	tree := newTestGroupTree(t)
+
+	assert.Len(t, tree.Roots, 2)
+	assert.Equal(t, "Acme", tree.Roots[0].Group.GroupName)
+	assert.Equal(t, "Shared", tree.Roots[1].Group.GroupName)
+
+	acme := tree.Roots[0]
+	assert.Len(t, acme.Children, 2)
+	assert.Equal(t, "API", acme.Children[0].Group.GroupName)
+	assert.Equal(t, "Web", acme.Children[1].Group.GroupName)
+
+	marketing, err := tree.FindGroup("grp_5")
+	assert.NoError(t, err)
+	assert.Equal(t, "Acme > Web > Marketing", marketing.Path())
+	assert.Equal(t, "grp_2", marketing.Parent.Group.GroupID)
+	assert.Equal(t, []string{"Acme", "Web", "Marketing"}, marketing.Names())
+	assert.Equal(t, []string{"ctr_2", "ctr_1"}, marketing.ContractIDs())
+
+	apiMarketing, err := tree.FindGroup("grp_6")
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"ctr_1"}, apiMarketing.ContractIDs())
+
+	_, err = tree.FindGroup("grp_404")
+	assert.Error(t, err)
+}
+
+func TestGroupTree_FindGroupByPath(t *testing.T) {
+	tree := newTestGroupTree(t)
+
+	node, err := tree.FindGroupByPath("Acme > Web > Marketing")
+	assert.NoError(t, err)
+	assert.Equal(t, "grp_5", node.Group.GroupID)
+
+	node, err = tree.FindGroupByPath(" Acme > API > Marketing ")
+	assert.NoError(t, err)
+	assert.Equal(t, "grp_6", node.Group.GroupID)
+
+	_, err = tree.FindGroupByPath("Acme>API>Marketing")
+	assert.EqualError(t, err, `Unable to find group: "Acme>API>Marketing"`)
+
+	_, err = tree.FindGroupByPath("Acme > Mobile > Marketing")
+	assert.EqualError(t, err, `Unable to find group: "Acme > Mobile"`)
+}
+
+func TestGroupTree_FindGroupByPath_Separator(t *testing.T) {
+	groups := NewGroups()
+	groups.AddGroup(&Group{GroupID: "grp_1", GroupName: "Acme"})
+	groups.AddGroup(&Group{GroupID: "grp_2", GroupName: "Web>Mobile", ParentGroupID: "grp_1"})
+
+	node, err := groups.Tree().FindGroupByPath("Acme > Web>Mobile")
+	assert.NoError(t, err)
+	assert.Equal(t, "grp_2", node.Group.GroupID)
+}
+
+func TestGroupTree_FindGroupByPath_Ambiguous(t *testing.T) {
+	groups := NewGroups()
+	groups.AddGroup(&Group{GroupID: "grp_1", GroupName: "Acme"})
+	groups.AddGroup(&Group{GroupID: "grp_2", GroupName: "Web", ParentGroupID: "grp_1"})
+	groups.AddGroup(&Group{GroupID: "grp_3", GroupName: "Web", ParentGroupID: "grp_1"})
+
+	_, err := groups.Tree().FindGroupByPath("Acme > Web")
+	assert.EqualError(t, err, `Group path "Acme > Web" is ambiguous: grp_2, grp_3`)
+}
+
+func TestGroupTree_Walk(t *testing.T) {
+	tree := newTestGroupTree(t)
+
+	var paths []string
+	err := tree.Walk(func(node *GroupNode) error {
+		paths = append(paths, node.Path())
+		if node.Group.GroupName == "API" {
+			return SkipGroup
+		}
+		return nil
+	})
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"Acme", "Acme > API", "Acme > Web", "Acme > Web > Marketing", "Shared"}, paths)
+
+	web, _ := tree.FindGroupByPath("Acme > Web")
+	var ids []string
+	for _, node := range web.Subtree() {
+		ids = append(ids, node.Group.GroupID)
+	}
+	assert.Equal(t, []string{"grp_2", "grp_5"}, ids)
+
+	stop := errors.New("stop")
+	assert.Equal(t, stop, tree.Walk(func(node *GroupNode) error { return stop }))
+}
+
+func TestGroups_Tree_Cycle(t *testing.T) {
+	groups := NewGroups()
+	groups.AddGroup(&Group{GroupID: "grp_1", GroupName: "A", ParentGroupID: "grp_2"})
+	groups.AddGroup(&Group{GroupID: "grp_2", GroupName: "B", ParentGroupID: "grp_1"})
+
+	tree := groups.Tree()
+	assert.Len(t, tree.Roots, 1)
+	assert.Len(t, tree.Roots[0].Subtree(), 2)
+}
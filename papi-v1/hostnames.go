@@ -154,11 +154,12 @@ func (hostnames *Hostnames) Save() error {
 type Hostname struct {
 	client.Resource
 	parent           *Hostnames
-	CnameType        CnameTypeValue `json:"cnameType"`
-	EdgeHostnameID   string         `json:"edgeHostnameId,omitempty"`
-	CnameFrom        string         `json:"cnameFrom"`
-	CnameTo          string         `json:"cnameTo,omitempty"`
-	CertEnrollmentId string         `json:"certEnrollmentId,omitempty"`
+	CnameType            CnameTypeValue            `json:"cnameType"`
+	EdgeHostnameID       string                    `json:"edgeHostnameId,omitempty"`
+	CnameFrom            string                    `json:"cnameFrom"`
+	CnameTo              string                    `json:"cnameTo,omitempty"`
+	CertEnrollmentId     string                    `json:"certEnrollmentId,omitempty"`
+	CertProvisioningType CertProvisioningTypeValue `json:"certProvisioningType,omitempty"`
 }
 
 // NewHostname creates a new Hostname
@@ -176,3 +177,13 @@ const (
 	// CnameTypeEdgeHostname Hostname.CnameType value EDGE_HOSTNAME
 	CnameTypeEdgeHostname CnameTypeValue = "EDGE_HOSTNAME"
 )
+
+// CertProvisioningTypeValue is used to create an "enum" of possible Hostname.CertProvisioningType values
+type CertProvisioningTypeValue string
+
+const (
+	// CertProvisioningTypeCPSManaged Hostname.CertProvisioningType value CPS_MANAGED
+	CertProvisioningTypeCPSManaged CertProvisioningTypeValue = "CPS_MANAGED"
+	// CertProvisioningTypeDefault Hostname.CertProvisioningType value DEFAULT
+	CertProvisioningTypeDefault CertProvisioningTypeValue = "DEFAULT"
+)
@@ -0,0 +1,365 @@
+package papi
+
+import (
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/akamai/AkamaiOPEN-edgegrid-golang/client-v1"
+	edge "github.com/akamai/AkamaiOPEN-edgegrid-golang/edgegrid"
+)
+
+// HostnameChanges are the changes needed to make a property version's
+// hostnames match the desired hostnames
+type HostnameChanges struct {
+	// Add are new hostnames
+	Add []*Hostname
+	// Change are existing hostnames whose cnameTo or certificate provisioning
+	// type changed, with their desired values
+	Change []*Hostname
+	// Remove are hostnames that are no longer wanted
+	Remove []*Hostname
+	// CreateEdgeHostnames are the edge hostnames created, or that would be
+	// created, for hostnames in Add and Change
+	CreateEdgeHostnames []*EdgeHostname
+}
+
+// IsEmpty reports whether there are no changes
+func (changes *HostnameChanges) IsEmpty() bool {
+	return len(changes.Add) == 0 && len(changes.Change) == 0 && len(changes.Remove) == 0
+}
+
+// HostnameSyncOptions configures Property.SyncHostnames
+type HostnameSyncOptions struct {
+	// Force allows removing or changing hostnames that are active in
+	// production
+	Force bool
+	// DryRun computes the changes without creating edge hostnames or
+	// updating the version
+	DryRun bool
+	// ProductID is used when creating edge hostnames. Defaults to the
+	// property's product
+	ProductID string
+	// IPVersionBehavior is used when creating edge hostnames. Defaults to IPV4
+	IPVersionBehavior string
+}
+
+// DiffHostnames compares current hostnames with the desired hostnames, by
+// cnameFrom
+//
+// Changes are sorted by cnameFrom. A desired hostname without a certificate
+// provisioning type keeps the current one.
+func DiffHostnames(current, desired []*Hostname) *HostnameChanges {
+	changes := &HostnameChanges{}
+
+	currentByName := map[string]*Hostname{}
+	for _, hostname := range current {
+		currentByName[strings.ToLower(hostname.CnameFrom)] = hostname
+	}
+
+	desiredByName := map[string]*Hostname{}
+	for _, hostname := range desired {
+		name := strings.ToLower(hostname.CnameFrom)
+		desiredByName[name] = hostname
+
+		existing, ok := currentByName[name]
+		if !ok {
+			changes.Add = append(changes.Add, hostname)
+		} else if !strings.EqualFold(existing.CnameTo, hostname.CnameTo) ||
+			(hostname.CertProvisioningType != "" && hostname.CertProvisioningType != existing.CertProvisioningType) {
+			changes.Change = append(changes.Change, hostname)
+		}
+	}
+
+	for _, hostname := range current {
+		if _, ok := desiredByName[strings.ToLower(hostname.CnameFrom)]; !ok {
+			changes.Remove = append(changes.Remove, hostname)
+		}
+	}
+
+	sort.SliceStable(changes.Add, func(i, j int) bool {
+		return changes.Add[i].CnameFrom < changes.Add[j].CnameFrom
+	})
+	sort.SliceStable(changes.Change, func(i, j int) bool {
+		return changes.Change[i].CnameFrom < changes.Change[j].CnameFrom
+	})
+	sort.SliceStable(changes.Remove, func(i, j int) bool {
+		return changes.Remove[i].CnameFrom < changes.Remove[j].CnameFrom
+	})
+
+	return changes
+}
+
+// SyncHostnames makes the hostnames of an editable property version match
+// the desired hostnames, applying only the difference
+//
+// Each desired hostname needs a CnameFrom and a CnameTo naming its edge
+// hostname, e.g. "www.example.com.edgekey.net". Edge hostnames are looked up
+// by name in the property's contract and group, and created if they do not
+// exist, on Enhanced TLS for edgekey.net, Standard TLS for edgesuite.net and
+// the shared certificate for akamaized.net. A CPS_MANAGED hostname needs a
+// CertEnrollmentId to create an edgekey.net edge hostname, and a DEFAULT one
+// must not have one. Removing or changing a hostname that is on the version
+// active in production fails with ErrHostnameActive unless options.Force is
+// set.
+//
+// API Docs: https://developer.akamai.com/api/luna/papi/resources.html#patchpropertyversionhostnames
+// Endpoint: PATCH /papi/v1/properties/{propertyId}/versions/{propertyVersion}/hostnames{?contractId,groupId}
+func (property *Property) SyncHostnames(version int, desired []*Hostname, options *HostnameSyncOptions, correlationid string) (*HostnameChanges, error) {
+	if options == nil {
+		options = &HostnameSyncOptions{}
+	}
+
+	current, err := property.hostnamesForVersion(version, correlationid)
+	if err != nil {
+		return nil, err
+	}
+
+	changes := DiffHostnames(current.Hostnames.Items, desired)
+	if changes.IsEmpty() {
+		return changes, nil
+	}
+
+	if !options.Force {
+		guarded := append(append([]*Hostname{}, changes.Remove...), changes.Change...)
+		if err = property.checkProductionHostnames(guarded, correlationid); err != nil {
+			return nil, err
+		}
+	}
+
+	if err = property.resolveEdgeHostnames(changes, options, correlationid); err != nil {
+		return nil, err
+	}
+
+	if options.DryRun {
+		return changes, nil
+	}
+
+	if err = property.patchHostnames(version, changes, correlationid); err != nil {
+		return nil, err
+	}
+
+	return changes, nil
+}
+
+// hostnamesForVersion fetches the hostnames of a version of the property
+func (property *Property) hostnamesForVersion(version int, correlationid string) (*Hostnames, error) {
+	hostnames := NewHostnames()
+	hostnames.PropertyID = property.PropertyID
+	hostnames.ContractID = property.ContractID
+	hostnames.GroupID = property.GroupID
+
+	if err := hostnames.GetHostnames(&Version{PropertyVersion: version}, correlationid); err != nil {
+		return nil, err
+	}
+
+	return hostnames, nil
+}
+
+// checkProductionHostnames fails if any of the hostnames are on the version
+// active in production
+func (property *Property) checkProductionHostnames(hostnames []*Hostname, correlationid string) error {
+	if len(hostnames) == 0 {
+		return nil
+	}
+
+	productionVersion := property.ProductionVersion
+	if productionVersion == 0 {
+		latest := NewProperty(NewProperties())
+		latest.PropertyID = property.PropertyID
+		if err := latest.GetProperty(correlationid); err != nil {
+			return err
+		}
+		productionVersion = latest.ProductionVersion
+	}
+
+	if productionVersion == 0 {
+		return nil
+	}
+
+	production, err := property.hostnamesForVersion(productionVersion, correlationid)
+	if err != nil {
+		return err
+	}
+
+	active := map[string]bool{}
+	for _, hostname := range production.Hostnames.Items {
+		active[strings.ToLower(hostname.CnameFrom)] = true
+	}
+
+	var names []string
+	for _, hostname := range hostnames {
+		if active[strings.ToLower(hostname.CnameFrom)] {
+			names = append(names, hostname.CnameFrom)
+		}
+	}
+
+	if len(names) != 0 {
+		return fmt.Errorf("%w: %s (version %d)", ErrorMap[ErrHostnameActive], strings.Join(names, ", "), productionVersion)
+	}
+
+	return nil
+}
+
+// edgeHostnameSuffixes maps the domain suffixes of edge hostnames to their
+// secure networks
+var edgeHostnameSuffixes = map[string]string{
+	"edgekey.net":   "ENHANCED_TLS",
+	"edgesuite.net": "STANDARD_TLS",
+	"akamaized.net": "SHARED_CERT",
+}
+
+// splitEdgeHostname splits an edge hostname into its domain prefix and suffix
+func splitEdgeHostname(name string) (string, string, error) {
+	name = strings.ToLower(strings.TrimSuffix(name, "."))
+	for suffix := range edgeHostnameSuffixes {
+		prefix := strings.TrimSuffix(name, "."+suffix)
+		if prefix != name && prefix != "" {
+			return prefix, suffix, nil
+		}
+	}
+
+	return "", "", fmt.Errorf("%s is not an edgekey.net, edgesuite.net or akamaized.net edge hostname", name)
+}
+
+// resolveEdgeHostnames sets the edge hostname ID of each added or changed
+// hostname, creating edge hostnames that do not exist unless this is a dry run
+func (property *Property) resolveEdgeHostnames(changes *HostnameChanges, options *HostnameSyncOptions, correlationid string) error {
+	var edgeHostnames *EdgeHostnames
+	for _, hostname := range append(append([]*Hostname{}, changes.Add...), changes.Change...) {
+		if hostname.EdgeHostnameID != "" || hostname.CnameTo == "" {
+			continue
+		}
+
+		if edgeHostnames == nil {
+			contract := NewContract(NewContracts())
+			contract.ContractID = property.ContractID
+			group := NewGroup(NewGroups())
+			group.GroupID = property.GroupID
+
+			edgeHostnames = NewEdgeHostnames()
+			if err := edgeHostnames.GetEdgeHostnames(contract, group, "", correlationid); err != nil {
+				return err
+			}
+			edgeHostnames.ContractID = property.ContractID
+			edgeHostnames.GroupID = property.GroupID
+		}
+
+		prefix, suffix, err := splitEdgeHostname(hostname.CnameTo)
+		if err != nil {
+			return fmt.Errorf("hostname %s: %w", hostname.CnameFrom, err)
+		}
+
+		wanted := NewEdgeHostname(edgeHostnames)
+		wanted.EdgeHostnameDomain = hostname.CnameTo
+		wanted.DomainPrefix = prefix
+		wanted.DomainSuffix = suffix
+
+		var found *EdgeHostname
+		if len(edgeHostnames.EdgeHostnames.Items) != 0 {
+			if found, err = edgeHostnames.FindEdgeHostname(wanted); err != nil {
+				return err
+			}
+		}
+
+		if found != nil {
+			hostname.EdgeHostnameID = found.EdgeHostnameID
+			continue
+		}
+
+		wanted.SecureNetwork = edgeHostnameSuffixes[suffix]
+		wanted.ProductID = options.ProductID
+		if wanted.ProductID == "" {
+			wanted.ProductID = property.ProductID
+		}
+		wanted.IPVersionBehavior = options.IPVersionBehavior
+		if wanted.IPVersionBehavior == "" {
+			wanted.IPVersionBehavior = "IPV4"
+		}
+		if hostname.CertEnrollmentId != "" {
+			enrollmentID, err := strconv.Atoi(hostname.CertEnrollmentId)
+			if err != nil {
+				return fmt.Errorf("hostname %s: invalid certEnrollmentId %q", hostname.CnameFrom, hostname.CertEnrollmentId)
+			}
+			wanted.CertEnrollmentId = enrollmentID
+		}
+
+		switch hostname.CertProvisioningType {
+		case CertProvisioningTypeCPSManaged:
+			if suffix == "akamaized.net" {
+				return fmt.Errorf("hostname %s: CPS_MANAGED certificates cannot be used with akamaized.net edge hostnames", hostname.CnameFrom)
+			}
+			if suffix == "edgekey.net" && wanted.CertEnrollmentId == 0 {
+				return fmt.Errorf("hostname %s: CPS_MANAGED edgekey.net edge hostnames need a certEnrollmentId", hostname.CnameFrom)
+			}
+		case CertProvisioningTypeDefault:
+			if wanted.CertEnrollmentId != 0 {
+				return fmt.Errorf("hostname %s: DEFAULT certificates do not use a certEnrollmentId", hostname.CnameFrom)
+			}
+		}
+
+		changes.CreateEdgeHostnames = append(changes.CreateEdgeHostnames, wanted)
+
+		if options.DryRun {
+			continue
+		}
+
+		if err := wanted.Save("", correlationid); err != nil {
+			return fmt.Errorf("creating edge hostname %s: %w", hostname.CnameTo, err)
+		}
+		hostname.EdgeHostnameID = wanted.EdgeHostnameID
+	}
+
+	return nil
+}
+
+// patchHostnames adds, replaces and removes hostnames on a property version.
+// Changed hostnames are replaced by adding them again.
+func (property *Property) patchHostnames(version int, changes *HostnameChanges, correlationid string) error {
+	add := []*Hostname{}
+	for _, hostname := range append(append([]*Hostname{}, changes.Add...), changes.Change...) {
+		patchHostname := NewHostname(nil)
+		patchHostname.CnameFrom = hostname.CnameFrom
+		patchHostname.CnameTo = hostname.CnameTo
+		patchHostname.EdgeHostnameID = hostname.EdgeHostnameID
+		patchHostname.CertProvisioningType = hostname.CertProvisioningType
+		add = append(add, patchHostname)
+	}
+
+	remove := []string{}
+	for _, hostname := range changes.Remove {
+		remove = append(remove, hostname.CnameFrom)
+	}
+
+	req, err := client.NewJSONRequest(
+		Config,
+		"PATCH",
+		fmt.Sprintf(
+			"/papi/v1/properties/%s/versions/%d/hostnames?contractId=%s&groupId=%s",
+			property.PropertyID,
+			version,
+			property.ContractID,
+			property.GroupID,
+		),
+		map[string]interface{}{"add": add, "remove": remove},
+	)
+	if err != nil {
+		return err
+	}
+
+	edge.PrintHttpRequestCorrelation(req, true, correlationid)
+
+	res, err := client.Do(Config, req)
+	if err != nil {
+		return err
+	}
+
+	edge.PrintHttpResponseCorrelation(res, true, correlationid)
+
+	if client.IsError(res) {
+		return client.NewAPIError(res)
+	}
+
+	return nil
+}
@@ -0,0 +1,293 @@
+package papi
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"gopkg.in/h2non/gock.v1"
+)
+
+func testHostname(cnameFrom, cnameTo string) *Hostname {
+	hostname := NewHostname(nil)
+	hostname.CnameFrom = cnameFrom
+	hostname.CnameTo = cnameTo
+	return hostname
+}
+
+func mockSyncHostnames(version string, body string) {
+	gock.New("https://akaa-baseurl-xxxxxxxxxxx-xxxxxxxxxxxxx.luna.akamaiapis.net").
+		Get("/papi/v1/properties/prp_1/versions/"+version+"/hostnames/").
+		MatchParam("contractId", "ctr_1").
+		MatchParam("groupId", "grp_1").
+		Reply(200).
+		SetHeader("Content-Type", "application/json").
+		BodyString(`{"propertyId": "prp_1", "propertyVersion": ` + version + `, "hostnames": {"items": ` + body + `}}`)
+}
+
+func testSyncProperty() *Property {
+	property := NewProperty(NewProperties())
+	property.PropertyID = "prp_1"
+	property.ContractID = "ctr_1"
+	property.GroupID = "grp_1"
+	property.ProductID = "prd_Fresca"
+	property.ProductionVersion = 3
+	return property
+}
+
+func TestDiffHostnames(t *testing.T) {
+	current := []*Hostname{
+		testHostname("www.example.com", "www.example.com.edgesuite.net"),
+		testHostname("api.example.com", "api.example.com.edgesuite.net"),
+		testHostname("old.example.com", "old.example.com.edgesuite.net"),
+	}
+	desired := []*Hostname{
+		testHostname("WWW.example.com", "www.example.com.edgesuite.net"),
+		testHostname("api.example.com", "api.example.com.edgekey.net"),
+		testHostname("new.example.com", "new.example.com.edgesuite.net"),
+	}
+
+	changes := DiffHostnames(current, desired)
+	assert.Len(t, changes.Add, 1)
+	assert.Equal(t, "new.example.com", changes.Add[0].CnameFrom)
+	assert.Len(t, changes.Change, 1)
+	assert.Equal(t, "api.example.com", changes.Change[0].CnameFrom)
+	assert.Len(t, changes.Remove, 1)
+	assert.Equal(t, "old.example.com", changes.Remove[0].CnameFrom)
+
+	assert.True(t, DiffHostnames(current, current).IsEmpty())
+}
+
+func TestProperty_SyncHostnames(t *testing.T) {
+	defer gock.Off()
+	ResponseCache.Invalidate("")
+
+	mockSyncHostnames("4", `[
+		{"cnameType": "EDGE_HOSTNAME", "edgeHostnameId": "ehn_1", "cnameFrom": "www.example.com", "cnameTo": "www.example.com.edgesuite.net"},
+		{"cnameType": "EDGE_HOSTNAME", "edgeHostnameId": "ehn_2", "cnameFrom": "staging-only.example.com", "cnameTo": "staging-only.example.com.edgesuite.net"}
+	]`)
+	mockSyncHostnames("3", `[
+		{"cnameType": "EDGE_HOSTNAME", "edgeHostnameId": "ehn_1", "cnameFrom": "www.example.com", "cnameTo": "www.example.com.edgesuite.net"}
+	]`)
+
+	gock.New("https://akaa-baseurl-xxxxxxxxxxx-xxxxxxxxxxxxx.luna.akamaiapis.net").
+		Get("/papi/v1/edgehostnames").
+		MatchParam("contractId", "ctr_1").
+		MatchParam("groupId", "grp_1").
+		Reply(200).
+		SetHeader("Content-Type", "application/json").
+		BodyString(`{"edgeHostnames": {"items": [
+			{"edgeHostnameId": "ehn_3", "edgeHostnameDomain": "api.example.com.edgesuite.net", "domainPrefix": "api.example.com", "domainSuffix": "edgesuite.net"}
+		]}}`)
+
+	gock.New("https://akaa-baseurl-xxxxxxxxxxx-xxxxxxxxxxxxx.luna.akamaiapis.net").
+		Post("/papi/v1/edgehostnames/").
+		MatchType("json").
+		JSON(map[string]interface{}{
+			"productId":          "prd_Fresca",
+			"domainPrefix":       "secure.example.com",
+			"domainSuffix":       "edgekey.net",
+			"edgeHostnameDomain": "secure.example.com.edgekey.net",
+			"secureNetwork":      "ENHANCED_TLS",
+			"ipVersionBehavior":  "IPV4",
+			"certEnrollmentId":   1234,
+		}).
+		Reply(201).
+		SetHeader("Content-Type", "application/json").
+		BodyString(`{"edgeHostnameLink": "/papi/v1/edgehostnames/ehn_4?contractId=ctr_1&groupId=grp_1"}`)
+
+	gock.New("https://akaa-baseurl-xxxxxxxxxxx-xxxxxxxxxxxxx.luna.akamaiapis.net").
+		Patch("/papi/v1/properties/prp_1/versions/4/hostnames").
+		MatchType("json").
+		JSON(map[string]interface{}{
+			"add": []interface{}{
+				map[string]interface{}{"cnameType": "EDGE_HOSTNAME", "cnameFrom": "api.example.com", "cnameTo": "api.example.com.edgesuite.net", "edgeHostnameId": "ehn_3"},
+				map[string]interface{}{"cnameType": "EDGE_HOSTNAME", "cnameFrom": "secure.example.com", "cnameTo": "secure.example.com.edgekey.net", "edgeHostnameId": "ehn_4", "certProvisioningType": "CPS_MANAGED"},
+			},
+			"remove": []string{"staging-only.example.com"},
+		}).
+		Reply(200).
+		SetHeader("Content-Type", "application/json").
+		BodyString(`{"propertyId": "prp_1", "propertyVersion": 4, "hostnames": {"items": []}}`)
+
+	Init(config)
+
+	secure := testHostname("secure.example.com", "secure.example.com.edgekey.net")
+	secure.CertProvisioningType = CertProvisioningTypeCPSManaged
+	secure.CertEnrollmentId = "1234"
+
+	changes, err := testSyncProperty().SyncHostnames(4, []*Hostname{
+		testHostname("www.example.com", "www.example.com.edgesuite.net"),
+		testHostname("api.example.com", "api.example.com.edgesuite.net"),
+		secure,
+	}, nil, "")
+	assert.NoError(t, err)
+	assert.Len(t, changes.Add, 2)
+	assert.Len(t, changes.Remove, 1)
+	assert.Len(t, changes.CreateEdgeHostnames, 1)
+	assert.Equal(t, "ehn_4", changes.Add[1].EdgeHostnameID)
+	assert.True(t, gock.IsDone())
+}
+
+func TestProperty_SyncHostnames_ActiveInProduction(t *testing.T) {
+	defer gock.Off()
+
+	mockSyncHostnames("4", `[
+		{"cnameType": "EDGE_HOSTNAME", "edgeHostnameId": "ehn_1", "cnameFrom": "www.example.com", "cnameTo": "www.example.com.edgesuite.net"}
+	]`)
+	mockSyncHostnames("3", `[
+		{"cnameType": "EDGE_HOSTNAME", "edgeHostnameId": "ehn_1", "cnameFrom": "www.example.com", "cnameTo": "www.example.com.edgesuite.net"}
+	]`)
+
+	Init(config)
+
+	_, err := testSyncProperty().SyncHostnames(4, []*Hostname{}, nil, "")
+	assert.Error(t, err)
+	assert.True(t, errors.Is(err, ErrorMap[ErrHostnameActive]))
+	assert.Contains(t, err.Error(), "www.example.com")
+	assert.True(t, gock.IsDone())
+}
+
+func TestProperty_SyncHostnames_Force(t *testing.T) {
+	defer gock.Off()
+
+	mockSyncHostnames("4", `[
+		{"cnameType": "EDGE_HOSTNAME", "edgeHostnameId": "ehn_1", "cnameFrom": "www.example.com", "cnameTo": "www.example.com.edgesuite.net"}
+	]`)
+
+	gock.New("https://akaa-baseurl-xxxxxxxxxxx-xxxxxxxxxxxxx.luna.akamaiapis.net").
+		Patch("/papi/v1/properties/prp_1/versions/4/hostnames").
+		MatchType("json").
+		JSON(map[string]interface{}{"add": []interface{}{}, "remove": []string{"www.example.com"}}).
+		Reply(200).
+		SetHeader("Content-Type", "application/json").
+		BodyString(`{"propertyId": "prp_1", "propertyVersion": 4, "hostnames": {"items": []}}`)
+
+	Init(config)
+
+	changes, err := testSyncProperty().SyncHostnames(4, nil, &HostnameSyncOptions{Force: true}, "")
+	assert.NoError(t, err)
+	assert.Len(t, changes.Remove, 1)
+	assert.True(t, gock.IsDone())
+}
+
+func TestProperty_SyncHostnames_ChangedInProduction(t *testing.T) {
+	defer gock.Off()
+
+	mockSyncHostnames("4", `[
+		{"cnameType": "EDGE_HOSTNAME", "edgeHostnameId": "ehn_1", "cnameFrom": "www.example.com", "cnameTo": "www.example.com.edgesuite.net"}
+	]`)
+	mockSyncHostnames("3", `[
+		{"cnameType": "EDGE_HOSTNAME", "edgeHostnameId": "ehn_1", "cnameFrom": "www.example.com", "cnameTo": "www.example.com.edgesuite.net"}
+	]`)
+
+	Init(config)
+
+	_, err := testSyncProperty().SyncHostnames(4, []*Hostname{
+		testHostname("www.example.com", "www.example.com.edgekey.net"),
+	}, nil, "")
+	assert.True(t, errors.Is(err, ErrorMap[ErrHostnameActive]))
+	assert.True(t, gock.IsDone())
+}
+
+func TestProperty_SyncHostnames_Akamaized(t *testing.T) {
+	defer gock.Off()
+	ResponseCache.Invalidate("")
+
+	mockSyncHostnames("4", `[]`)
+
+	gock.New("https://akaa-baseurl-xxxxxxxxxxx-xxxxxxxxxxxxx.luna.akamaiapis.net").
+		Get("/papi/v1/edgehostnames").
+		MatchParam("contractId", "ctr_1").
+		MatchParam("groupId", "grp_1").
+		Reply(200).
+		SetHeader("Content-Type", "application/json").
+		BodyString(`{"edgeHostnames": {"items": []}}`)
+
+	Init(config)
+
+	changes, err := testSyncProperty().SyncHostnames(4, []*Hostname{
+		testHostname("media.example.com", "media.example.com.akamaized.net"),
+	}, &HostnameSyncOptions{DryRun: true}, "")
+	assert.NoError(t, err)
+	assert.Len(t, changes.CreateEdgeHostnames, 1)
+	assert.Equal(t, "media.example.com", changes.CreateEdgeHostnames[0].DomainPrefix)
+	assert.Equal(t, "akamaized.net", changes.CreateEdgeHostnames[0].DomainSuffix)
+	assert.Equal(t, "SHARED_CERT", changes.CreateEdgeHostnames[0].SecureNetwork)
+	assert.True(t, gock.IsDone())
+}
+
+func TestProperty_SyncHostnames_CertProvisioningType(t *testing.T) {
+	defer gock.Off()
+	ResponseCache.Invalidate("")
+
+	mockSyncHostnames("4", `[]`)
+
+	gock.New("https://akaa-baseurl-xxxxxxxxxxx-xxxxxxxxxxxxx.luna.akamaiapis.net").
+		Get("/papi/v1/edgehostnames").
+		MatchParam("contractId", "ctr_1").
+		MatchParam("groupId", "grp_1").
+		Reply(200).
+		SetHeader("Content-Type", "application/json").
+		BodyString(`{"edgeHostnames": {"items": []}}`)
+
+	Init(config)
+
+	secure := testHostname("secure.example.com", "secure.example.com.edgekey.net")
+	secure.CertProvisioningType = CertProvisioningTypeCPSManaged
+
+	_, err := testSyncProperty().SyncHostnames(4, []*Hostname{secure}, &HostnameSyncOptions{DryRun: true}, "")
+	assert.EqualError(t, err, "hostname secure.example.com: CPS_MANAGED edgekey.net edge hostnames need a certEnrollmentId")
+	assert.True(t, gock.IsDone())
+}
+
+func TestSplitEdgeHostname(t *testing.T) {
+	tests := []struct {
+		name   string
+		prefix string
+		suffix string
+	}{
+		{"www.example.com.edgekey.net", "www.example.com", "edgekey.net"},
+		{"www.example.com.EdgeSuite.net.", "www.example.com", "edgesuite.net"},
+		{"media.example.com.akamaized.net", "media.example.com", "akamaized.net"},
+	}
+
+	for _, test := range tests {
+		prefix, suffix, err := splitEdgeHostname(test.name)
+		assert.NoError(t, err)
+		assert.Equal(t, test.prefix, prefix)
+		assert.Equal(t, test.suffix, suffix)
+	}
+
+	_, _, err := splitEdgeHostname("www.example.com.cdn.example.net")
+	assert.EqualError(t, err, "www.example.com.cdn.example.net is not an edgekey.net, edgesuite.net or akamaized.net edge hostname")
+
+	_, _, err = splitEdgeHostname("edgekey.net")
+	assert.Error(t, err)
+}
+
+func TestProperty_SyncHostnames_AkamaizedExists(t *testing.T) {
+	defer gock.Off()
+	ResponseCache.Invalidate("")
+
+	mockSyncHostnames("4", `[]`)
+
+	gock.New("https://akaa-baseurl-xxxxxxxxxxx-xxxxxxxxxxxxx.luna.akamaiapis.net").
+		Get("/papi/v1/edgehostnames").
+		MatchParam("contractId", "ctr_1").
+		MatchParam("groupId", "grp_1").
+		Reply(200).
+		SetHeader("Content-Type", "application/json").
+		BodyString(`{"edgeHostnames": {"items": [
+			{"edgeHostnameId": "ehn_5", "edgeHostnameDomain": "media.example.com.akamaized.net", "domainPrefix": "media.example.com", "domainSuffix": "akamaized.net"}
+		]}}`)
+
+	Init(config)
+
+	media := testHostname("media.example.com", "media.example.com.akamaized.net")
+	changes, err := testSyncProperty().SyncHostnames(4, []*Hostname{media}, &HostnameSyncOptions{DryRun: true}, "")
+	assert.NoError(t, err)
+	assert.Empty(t, changes.CreateEdgeHostnames)
+	assert.Equal(t, "ehn_5", media.EdgeHostnameID)
+	assert.True(t, gock.IsDone())
+}
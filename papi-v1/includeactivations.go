@@ -0,0 +1,247 @@
+package papi
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/akamai/AkamaiOPEN-edgegrid-golang/client-v1"
+	edge "github.com/akamai/AkamaiOPEN-edgegrid-golang/edgegrid"
+)
+
+// IncludeActivation represents an include activation resource
+//
+// API Docs: https://developer.akamai.com/api/core_features/property_manager/v1.html#includeactivations
+type IncludeActivation struct {
+	client.Resource
+	parent                 *Include
+	ActivationID           string             `json:"activationId,omitempty"`
+	ActivationType         ActivationValue    `json:"activationType,omitempty"`
+	AcknowledgeAllWarnings bool               `json:"acknowledgeAllWarnings,omitempty"`
+	AcknowledgeWarnings    []string           `json:"acknowledgeWarnings,omitempty"`
+	IncludeID              string             `json:"includeId,omitempty"`
+	IncludeName            string             `json:"includeName,omitempty"`
+	IncludeType            IncludeTypeValue   `json:"includeType,omitempty"`
+	IncludeVersion         int                `json:"includeVersion"`
+	Network                NetworkValue       `json:"network"`
+	Status                 StatusValue        `json:"status,omitempty"`
+	SubmitDate             string             `json:"submitDate,omitempty"`
+	UpdateDate             string             `json:"updateDate,omitempty"`
+	Note                   string             `json:"note,omitempty"`
+	NotifyEmails           []string           `json:"notifyEmails"`
+	FMAPercent             *float64           `json:"fmaPercent,omitempty"`
+	Warnings               ActivationWarnings `json:"-"`
+}
+
+// includeActivations is the response body of the include activation endpoints
+type includeActivations struct {
+	client.Resource
+	Activations struct {
+		Items []*IncludeActivation `json:"items"`
+	} `json:"activations"`
+}
+
+// NewActivation creates a new *IncludeActivation of this include
+func (include *Include) NewActivation() *IncludeActivation {
+	activation := &IncludeActivation{parent: include}
+	activation.Init()
+
+	return activation
+}
+
+// GetActivations retrieves all activations of the include
+//
+// API Docs: https://developer.akamai.com/api/core_features/property_manager/v1.html#getincludeactivations
+// Endpoint: GET /papi/v1/includes/{includeId}/activations{?contractId,groupId}
+func (include *Include) GetActivations(correlationid string) ([]*IncludeActivation, error) {
+	activations := &includeActivations{}
+	if err := doIncludeRequest("GET", include.path("/activations"), nil, activations, correlationid); err != nil {
+		return nil, err
+	}
+
+	for _, activation := range activations.Activations.Items {
+		activation.parent = include
+	}
+
+	return activations.Activations.Items, nil
+}
+
+// Activate activates or, with ActivationType DEACTIVATE, deactivates an
+// include version on a network
+//
+// Properties using the include pick up the activated version on their next
+// activation. Use IncludeActivation.Wait() to wait for it to complete.
+//
+// See: IncludeActivation.ActivateWithPolicy() to acknowledge only some warnings
+// API Docs: https://developer.akamai.com/api/core_features/property_manager/v1.html#postincludeactivation
+// Endpoint: POST /papi/v1/includes/{includeId}/activations{?contractId,groupId}
+func (activation *IncludeActivation) Activate(correlationid string) error {
+	return activation.ActivateWithPolicy(nil, correlationid)
+}
+
+// ActivateWithPolicy activates an include version, deciding which warnings to
+// acknowledge with policy
+//
+// It handles warnings exactly like Activation.SaveWithPolicy(): they are
+// stored in activation.Warnings, and the activation is resubmitted
+// acknowledging their message IDs only when policy approves every one of them.
+// Otherwise the rejected warnings are returned as ActivationWarnings.
+//
+// API Docs: https://developer.akamai.com/api/core_features/property_manager/v1.html#postincludeactivation
+// Endpoint: POST /papi/v1/includes/{includeId}/activations{?contractId,groupId}
+func (activation *IncludeActivation) ActivateWithPolicy(policy ActivationWarningPolicy, correlationid string) error {
+	if activation.parent == nil {
+		return errors.New("You must create the activation with Include.NewActivation()")
+	}
+
+	if activation.ActivationType == "" {
+		activation.ActivationType = ActivationTypeActivate
+	}
+
+	body := client.JSONBody{
+		"activationType":         activation.ActivationType,
+		"includeVersion":         activation.IncludeVersion,
+		"network":                activation.Network,
+		"note":                   activation.Note,
+		"notifyEmails":           activation.NotifyEmails,
+		"acknowledgeAllWarnings": activation.AcknowledgeAllWarnings,
+	}
+	if len(activation.AcknowledgeWarnings) != 0 {
+		body["acknowledgeWarnings"] = activation.AcknowledgeWarnings
+	}
+
+	var location client.JSONBody
+	err := doIncludeRequest("POST", activation.parent.path("/activations"), body, &location, correlationid)
+
+	var apiError client.APIError
+	if errors.As(err, &apiError) && apiError.Response.StatusCode == 400 {
+		warnings := parseActivationWarnings([]byte(apiError.RawBody))
+		if len(warnings) != 0 {
+			activation.Warnings = warnings
+		}
+
+		if len(warnings) != 0 && policy != nil {
+			var rejected ActivationWarnings
+			for _, warning := range warnings {
+				if !policy(warning) {
+					rejected = append(rejected, warning)
+				}
+			}
+
+			if len(rejected) != 0 {
+				return rejected
+			}
+
+			activation.AcknowledgeWarnings = append(activation.AcknowledgeWarnings, warnings.MessageIDs()...)
+
+			// Don't acknowledge warnings again, halting a potential endless recursion
+			return activation.ActivateWithPolicy(nil, correlationid)
+		}
+	}
+	if err != nil {
+		return err
+	}
+
+	activation.ActivationID = linkID(location["activationLink"], "atv_")
+	if activation.ActivationID == "" {
+		return fmt.Errorf("No activation ID in %v", location["activationLink"])
+	}
+
+	_, err = activation.getActivation(context.Background(), 0, correlationid)
+	return err
+}
+
+// GetActivation populates the IncludeActivation
+//
+// API Docs: https://developer.akamai.com/api/core_features/property_manager/v1.html#getincludeactivation
+// Endpoint: GET /papi/v1/includes/{includeId}/activations/{activationId}{?contractId,groupId}
+func (activation *IncludeActivation) GetActivation(correlationid string) error {
+	_, err := activation.getActivation(context.Background(), 0, correlationid)
+	return err
+}
+
+// getActivation populates the IncludeActivation, returning the poll
+// interval suggested by the Retry-After header, no shorter than minimum
+func (activation *IncludeActivation) getActivation(ctx context.Context, minimum time.Duration, correlationid string) (time.Duration, error) {
+	if activation.parent == nil {
+		return 0, errors.New("You must create the activation with Include.NewActivation()")
+	}
+
+	req, err := client.NewRequest(
+		Config,
+		"GET",
+		activation.parent.path("/activations/"+activation.ActivationID),
+		nil,
+	)
+	if err != nil {
+		return 0, err
+	}
+
+	req = req.WithContext(ctx)
+
+	edge.PrintHttpRequestCorrelation(req, true, correlationid)
+
+	res, err := client.Do(Config, req)
+	if err != nil {
+		return 0, err
+	}
+
+	edge.PrintHttpResponseCorrelation(res, true, correlationid)
+
+	if client.IsError(res) {
+		return 0, client.NewAPIError(res)
+	}
+
+	activations := &includeActivations{}
+	if err := client.BodyJSON(res, activations); err != nil {
+		return 0, err
+	}
+
+	if len(activations.Activations.Items) == 0 {
+		return 0, fmt.Errorf("Activation \"%s\" not found", activation.ActivationID)
+	}
+
+	found := activations.Activations.Items[0]
+	activation.ActivationType = found.ActivationType
+	activation.AcknowledgeAllWarnings = found.AcknowledgeAllWarnings
+	activation.AcknowledgeWarnings = found.AcknowledgeWarnings
+	activation.IncludeID = found.IncludeID
+	activation.IncludeName = found.IncludeName
+	activation.IncludeType = found.IncludeType
+	activation.IncludeVersion = found.IncludeVersion
+	activation.Network = found.Network
+	activation.Status = found.Status
+	activation.SubmitDate = found.SubmitDate
+	activation.UpdateDate = found.UpdateDate
+	activation.Note = found.Note
+	activation.NotifyEmails = found.NotifyEmails
+	activation.FMAPercent = found.FMAPercent
+
+	return retryAfter(res.Header.Get("Retry-After"), minimum), nil
+}
+
+// Wait polls the include activation until its status is terminal or ctx is
+// done
+//
+// It behaves exactly like Activation.Wait(); opts.Property is ignored.
+func (activation *IncludeActivation) Wait(ctx context.Context, opts *ActivationWaitOptions) error {
+	if opts == nil {
+		opts = &ActivationWaitOptions{}
+	}
+
+	return waitForActivation(ctx, activation, opts)
+}
+
+func (activation *IncludeActivation) poll(ctx context.Context, minimum time.Duration) (time.Duration, error) {
+	return activation.getActivation(ctx, minimum, "")
+}
+
+func (activation *IncludeActivation) state() activationState {
+	return activationState{
+		activationID: activation.ActivationID,
+		network:      activation.Network,
+		status:       activation.Status,
+		fmaPercent:   activation.FMAPercent,
+	}
+}
@@ -0,0 +1,161 @@
+package papi
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"gopkg.in/h2non/gock.v1"
+)
+
+func mockIncludeActivationStatus(status StatusValue) {
+	gock.New("https://akaa-baseurl-xxxxxxxxxxx-xxxxxxxxxxxxx.luna.akamaiapis.net").
+		Get("/papi/v1/includes/inc_1/activations/atv_1").
+		MatchParam("contractId", "ctr_1").
+		Reply(200).
+		SetHeader("Content-Type", "application/json").
+		SetHeader("Retry-After", "0").
+		BodyString(`{"activations": {"items": [{"activationId": "atv_1", "includeId": "inc_1", "includeVersion": 2, "network": "STAGING", "status": "` + string(status) + `"}]}}`)
+}
+
+func TestIncludeActivation_Activate(t *testing.T) {
+	defer gock.Off()
+
+	gock.New("https://akaa-baseurl-xxxxxxxxxxx-xxxxxxxxxxxxx.luna.akamaiapis.net").
+		Post("/papi/v1/includes/inc_1/activations").
+		MatchType("json").
+		JSON(map[string]interface{}{
+			"activationType":         "ACTIVATE",
+			"includeVersion":         2,
+			"network":                "STAGING",
+			"note":                   "",
+			"notifyEmails":           []string{"noc@example.com"},
+			"acknowledgeAllWarnings": true,
+		}).
+		Reply(201).
+		SetHeader("Content-Type", "application/json").
+		BodyString(`{"activationLink": "/papi/v1/includes/inc_1/activations/atv_1?contractId=ctr_1&groupId=grp_1"}`)
+
+	mockIncludeActivationStatus(StatusPending)
+
+	Init(config)
+
+	activation := newTestInclude().NewActivation()
+	activation.IncludeVersion = 2
+	activation.Network = NetworkStaging
+	activation.NotifyEmails = []string{"noc@example.com"}
+	activation.AcknowledgeAllWarnings = true
+
+	assert.NoError(t, activation.Activate(""))
+	assert.Equal(t, "atv_1", activation.ActivationID)
+	assert.Equal(t, StatusPending, activation.Status)
+	assert.True(t, gock.IsDone())
+}
+
+func mockIncludeActivationWarnings() {
+	gock.New("https://akaa-baseurl-xxxxxxxxxxx-xxxxxxxxxxxxx.luna.akamaiapis.net").
+		Post("/papi/v1/includes/inc_1/activations").
+		Reply(400).
+		SetHeader("Content-Type", "application/problem+json").
+		BodyString(`{
+			"type": "https://problems.luna.akamaiapis.net/papi/v0/activation-warnings-not-acknowledged",
+			"status": 400,
+			"warnings": [{
+				"type": "https://problems.luna.akamaiapis.net/papi/v0/validation/validation_message.ssl_custom_origin",
+				"messageId": "msg_1"
+			}, {
+				"type": "https://problems.luna.akamaiapis.net/papi/v0/validation/validation_message.variable_default_value",
+				"messageId": "msg_2"
+			}]
+		}`)
+}
+
+func TestIncludeActivation_ActivateWithPolicy(t *testing.T) {
+	defer gock.Off()
+
+	mockIncludeActivationWarnings()
+
+	gock.New("https://akaa-baseurl-xxxxxxxxxxx-xxxxxxxxxxxxx.luna.akamaiapis.net").
+		Post("/papi/v1/includes/inc_1/activations").
+		BodyString(`"acknowledgeWarnings":\["msg_1","msg_2"\]`).
+		Reply(201).
+		SetHeader("Content-Type", "application/json").
+		BodyString(`{"activationLink": "/papi/v1/includes/inc_1/activations/atv_1?contractId=ctr_1&groupId=grp_1"}`)
+
+	mockIncludeActivationStatus(StatusPending)
+
+	Init(config)
+
+	activation := newTestInclude().NewActivation()
+	activation.IncludeVersion = 2
+	activation.Network = NetworkStaging
+
+	assert.NoError(t, activation.ActivateWithPolicy(AcknowledgeAllWarnings, ""))
+	assert.Equal(t, "atv_1", activation.ActivationID)
+	assert.Equal(t, []string{"msg_1", "msg_2"}, activation.Warnings.MessageIDs())
+	assert.True(t, gock.IsDone())
+}
+
+func TestIncludeActivation_ActivateWithPolicy_Rejected(t *testing.T) {
+	defer gock.Off()
+
+	mockIncludeActivationWarnings()
+
+	Init(config)
+
+	activation := newTestInclude().NewActivation()
+	activation.IncludeVersion = 2
+	activation.Network = NetworkStaging
+
+	err := activation.ActivateWithPolicy(AcknowledgeWarningTypes("https://problems.luna.akamaiapis.net/papi/v0/validation/validation_message.ssl_custom_origin"), "")
+
+	var rejected ActivationWarnings
+	assert.True(t, errors.As(err, &rejected))
+	assert.Equal(t, []string{"msg_2"}, rejected.MessageIDs())
+	assert.Empty(t, activation.ActivationID)
+	assert.True(t, gock.IsDone())
+}
+
+func TestIncludeActivation_Wait(t *testing.T) {
+	defer gock.Off()
+
+	mockIncludeActivationStatus(StatusPending)
+	mockIncludeActivationStatus(StatusActive)
+
+	Init(config)
+
+	activation := newTestInclude().NewActivation()
+	activation.ActivationID = "atv_1"
+
+	events := make(chan ActivationEvent, 10)
+	err := activation.Wait(context.Background(), &ActivationWaitOptions{Events: events, PollInterval: time.Millisecond})
+	close(events)
+
+	assert.NoError(t, err)
+	assert.Equal(t, StatusActive, activation.Status)
+	assert.True(t, gock.IsDone())
+
+	var statuses []StatusValue
+	for event := range events {
+		assert.Equal(t, "atv_1", event.ActivationID)
+		assert.Equal(t, NetworkStaging, event.Network)
+		statuses = append(statuses, event.NewStatus)
+	}
+	assert.Equal(t, []StatusValue{StatusPending, StatusActive}, statuses)
+}
+
+func TestIncludeActivation_Wait_Failed(t *testing.T) {
+	defer gock.Off()
+
+	mockIncludeActivationStatus(StatusFailed)
+
+	Init(config)
+
+	activation := newTestInclude().NewActivation()
+	activation.ActivationID = "atv_1"
+
+	err := activation.Wait(context.Background(), &ActivationWaitOptions{PollInterval: time.Millisecond})
+	assert.True(t, errors.Is(err, ErrorMap[ErrActivationFailed]))
+}
@@ -0,0 +1,464 @@
+package papi
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+
+	"github.com/akamai/AkamaiOPEN-edgegrid-golang/client-v1"
+	edge "github.com/akamai/AkamaiOPEN-edgegrid-golang/edgegrid"
+)
+
+// IncludeTypeValue is used to create an "enum" of possible Include.IncludeType values
+type IncludeTypeValue string
+
+const (
+	// IncludeTypeMicroservices Include.IncludeType value MICROSERVICES
+	IncludeTypeMicroservices IncludeTypeValue = "MICROSERVICES"
+	// IncludeTypeCommonSettings Include.IncludeType value COMMON_SETTINGS
+	IncludeTypeCommonSettings IncludeTypeValue = "COMMON_SETTINGS"
+)
+
+// Includes represents a collection of includes, the reusable rule trees
+// shared by properties
+//
+// See: Includes.GetIncludes()
+// API Docs: https://developer.akamai.com/api/core_features/property_manager/v1.html#includes
+type Includes struct {
+	client.Resource
+	AccountID  string `json:"accountId"`
+	ContractID string `json:"contractId"`
+	GroupID    string `json:"groupId"`
+	Includes   struct {
+		Items []*Include `json:"items"`
+	} `json:"includes"`
+}
+
+// NewIncludes creates a new *Includes
+func NewIncludes() *Includes {
+	includes := &Includes{}
+	includes.Init()
+
+	return includes
+}
+
+// PostUnmarshalJSON is called after JSON unmarshaling into Includes
+//
+// See: jsonhooks-v1/jsonhooks.Unmarshal()
+func (includes *Includes) PostUnmarshalJSON() error {
+	includes.Init()
+
+	for key := range includes.Includes.Items {
+		includes.Includes.Items[key].parent = includes
+	}
+
+	signalComplete(includes.Complete, true)
+
+	return nil
+}
+
+// GetIncludes populates Includes with the includes of a contract and group
+//
+// API Docs: https://developer.akamai.com/api/core_features/property_manager/v1.html#getincludes
+// Endpoint: GET /papi/v1/includes{?contractId,groupId}
+func (includes *Includes) GetIncludes(contract *Contract, group *Group, correlationid string) error {
+	if contract == nil || group == nil {
+		return errors.New("You must provide a contract and group")
+	}
+
+	return doIncludeRequest(
+		"GET",
+		fmt.Sprintf("/papi/v1/includes?contractId=%s&groupId=%s", contract.ContractID, group.GroupID),
+		nil,
+		includes,
+		correlationid,
+	)
+}
+
+// FindInclude finds an include by ID or name
+func (includes *Includes) FindInclude(nameOrID string) (*Include, error) {
+	for _, include := range includes.Includes.Items {
+		if include.IncludeID == nameOrID || include.IncludeName == nameOrID {
+			return include, nil
+		}
+	}
+
+	return nil, fmt.Errorf("Unable to find include: \"%s\"", nameOrID)
+}
+
+// NewInclude creates a new *Include associated with this *Includes as it's parent
+func (includes *Includes) NewInclude() *Include {
+	include := NewInclude(includes)
+	include.ContractID = includes.ContractID
+	include.GroupID = includes.GroupID
+	includes.Includes.Items = append(includes.Includes.Items, include)
+
+	return include
+}
+
+// Include represents an include resource
+//
+// API Docs: https://developer.akamai.com/api/core_features/property_manager/v1.html#includes
+type Include struct {
+	client.Resource
+	parent            *Includes
+	AccountID         string           `json:"accountId,omitempty"`
+	ContractID        string           `json:"contractId"`
+	GroupID           string           `json:"groupId"`
+	IncludeID         string           `json:"includeId,omitempty"`
+	IncludeName       string           `json:"includeName"`
+	IncludeType       IncludeTypeValue `json:"includeType"`
+	AssetID           string           `json:"assetId,omitempty"`
+	LatestVersion     int              `json:"latestVersion,omitempty"`
+	StagingVersion    int              `json:"stagingVersion,omitempty"`
+	ProductionVersion int              `json:"productionVersion,omitempty"`
+	// ProductID and RuleFormat are only used by Save
+	ProductID  string `json:"productId,omitempty"`
+	RuleFormat string `json:"ruleFormat,omitempty"`
+}
+
+// NewInclude creates a new *Include
+func NewInclude(parent *Includes) *Include {
+	include := &Include{parent: parent}
+	include.Init()
+
+	return include
+}
+
+// GetInclude populates the Include
+//
+// API Docs: https://developer.akamai.com/api/core_features/property_manager/v1.html#getinclude
+// Endpoint: GET /papi/v1/includes/{includeId}{?contractId,groupId}
+func (include *Include) GetInclude(correlationid string) error {
+	includes := NewIncludes()
+	if err := doIncludeRequest("GET", include.path(""), nil, includes, correlationid); err != nil {
+		return err
+	}
+
+	if len(includes.Includes.Items) == 0 {
+		return fmt.Errorf("Include \"%s\" not found", include.IncludeID)
+	}
+
+	found := includes.Includes.Items[0]
+	include.AccountID = found.AccountID
+	include.ContractID = found.ContractID
+	include.GroupID = found.GroupID
+	include.IncludeID = found.IncludeID
+	include.IncludeName = found.IncludeName
+	include.IncludeType = found.IncludeType
+	include.AssetID = found.AssetID
+	include.LatestVersion = found.LatestVersion
+	include.StagingVersion = found.StagingVersion
+	include.ProductionVersion = found.ProductionVersion
+
+	return nil
+}
+
+// Save creates a new include. You cannot update an include; create a new
+// version of its rules instead.
+//
+// API Docs: https://developer.akamai.com/api/core_features/property_manager/v1.html#postincludes
+// Endpoint: POST /papi/v1/includes{?contractId,groupId}
+func (include *Include) Save(correlationid string) error {
+	var location client.JSONBody
+	err := doIncludeRequest(
+		"POST",
+		fmt.Sprintf("/papi/v1/includes?contractId=%s&groupId=%s", include.ContractID, include.GroupID),
+		client.JSONBody{
+			"includeName": include.IncludeName,
+			"includeType": include.IncludeType,
+			"productId":   include.ProductID,
+			"ruleFormat":  include.RuleFormat,
+		},
+		&location,
+		correlationid,
+	)
+	if err != nil {
+		return err
+	}
+
+	include.IncludeID = linkID(location["includeLink"], "inc_")
+	if include.IncludeID == "" {
+		return fmt.Errorf("No include ID in %v", location["includeLink"])
+	}
+
+	return include.GetInclude(correlationid)
+}
+
+// Delete removes an include. Includes used by a property version, or
+// active on either network, cannot be deleted.
+//
+// API Docs: https://developer.akamai.com/api/core_features/property_manager/v1.html#deleteinclude
+// Endpoint: DELETE /papi/v1/includes/{includeId}{?contractId,groupId}
+func (include *Include) Delete(correlationid string) error {
+	return doIncludeRequest("DELETE", include.path(""), nil, nil, correlationid)
+}
+
+// IncludeVersions contains a collection of include versions
+type IncludeVersions struct {
+	client.Resource
+	AccountID   string           `json:"accountId"`
+	ContractID  string           `json:"contractId"`
+	GroupID     string           `json:"groupId"`
+	IncludeID   string           `json:"includeId"`
+	IncludeName string           `json:"includeName"`
+	IncludeType IncludeTypeValue `json:"includeType"`
+	Versions    struct {
+		Items []*IncludeVersion `json:"items"`
+	} `json:"versions"`
+}
+
+// IncludeVersion represents an include version resource
+type IncludeVersion struct {
+	client.Resource
+	IncludeVersion        int         `json:"includeVersion,omitempty"`
+	UpdatedByUser         string      `json:"updatedByUser,omitempty"`
+	UpdatedDate           string      `json:"updatedDate,omitempty"`
+	ProductionStatus      StatusValue `json:"productionStatus,omitempty"`
+	StagingStatus         StatusValue `json:"stagingStatus,omitempty"`
+	Etag                  string      `json:"etag,omitempty"`
+	ProductID             string      `json:"productId,omitempty"`
+	RuleFormat            string      `json:"ruleFormat,omitempty"`
+	Note                  string      `json:"note,omitempty"`
+	CreateFromVersion     int         `json:"createFromVersion,omitempty"`
+	CreateFromVersionEtag string      `json:"createFromVersionEtag,omitempty"`
+}
+
+// GetVersions retrieves all versions of the include
+//
+// API Docs: https://developer.akamai.com/api/core_features/property_manager/v1.html#getincludeversions
+// Endpoint: GET /papi/v1/includes/{includeId}/versions{?contractId,groupId}
+func (include *Include) GetVersions(correlationid string) (*IncludeVersions, error) {
+	versions := &IncludeVersions{}
+	if err := doIncludeRequest("GET", include.path("/versions"), nil, versions, correlationid); err != nil {
+		return nil, err
+	}
+
+	return versions, nil
+}
+
+// GetVersion retrieves a single version of the include
+//
+// API Docs: https://developer.akamai.com/api/core_features/property_manager/v1.html#getincludeversion
+// Endpoint: GET /papi/v1/includes/{includeId}/versions/{includeVersion}{?contractId,groupId}
+func (include *Include) GetVersion(version int, correlationid string) (*IncludeVersion, error) {
+	versions := &IncludeVersions{}
+	if err := doIncludeRequest("GET", include.path(fmt.Sprintf("/versions/%d", version)), nil, versions, correlationid); err != nil {
+		return nil, err
+	}
+
+	if len(versions.Versions.Items) == 0 {
+		return nil, fmt.Errorf("Include \"%s\" version %d not found", include.IncludeID, version)
+	}
+
+	return versions.Versions.Items[0], nil
+}
+
+// CreateVersion creates a new version of the include from an existing
+// version, which must have an Etag
+//
+// API Docs: https://developer.akamai.com/api/core_features/property_manager/v1.html#postincludeversions
+// Endpoint: POST /papi/v1/includes/{includeId}/versions{?contractId,groupId}
+func (include *Include) CreateVersion(createFromVersion *IncludeVersion, correlationid string) (*IncludeVersion, error) {
+	if createFromVersion == nil {
+		return nil, errors.New("You must provide a version to create from")
+	}
+
+	var location client.JSONBody
+	err := doIncludeRequest(
+		"POST",
+		include.path("/versions"),
+		client.JSONBody{
+			"createFromVersion":     createFromVersion.IncludeVersion,
+			"createFromVersionEtag": createFromVersion.Etag,
+		},
+		&location,
+		correlationid,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	version, err := strconv.Atoi(linkID(location["versionLink"], ""))
+	if err != nil {
+		return nil, fmt.Errorf("No include version in %v", location["versionLink"])
+	}
+
+	if version > include.LatestVersion {
+		include.LatestVersion = version
+	}
+
+	return include.GetVersion(version, correlationid)
+}
+
+// GetRules retrieves the rule tree of an include version
+//
+// The returned Rules have IncludeID and IncludeVersion set instead of
+// PropertyID and PropertyVersion.
+//
+// API Docs: https://developer.akamai.com/api/core_features/property_manager/v1.html#getincluderuletree
+// Endpoint: GET /papi/v1/includes/{includeId}/versions/{includeVersion}/rules{?contractId,groupId}
+func (include *Include) GetRules(version int, correlationid string) (*Rules, error) {
+	rules := NewRules()
+	if err := doIncludeRequest("GET", include.path(fmt.Sprintf("/versions/%d/rules", version)), nil, rules, correlationid); err != nil {
+		return nil, err
+	}
+
+	return rules, nil
+}
+
+// SaveRules replaces the rule tree of the include version rules.IncludeVersion
+//
+// If rules.RuleFormat is set to something other than "latest", the rules
+// are frozen to that format.
+//
+// API Docs: https://developer.akamai.com/api/core_features/property_manager/v1.html#putincluderuletree
+// Endpoint: PUT /papi/v1/includes/{includeId}/versions/{includeVersion}/rules{?contractId,groupId}
+func (include *Include) SaveRules(rules *Rules, correlationid string) error {
+	if rules.IncludeVersion == 0 {
+		return errors.New("You must provide the include version of the rules")
+	}
+
+	req, err := client.NewJSONRequest(
+		Config,
+		"PUT",
+		include.path(fmt.Sprintf("/versions/%d/rules", rules.IncludeVersion)),
+		struct {
+			Rule *Rule `json:"rules"`
+		}{rules.Rule},
+	)
+	if err != nil {
+		return err
+	}
+
+	if rules.RuleFormat != "" && rules.RuleFormat != "latest" {
+		req.Header.Set("Content-Type", fmt.Sprintf("application/vnd.akamai.papirules.%s+json", rules.RuleFormat))
+	}
+
+	edge.PrintHttpRequestCorrelation(req, true, correlationid)
+
+	res, err := client.Do(Config, req)
+	if err != nil {
+		return err
+	}
+
+	edge.PrintHttpResponseCorrelation(res, true, correlationid)
+
+	if client.IsError(res) {
+		return client.NewAPIError(res)
+	}
+
+	rules.Errors = []*RuleErrors{}
+	if err = client.BodyJSON(res, rules); err != nil {
+		return err
+	}
+
+	if len(rules.Errors) != 0 {
+		return ErrorMap[ErrInvalidRules]
+	}
+
+	return nil
+}
+
+// IncludeParent is a property that uses an include
+type IncludeParent struct {
+	client.Resource
+	AccountID                        string `json:"accountId"`
+	ContractID                       string `json:"contractId"`
+	GroupID                          string `json:"groupId"`
+	PropertyID                       string `json:"propertyId"`
+	PropertyName                     string `json:"propertyName"`
+	StagingVersion                   int    `json:"stagingVersion,omitempty"`
+	ProductionVersion                int    `json:"productionVersion,omitempty"`
+	IsIncludeUsedInStagingVersion    bool   `json:"isIncludeUsedInStagingVersion"`
+	IsIncludeUsedInProductionVersion bool   `json:"isIncludeUsedInProductionVersion"`
+}
+
+// GetParents lists the properties that use the include
+//
+// API Docs: https://developer.akamai.com/api/core_features/property_manager/v1.html#getincludeparents
+// Endpoint: GET /papi/v1/includes/{includeId}/parents{?contractId,groupId}
+func (include *Include) GetParents(correlationid string) ([]*IncludeParent, error) {
+	var parents struct {
+		Properties struct {
+			Items []*IncludeParent `json:"items"`
+		} `json:"properties"`
+	}
+	if err := doIncludeRequest("GET", include.path("/parents"), nil, &parents, correlationid); err != nil {
+		return nil, err
+	}
+
+	return parents.Properties.Items, nil
+}
+
+// path returns the path of a subresource of the include, with the
+// include's contract and group
+func (include *Include) path(subresource string) string {
+	query := url.Values{}
+	if include.ContractID != "" {
+		query.Set("contractId", include.ContractID)
+	}
+	if include.GroupID != "" {
+		query.Set("groupId", include.GroupID)
+	}
+
+	return fmt.Sprintf("/papi/v1/includes/%s%s?%s", include.IncludeID, subresource, query.Encode())
+}
+
+// doIncludeRequest makes a request, decoding the response into result
+// unless it is nil
+func doIncludeRequest(method string, path string, body interface{}, result interface{}, correlationid string) error {
+	var req *http.Request
+	var err error
+	if body != nil {
+		req, err = client.NewJSONRequest(Config, method, path, body)
+	} else {
+		req, err = client.NewRequest(Config, method, path, nil)
+	}
+	if err != nil {
+		return err
+	}
+
+	edge.PrintHttpRequestCorrelation(req, true, correlationid)
+
+	res, err := client.Do(Config, req)
+	if err != nil {
+		return err
+	}
+
+	edge.PrintHttpResponseCorrelation(res, true, correlationid)
+
+	if client.IsError(res) {
+		return client.NewAPIError(res)
+	}
+
+	if result == nil {
+		return nil
+	}
+
+	return client.BodyJSON(res, result)
+}
+
+// linkID returns the last segment of a link's path, if it has prefix
+func linkID(link interface{}, prefix string) string {
+	location, ok := link.(string)
+	if !ok {
+		return ""
+	}
+
+	parsed, err := url.Parse(location)
+	if err != nil {
+		return ""
+	}
+
+	parts := strings.Split(strings.TrimSuffix(parsed.Path, "/"), "/")
+	last := parts[len(parts)-1]
+	if !strings.HasPrefix(last, prefix) {
+		return ""
+	}
+
+	return last
+}
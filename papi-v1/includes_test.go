@@ -0,0 +1,196 @@
+package papi
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"gopkg.in/h2non/gock.v1"
+)
+
+func newTestInclude() *Include {
+	include := NewInclude(NewIncludes())
+	include.IncludeID = "inc_1"
+	include.ContractID = "ctr_1"
+	include.GroupID = "grp_1"
+
+	return include
+}
+
+func mockGetInclude() {
+	gock.New("https://akaa-baseurl-xxxxxxxxxxx-xxxxxxxxxxxxx.luna.akamaiapis.net").
+		Get("/papi/v1/includes/inc_1").
+		MatchParam("contractId", "ctr_1").
+		MatchParam("groupId", "grp_1").
+		Reply(200).
+		SetHeader("Content-Type", "application/json").
+		BodyString(`{"includes": {"items": [{"includeId": "inc_1", "includeName": "common", "includeType": "COMMON_SETTINGS", "contractId": "ctr_1", "groupId": "grp_1", "latestVersion": 2, "stagingVersion": 1}]}}`)
+}
+
+func TestIncludes_GetIncludes(t *testing.T) {
+	defer gock.Off()
+
+	gock.New("https://akaa-baseurl-xxxxxxxxxxx-xxxxxxxxxxxxx.luna.akamaiapis.net").
+		Get("/papi/v1/includes").
+		MatchParam("contractId", "ctr_1").
+		MatchParam("groupId", "grp_1").
+		Reply(200).
+		SetHeader("Content-Type", "application/json").
+		BodyString(`{"contractId": "ctr_1", "groupId": "grp_1", "includes": {"items": [
+			{"includeId": "inc_1", "includeName": "common", "includeType": "COMMON_SETTINGS", "latestVersion": 2},
+			{"includeId": "inc_2", "includeName": "api", "includeType": "MICROSERVICES", "latestVersion": 1}
+		]}}`)
+
+	Init(config)
+
+	contract := NewContract(NewContracts())
+	contract.ContractID = "ctr_1"
+	group := NewGroup(NewGroups())
+	group.GroupID = "grp_1"
+
+	includes := NewIncludes()
+	assert.NoError(t, includes.GetIncludes(contract, group, ""))
+	assert.Len(t, includes.Includes.Items, 2)
+
+	include, err := includes.FindInclude("api")
+	assert.NoError(t, err)
+	assert.Equal(t, "inc_2", include.IncludeID)
+	assert.Equal(t, IncludeTypeMicroservices, include.IncludeType)
+
+	_, err = includes.FindInclude("missing")
+	assert.Error(t, err)
+}
+
+func TestInclude_Save(t *testing.T) {
+	defer gock.Off()
+
+	gock.New("https://akaa-baseurl-xxxxxxxxxxx-xxxxxxxxxxxxx.luna.akamaiapis.net").
+		Post("/papi/v1/includes").
+		MatchParam("contractId", "ctr_1").
+		MatchParam("groupId", "grp_1").
+		MatchType("json").
+		JSON(map[string]string{"includeName": "common", "includeType": "COMMON_SETTINGS", "productId": "prd_Fresca", "ruleFormat": "v2020-11-02"}).
+		Reply(201).
+		SetHeader("Content-Type", "application/json").
+		BodyString(`{"includeLink": "/papi/v1/includes/inc_1?contractId=ctr_1&groupId=grp_1"}`)
+
+	mockGetInclude()
+
+	Init(config)
+
+	includes := NewIncludes()
+	includes.ContractID = "ctr_1"
+	includes.GroupID = "grp_1"
+
+	include := includes.NewInclude()
+	include.IncludeName = "common"
+	include.IncludeType = IncludeTypeCommonSettings
+	include.ProductID = "prd_Fresca"
+	include.RuleFormat = "v2020-11-02"
+
+	assert.NoError(t, include.Save(""))
+	assert.Equal(t, "inc_1", include.IncludeID)
+	assert.Equal(t, 2, include.LatestVersion)
+	assert.True(t, gock.IsDone())
+}
+
+func TestInclude_Delete(t *testing.T) {
+	defer gock.Off()
+
+	gock.New("https://akaa-baseurl-xxxxxxxxxxx-xxxxxxxxxxxxx.luna.akamaiapis.net").
+		Delete("/papi/v1/includes/inc_1").
+		MatchParam("contractId", "ctr_1").
+		Reply(200).
+		SetHeader("Content-Type", "application/json").
+		BodyString(`{"message": "Deletion Successful."}`)
+
+	Init(config)
+
+	assert.NoError(t, newTestInclude().Delete(""))
+	assert.True(t, gock.IsDone())
+}
+
+func TestInclude_CreateVersion(t *testing.T) {
+	defer gock.Off()
+
+	gock.New("https://akaa-baseurl-xxxxxxxxxxx-xxxxxxxxxxxxx.luna.akamaiapis.net").
+		Post("/papi/v1/includes/inc_1/versions").
+		MatchType("json").
+		JSON(map[string]interface{}{"createFromVersion": 2, "createFromVersionEtag": "abc"}).
+		Reply(201).
+		SetHeader("Content-Type", "application/json").
+		BodyString(`{"versionLink": "/papi/v1/includes/inc_1/versions/3?contractId=ctr_1&groupId=grp_1"}`)
+
+	gock.New("https://akaa-baseurl-xxxxxxxxxxx-xxxxxxxxxxxxx.luna.akamaiapis.net").
+		Get("/papi/v1/includes/inc_1/versions/3").
+		Reply(200).
+		SetHeader("Content-Type", "application/json").
+		BodyString(`{"includeId": "inc_1", "versions": {"items": [{"includeVersion": 3, "etag": "def", "stagingStatus": "INACTIVE", "productionStatus": "INACTIVE"}]}}`)
+
+	Init(config)
+
+	include := newTestInclude()
+	include.LatestVersion = 2
+
+	version, err := include.CreateVersion(&IncludeVersion{IncludeVersion: 2, Etag: "abc"}, "")
+	assert.NoError(t, err)
+	assert.Equal(t, 3, version.IncludeVersion)
+	assert.Equal(t, "def", version.Etag)
+	assert.Equal(t, 3, include.LatestVersion)
+}
+
+func TestInclude_Rules(t *testing.T) {
+	defer gock.Off()
+
+	gock.New("https://akaa-baseurl-xxxxxxxxxxx-xxxxxxxxxxxxx.luna.akamaiapis.net").
+		Get("/papi/v1/includes/inc_1/versions/2/rules").
+		Reply(200).
+		SetHeader("Content-Type", "application/json").
+		BodyString(`{"includeId": "inc_1", "includeVersion": 2, "ruleFormat": "v2020-11-02", "etag": "abc", "rules": {"name": "default", "behaviors": [{"name": "caching", "options": {"behavior": "NO_STORE"}}]}}`)
+
+	gock.New("https://akaa-baseurl-xxxxxxxxxxx-xxxxxxxxxxxxx.luna.akamaiapis.net").
+		Put("/papi/v1/includes/inc_1/versions/2/rules").
+		MatchHeader("Content-Type", `application/vnd\.akamai\.papirules\.v2020-11-02\+json`).
+		Reply(200).
+		SetHeader("Content-Type", "application/json").
+		BodyString(`{"includeId": "inc_1", "includeVersion": 2, "ruleFormat": "v2020-11-02", "etag": "def", "rules": {"name": "default"}, "errors": [{"type": "https://problems.luna.akamaiapis.net/papi/v0/validation/attribute_required", "detail": "missing ttl"}]}`)
+
+	Init(config)
+
+	include := newTestInclude()
+
+	rules, err := include.GetRules(2, "")
+	assert.NoError(t, err)
+	assert.Equal(t, "inc_1", rules.IncludeID)
+	assert.Equal(t, 2, rules.IncludeVersion)
+
+	behavior, err := rules.FindBehavior("/caching")
+	assert.NoError(t, err)
+	behavior.Options["behavior"] = "MAX_AGE"
+
+	err = include.SaveRules(rules, "")
+	assert.Equal(t, ErrorMap[ErrInvalidRules], err)
+	assert.Equal(t, "def", rules.Etag)
+	assert.Len(t, rules.Errors, 1)
+	assert.True(t, gock.IsDone())
+}
+
+func TestInclude_GetParents(t *testing.T) {
+	defer gock.Off()
+
+	gock.New("https://akaa-baseurl-xxxxxxxxxxx-xxxxxxxxxxxxx.luna.akamaiapis.net").
+		Get("/papi/v1/includes/inc_1/parents").
+		Reply(200).
+		SetHeader("Content-Type", "application/json").
+		BodyString(`{"properties": {"items": [
+			{"propertyId": "prp_1", "propertyName": "www.example.com", "stagingVersion": 4, "isIncludeUsedInStagingVersion": true, "isIncludeUsedInProductionVersion": false}
+		]}}`)
+
+	Init(config)
+
+	parents, err := newTestInclude().GetParents("")
+	assert.NoError(t, err)
+	assert.Len(t, parents, 1)
+	assert.Equal(t, "prp_1", parents[0].PropertyID)
+	assert.True(t, parents[0].IsIncludeUsedInStagingVersion)
+	assert.False(t, parents[0].IsIncludeUsedInProductionVersion)
+}
@@ -0,0 +1,408 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"go/format"
+	"sort"
+	"strings"
+	"unicode"
+)
+
+// kind describes the generated package for behaviors or criteria
+type kind struct {
+	// Catalog is the key of the items in definitions.catalog
+	Catalog string
+	Package string
+	// Type is the papi type, and the name of the interface in the generated
+	// package
+	Type string
+	// Receiver is the receiver name of the generated methods
+	Receiver string
+}
+
+var kinds = map[string]kind{
+	"behaviors": {Catalog: "behaviors", Package: "behaviors", Type: "Behavior", Receiver: "behavior"},
+	"criteria":  {Catalog: "criteria", Package: "criteria", Type: "Criteria", Receiver: "criteria"},
+}
+
+// schemaNode is the subset of JSON schema used to describe options
+type schemaNode struct {
+	Ref         string                 `json:"$ref"`
+	Type        interface{}            `json:"type"`
+	Description string                 `json:"description"`
+	Enum        []interface{}          `json:"enum"`
+	Properties  map[string]*schemaNode `json:"properties"`
+	Items       *schemaNode            `json:"items"`
+}
+
+// schemaType returns the node's type, ignoring "null" in type lists
+func (node *schemaNode) schemaType() string {
+	switch value := node.Type.(type) {
+	case string:
+		return value
+	case []interface{}:
+		for _, item := range value {
+			if s, ok := item.(string); ok && s != "null" {
+				return s
+			}
+		}
+	}
+
+	if len(node.Properties) != 0 {
+		return "object"
+	}
+
+	return ""
+}
+
+type generator struct {
+	kind       kind
+	ruleFormat string
+	document   interface{}
+
+	out   strings.Builder
+	types strings.Builder
+	names map[string]bool
+}
+
+// generate returns the Go source of the typed builders for the behaviors or
+// criteria of a rule format schema
+func generate(schema []byte, kindName string, ruleFormat string) ([]byte, error) {
+	k, ok := kinds[kindName]
+	if !ok {
+		return nil, fmt.Errorf("unknown kind %q, expected behaviors or criteria", kindName)
+	}
+
+	gen := &generator{kind: k, ruleFormat: ruleFormat, names: map[string]bool{}}
+	if err := json.Unmarshal(schema, &gen.document); err != nil {
+		return nil, fmt.Errorf("parsing schema: %w", err)
+	}
+
+	items := map[string]*schemaNode{}
+	if err := gen.decode("#/definitions/catalog/"+k.Catalog, &items); err != nil {
+		return nil, err
+	}
+	if len(items) == 0 {
+		return nil, fmt.Errorf("schema has no definitions/catalog/%s", k.Catalog)
+	}
+
+	names := make([]string, 0, len(items))
+	for name := range items {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	for _, reserved := range []string{k.Type, "From" + k.Type, "RuleFormat", "Bool", "Int", "Float64"} {
+		gen.names[reserved] = true
+	}
+
+	// Items get their natural names before derived types are named
+	typeNames := map[string]string{}
+	for _, name := range names {
+		typeNames[name] = gen.unique(exportName(name))
+	}
+
+	fmt.Fprintf(&gen.out, "// Code generated by papi-v1/internal/gen from rule format %s. DO NOT EDIT.\n\n", ruleFormat)
+	fmt.Fprintf(&gen.out, "package %s\n\n", k.Package)
+	gen.out.WriteString("import papi \"github.com/akamai/AkamaiOPEN-edgegrid-golang/papi-v1\"\n\n")
+	fmt.Fprintf(&gen.out, "// RuleFormat is the rule format the %s were generated from\n", k.Catalog)
+	fmt.Fprintf(&gen.out, "const RuleFormat = %q\n\n", ruleFormat)
+
+	for _, name := range names {
+		if err := gen.item(name, typeNames[name], items[name]); err != nil {
+			return nil, fmt.Errorf("%s %s: %w", k.Receiver, name, err)
+		}
+	}
+
+	fmt.Fprintf(&gen.out, "var builders = map[string]func() %s{\n", k.Type)
+	for _, name := range names {
+		fmt.Fprintf(&gen.out, "\t%q: func() %s { return &%s{} },\n", name, k.Type, typeNames[name])
+	}
+	gen.out.WriteString("}\n")
+
+	source, err := format.Source([]byte(gen.out.String()))
+	if err != nil {
+		return nil, fmt.Errorf("formatting generated code: %w", err)
+	}
+
+	return source, nil
+}
+
+// item writes the struct and methods of a behavior or criterion
+func (gen *generator) item(name string, typeName string, node *schemaNode) error {
+	node, err := gen.resolve(node)
+	if err != nil {
+		return err
+	}
+
+	options := &schemaNode{}
+	if node.Properties["options"] != nil {
+		if options, err = gen.resolve(node.Properties["options"]); err != nil {
+			return err
+		}
+	}
+
+	gen.types.Reset()
+
+	description := fmt.Sprintf("%s is the %s %s", typeName, name, gen.kind.Receiver)
+	if err := gen.structType(&gen.out, typeName, description, options, true); err != nil {
+		return err
+	}
+
+	k := gen.kind
+	fmt.Fprintf(&gen.out, "// Name returns the %s name, %s\n", strings.ToLower(k.Type), name)
+	fmt.Fprintf(&gen.out, "func (%s *%s) Name() string {\n\treturn %q\n}\n\n", k.Receiver, typeName, name)
+	fmt.Fprintf(&gen.out, "// To%s converts the %s to a *papi.%s\n", k.Type, strings.ToLower(k.Type), k.Type)
+	fmt.Fprintf(&gen.out, "func (%s *%s) To%s() *papi.%s {\n\treturn new%s(%q, %s)\n}\n\n", k.Receiver, typeName, k.Type, k.Type, k.Type, name, k.Receiver)
+	fmt.Fprintf(&gen.out, "// From%s sets the options from a *papi.%s named %s\n", k.Type, k.Type, name)
+	fmt.Fprintf(&gen.out, "func (%s *%s) From%s(from *papi.%s) error {\n\treturn from%s(from, %q, %s)\n}\n\n", k.Receiver, typeName, k.Type, k.Type, k.Type, name, k.Receiver)
+
+	gen.out.WriteString(gen.types.String())
+
+	return nil
+}
+
+// structType writes a struct with a field per property of node. Enum and
+// nested struct types are written to gen.types. Fields of items that would
+// clash with their methods get an Option suffix.
+func (gen *generator) structType(out *strings.Builder, typeName string, description string, node *schemaNode, item bool) error {
+	properties := make([]string, 0, len(node.Properties))
+	for property := range node.Properties {
+		properties = append(properties, property)
+	}
+	sort.Strings(properties)
+
+	var fields strings.Builder
+	for _, property := range properties {
+		field, err := gen.resolve(node.Properties[property])
+		if err != nil {
+			return fmt.Errorf("option %s: %w", property, err)
+		}
+
+		fieldType, err := gen.fieldType(typeName, property, field)
+		if err != nil {
+			return fmt.Errorf("option %s: %w", property, err)
+		}
+
+		if field.Description != "" {
+			fmt.Fprintf(&fields, "\t// %s\n", strings.Join(strings.Fields(field.Description), " "))
+		}
+		fieldName := exportName(property)
+		if item && (fieldName == "Name" || fieldName == "To"+gen.kind.Type || fieldName == "From"+gen.kind.Type) {
+			fieldName += "Option"
+		}
+		fmt.Fprintf(&fields, "\t%s %s `json:\"%s,omitempty\"`\n", fieldName, fieldType, property)
+	}
+
+	fmt.Fprintf(out, "// %s\n", description)
+	fmt.Fprintf(out, "type %s struct {\n%s}\n\n", typeName, fields.String())
+
+	return nil
+}
+
+// fieldType returns the Go type of an option, writing the enum or struct
+// type it needs
+func (gen *generator) fieldType(parent string, property string, node *schemaNode) (string, error) {
+	switch node.schemaType() {
+	case "string":
+		if len(node.Enum) != 0 {
+			return gen.enumType(parent, property, node), nil
+		}
+		return "string", nil
+	case "boolean":
+		return "*bool", nil
+	case "integer":
+		return "*int", nil
+	case "number":
+		return "*float64", nil
+	case "array":
+		if node.Items == nil {
+			return "[]interface{}", nil
+		}
+		items, err := gen.resolve(node.Items)
+		if err != nil {
+			return "", err
+		}
+		itemType, err := gen.fieldType(parent, property, items)
+		if err != nil {
+			return "", err
+		}
+		return "[]" + strings.TrimPrefix(itemType, "*"), nil
+	case "object":
+		if len(node.Properties) == 0 {
+			return "map[string]interface{}", nil
+		}
+		typeName := gen.unique(parent + exportName(property))
+		description := fmt.Sprintf("%s is the value of %s.%s", typeName, parent, exportName(property))
+		if err := gen.structType(&gen.types, typeName, description, node, false); err != nil {
+			return "", err
+		}
+		return "*" + typeName, nil
+	}
+
+	return "interface{}", nil
+}
+
+// enumType writes a string type with a constant per enum value, following
+// the "XValue" convention of package papi
+func (gen *generator) enumType(parent string, property string, node *schemaNode) string {
+	prefix := parent + exportName(property)
+	typeName := prefix
+	if !strings.HasSuffix(typeName, "Value") {
+		typeName += "Value"
+	}
+	typeName = gen.unique(typeName)
+
+	field := parent + "." + exportName(property)
+	fmt.Fprintf(&gen.types, "// %s is used to create an \"enum\" of possible %s values\n", typeName, field)
+	fmt.Fprintf(&gen.types, "type %s string\n\nconst (\n", typeName)
+	for _, value := range node.Enum {
+		s, ok := value.(string)
+		if !ok {
+			continue
+		}
+		constant := gen.unique(prefix + enumName(s))
+		fmt.Fprintf(&gen.types, "\t// %s %s value %s\n", constant, field, s)
+		fmt.Fprintf(&gen.types, "\t%s %s = %q\n", constant, typeName, s)
+	}
+	gen.types.WriteString(")\n\n")
+
+	return typeName
+}
+
+// resolve follows a local $ref, e.g. #/definitions/type_name
+func (gen *generator) resolve(node *schemaNode) (*schemaNode, error) {
+	for depth := 0; node != nil && node.Ref != ""; depth++ {
+		if depth == 10 {
+			return nil, fmt.Errorf("$ref %s is circular", node.Ref)
+		}
+
+		resolved := &schemaNode{}
+		if err := gen.decode(node.Ref, resolved); err != nil {
+			return nil, err
+		}
+		node = resolved
+	}
+
+	if node == nil {
+		return &schemaNode{}, nil
+	}
+
+	return node, nil
+}
+
+// decode decodes the value at a local reference into v
+func (gen *generator) decode(ref string, v interface{}) error {
+	if !strings.HasPrefix(ref, "#/") {
+		return fmt.Errorf("$ref %s is not a local reference", ref)
+	}
+
+	value := gen.document
+	for _, token := range strings.Split(strings.TrimPrefix(ref, "#/"), "/") {
+		token = strings.NewReplacer("~1", "/", "~0", "~").Replace(token)
+		object, ok := value.(map[string]interface{})
+		if !ok {
+			return fmt.Errorf("$ref %s not found", ref)
+		}
+		if value, ok = object[token]; !ok {
+			return fmt.Errorf("$ref %s not found", ref)
+		}
+	}
+
+	data, err := json.Marshal(value)
+	if err != nil {
+		return err
+	}
+
+	return json.Unmarshal(data, v)
+}
+
+// unique returns name, or name with a number appended if it is taken
+func (gen *generator) unique(name string) string {
+	unique := name
+	for i := 2; gen.names[unique]; i++ {
+		unique = fmt.Sprintf("%s%d", name, i)
+	}
+	gen.names[unique] = true
+
+	return unique
+}
+
+// initialisms are the words exportName writes in upper case, as in
+// papi.Activation.IgnoreHTTPErrors
+var initialisms = map[string]bool{
+	"CN": true, "DNS": true, "HTTP": true, "HTTPS": true, "ID": true, "IP": true,
+	"SNI": true, "SSL": true, "TLS": true, "TTL": true, "URI": true, "URL": true,
+}
+
+// exportName converts a camelCase name to an exported Go name, e.g.
+// forwardHostHeader to ForwardHostHeader and originSni to OriginSNI
+func exportName(name string) string {
+	var exported strings.Builder
+	for _, part := range strings.FieldsFunc(name, isSeparator) {
+		for _, word := range camelWords(part) {
+			if initialisms[strings.ToUpper(word)] {
+				exported.WriteString(strings.ToUpper(word))
+				continue
+			}
+
+			runes := []rune(word)
+			runes[0] = unicode.ToUpper(runes[0])
+			exported.WriteString(string(runes))
+		}
+	}
+
+	return identifier(exported.String())
+}
+
+// camelWords splits a camelCase name into words, e.g. cpCodeList into cp,
+// Code and List
+func camelWords(name string) []string {
+	var words []string
+	runes := []rune(name)
+	start := 0
+	for i := 1; i < len(runes); i++ {
+		if unicode.IsUpper(runes[i]) && !unicode.IsUpper(runes[i-1]) {
+			words = append(words, string(runes[start:i]))
+			start = i
+		}
+	}
+
+	return append(words, string(runes[start:]))
+}
+
+// enumName converts an enum value to a name, e.g. REQUEST_HOST_HEADER to
+// RequestHostHeader and HTTP_DELETE to HTTPDelete
+func enumName(value string) string {
+	var name strings.Builder
+	for _, part := range strings.FieldsFunc(value, isSeparator) {
+		if initialisms[strings.ToUpper(part)] {
+			name.WriteString(strings.ToUpper(part))
+			continue
+		}
+
+		runes := []rune(strings.ToLower(part))
+		runes[0] = unicode.ToUpper(runes[0])
+		name.WriteString(string(runes))
+	}
+
+	if name.Len() == 0 {
+		return "Empty"
+	}
+
+	return name.String()
+}
+
+// identifier makes sure name is a valid exported identifier
+func identifier(name string) string {
+	if name == "" || !unicode.IsLetter([]rune(name)[0]) {
+		return "X" + name
+	}
+
+	return name
+}
+
+func isSeparator(r rune) bool {
+	return !unicode.IsLetter(r) && !unicode.IsDigit(r)
+}
@@ -0,0 +1,106 @@
+package main
+
+import (
+	"go/parser"
+	"go/token"
+	"io/ioutil"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestGenerate_UpToDate(t *testing.T) {
+	schema, err := ioutil.ReadFile("schemas/v2021-09-22-excerpt.json")
+	assert.NoError(t, err)
+
+	for kind, path := range map[string]string{
+		"behaviors": "../../behaviors/behaviors_gen.go",
+		"criteria":  "../../criteria/criteria_gen.go",
+	} {
+		generated, err := generate(schema, kind, "v2021-09-22")
+		assert.NoError(t, err)
+
+		committed, err := ioutil.ReadFile(path)
+		assert.NoError(t, err)
+		assert.Equal(t, string(committed), string(generated), "%s is out of date, run go generate", path)
+	}
+}
+
+func TestGenerate(t *testing.T) {
+	schema := []byte(`{
+		"definitions": {
+			"hostnames": {"type": "array", "items": {"type": "string"}},
+			"catalog": {
+				"behaviors": {
+					"edgeRedirector": {
+						"type": "object",
+						"properties": {
+							"name": {"enum": ["edgeRedirector"]},
+							"options": {
+								"type": "object",
+								"properties": {
+									"name": {"type": "string"},
+									"destinationHostnames": {"$ref": "#/definitions/hostnames"},
+									"responseCode": {"type": ["integer", "null"]},
+									"rules": {
+										"type": "array",
+										"items": {
+											"type": "object",
+											"properties": {
+												"matchUrl": {"type": "string", "description": "The URL to match."},
+												"statusCode": {"type": "integer", "enum": [301, 302]}
+											}
+										}
+									},
+									"mode": {"type": "string", "enum": ["ALWAYS", "HTTP-ONLY", ""]}
+								}
+							}
+						}
+					}
+				}
+			}
+		}
+	}`)
+
+	source, err := generate(schema, "behaviors", "v2020-01-01")
+	assert.NoError(t, err)
+
+	_, err = parser.ParseFile(token.NewFileSet(), "behaviors_gen.go", source, parser.ParseComments)
+	assert.NoError(t, err)
+
+	code := string(source)
+	assert.Contains(t, code, "// Code generated by papi-v1/internal/gen from rule format v2020-01-01. DO NOT EDIT.")
+	assert.Contains(t, code, "type EdgeRedirector struct {")
+	assert.Regexp(t, `NameOption\s+string\s+`+"`json:\"name,omitempty\"`", code)
+	assert.Regexp(t, `DestinationHostnames\s+\[\]string`, code)
+	assert.Regexp(t, `ResponseCode\s+\*int`, code)
+	assert.Regexp(t, `Rules\s+\[\]EdgeRedirectorRules`, code)
+	assert.Contains(t, code, "// The URL to match.")
+	assert.Regexp(t, `MatchURL\s+string`, code)
+	assert.Regexp(t, `StatusCode\s+\*int`, code)
+	assert.Contains(t, code, `EdgeRedirectorModeHTTPOnly EdgeRedirectorModeValue = "HTTP-ONLY"`)
+	assert.Contains(t, code, `EdgeRedirectorModeEmpty EdgeRedirectorModeValue = ""`)
+	assert.Contains(t, code, `"edgeRedirector": func() Behavior { return &EdgeRedirector{} },`)
+}
+
+func TestGenerate_Errors(t *testing.T) {
+	_, err := generate([]byte(`{}`), "rules", "v2020-01-01")
+	assert.Error(t, err)
+
+	_, err = generate([]byte(`{"definitions": {}}`), "criteria", "v2020-01-01")
+	assert.Error(t, err)
+
+	_, err = generate([]byte(`{"definitions": {"catalog": {"criteria": {"path": {"$ref": "#/definitions/missing"}}}}}`), "criteria", "v2020-01-01")
+	assert.EqualError(t, err, "criteria path: $ref #/definitions/missing not found")
+}
+
+func TestExportName(t *testing.T) {
+	assert.Equal(t, "ForwardHostHeader", exportName("forwardHostHeader"))
+	assert.Equal(t, "OriginSNI", exportName("originSni"))
+	assert.Equal(t, "CpCode", exportName("cpCode"))
+	assert.Equal(t, "Http2", exportName("http2"))
+	assert.Equal(t, "X3xx", exportName("3xx"))
+	assert.Equal(t, "RequestHostHeader", enumName("REQUEST_HOST_HEADER"))
+	assert.Equal(t, "Base64Decode", enumName("BASE_64_DECODE"))
+	assert.Equal(t, "HTTPDelete", enumName("HTTP_DELETE"))
+}
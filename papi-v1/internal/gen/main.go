@@ -0,0 +1,91 @@
+// Command gen generates typed builders for PAPI behaviors and criteria from
+// a rule format schema
+//
+// It is run by go generate in the behaviors and criteria packages, from a
+// schema in schemas/:
+//
+//	go run ../internal/gen -kind behaviors -format v2021-09-22 -schema ../internal/gen/schemas/v2021-09-22-excerpt.json -out behaviors_gen.go
+//
+// schemas/v2021-09-22-excerpt.json is not the full v2021-09-22 schema, only
+// an excerpt with 9 commonly used behaviors and 7 criteria, so the generated
+// packages only cover those. To cover a whole rule format, fetch its full
+// schema with -product, which saves it to -schema before generating, then
+// point the go:generate directives at it and remove the excerpt:
+//
+//	go run ./internal/gen -kind behaviors -format v2021-09-22 -product prd_SPM -edgerc ~/.edgerc -section papi \
+//		-schema internal/gen/schemas/v2021-09-22.json -out behaviors/behaviors_gen.go
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"io/ioutil"
+	"os"
+
+	"github.com/akamai/AkamaiOPEN-edgegrid-golang/edgegrid"
+	papi "github.com/akamai/AkamaiOPEN-edgegrid-golang/papi-v1"
+)
+
+func main() {
+	kindName := flag.String("kind", "", "behaviors or criteria")
+	ruleFormat := flag.String("format", "", "rule format of the schema, e.g. v2021-09-22")
+	schemaPath := flag.String("schema", "", "path of the rule format schema")
+	out := flag.String("out", "", "path of the generated file")
+	product := flag.String("product", "", "fetch the schema for this product to -schema before generating")
+	edgerc := flag.String("edgerc", "", "edgerc file used with -product, defaults to ~/.edgerc")
+	section := flag.String("section", "default", "edgerc section used with -product")
+	flag.Parse()
+
+	if *kindName == "" || *ruleFormat == "" || *schemaPath == "" || *out == "" {
+		flag.Usage()
+		os.Exit(2)
+	}
+
+	if *product != "" {
+		if err := fetchSchema(*edgerc, *section, *product, *ruleFormat, *schemaPath); err != nil {
+			fail(err)
+		}
+	}
+
+	schema, err := ioutil.ReadFile(*schemaPath)
+	if err != nil {
+		fail(err)
+	}
+
+	source, err := generate(schema, *kindName, *ruleFormat)
+	if err != nil {
+		fail(err)
+	}
+
+	if err := ioutil.WriteFile(*out, source, 0644); err != nil {
+		fail(err)
+	}
+}
+
+// fetchSchema saves the schema of a product and rule format to path
+func fetchSchema(edgerc string, section string, product string, ruleFormat string, path string) error {
+	config, err := edgegrid.Init(edgerc, section)
+	if err != nil {
+		return err
+	}
+	papi.Init(config)
+
+	schema, err := papi.NewRuleFormats().GetSchemaJSON(product, ruleFormat, "")
+	if err != nil {
+		return err
+	}
+
+	var indented bytes.Buffer
+	if err := json.Indent(&indented, schema, "", "  "); err != nil {
+		return err
+	}
+
+	return ioutil.WriteFile(path, indented.Bytes(), 0644)
+}
+
+func fail(err error) {
+	fmt.Fprintln(os.Stderr, "gen:", err)
+	os.Exit(1)
+}
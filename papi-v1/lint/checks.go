@@ -0,0 +1,420 @@
+package lint
+
+import (
+	"fmt"
+	"regexp"
+	"sort"
+	"strings"
+
+	papi "github.com/akamai/AkamaiOPEN-edgegrid-golang/papi-v1"
+)
+
+// RepeatableBehaviors are the behaviors the duplicate-behavior check allows
+// more than once in the same rule
+var RepeatableBehaviors = map[string]bool{
+	"modifyIncomingRequestHeader":  true,
+	"modifyIncomingResponseHeader": true,
+	"modifyOutgoingRequestHeader":  true,
+	"modifyOutgoingResponseHeader": true,
+	"setVariable":                  true,
+}
+
+// StaticExtensions are the file extensions treated as static content by
+// the no-store-static-content check
+var StaticExtensions = []string{
+	"css", "js", "jpg", "jpeg", "png", "gif", "svg", "webp", "ico", "bmp",
+	"woff", "woff2", "ttf", "otf", "eot", "mp4", "webm", "mp3", "pdf",
+}
+
+// staticPaths are path segments treated as static content
+var staticPaths = []string{"/static/", "/images/", "/img/", "/assets/", "/css/", "/js/", "/fonts/"}
+
+// staticContentTypes are content type prefixes treated as static content
+var staticContentTypes = []string{"image/", "video/", "audio/", "font/", "text/css", "application/javascript", "text/javascript"}
+
+// variableReference matches a user variable in an option value
+var variableReference = regexp.MustCompile(`\{\{\s*user\.(PMUSER_[A-Za-z0-9_]+)\s*\}\}`)
+
+// BuiltinChecks returns the checks added by NewLinter
+//
+//	origin-without-cpcode    origin behaviors with no cpCode behavior in scope
+//	no-store-static-content  caching set to NO_STORE under static content criteria
+//	duplicate-behavior       behaviors repeated in the same rule
+//	unreachable-rule         rules whose criteria, with their ancestors', can never match
+//	insecure-origin-tls      origins with SNI disabled or accepting any certificate name
+//	unused-variable          variables declared but never used
+func BuiltinChecks() []Check {
+	return []Check{
+		{
+			Name:        "origin-without-cpcode",
+			Description: "origin behaviors with no cpCode behavior in scope",
+			Severity:    SeverityError,
+			Run:         checkOriginWithoutCpCode,
+		},
+		{
+			Name:        "no-store-static-content",
+			Description: "caching set to NO_STORE under static content criteria",
+			Severity:    SeverityWarning,
+			Run:         checkNoStoreStaticContent,
+		},
+		{
+			Name:        "duplicate-behavior",
+			Description: "behaviors repeated in the same rule",
+			Severity:    SeverityWarning,
+			Run:         checkDuplicateBehavior,
+		},
+		{
+			Name:        "unreachable-rule",
+			Description: "rules whose criteria, with their ancestors', can never match",
+			Severity:    SeverityWarning,
+			Run:         checkUnreachableRule,
+		},
+		{
+			Name:        "insecure-origin-tls",
+			Description: "origins with SNI disabled or accepting any certificate name",
+			Severity:    SeverityWarning,
+			Run:         checkInsecureOriginTLS,
+		},
+		{
+			Name:        "unused-variable",
+			Description: "variables declared but never used",
+			Severity:    SeverityInfo,
+			Run:         checkUnusedVariable,
+		},
+	}
+}
+
+func checkOriginWithoutCpCode(rules *papi.Rules) Findings {
+	var findings Findings
+	Walk(rules, func(path string, rule *papi.Rule, ancestors []*papi.Rule) {
+		for _, behavior := range rule.Behaviors {
+			if behavior.Name == "origin" && len(inScope(rule, ancestors, "cpCode")) == 0 {
+				findings = append(findings, &Finding{
+					Path:    path,
+					Name:    behavior.Name,
+					Message: "origin has no cpCode behavior in this rule or its parents",
+				})
+			}
+		}
+	})
+
+	return findings
+}
+
+func checkNoStoreStaticContent(rules *papi.Rules) Findings {
+	var findings Findings
+	Walk(rules, func(path string, rule *papi.Rule, ancestors []*papi.Rule) {
+		static := ""
+		for _, scope := range append(ancestors[:len(ancestors):len(ancestors)], rule) {
+			for _, criteria := range scope.Criteria {
+				if isStaticCriteria(criteria) {
+					static = criteria.Name
+				}
+			}
+		}
+		if static == "" {
+			return
+		}
+
+		for _, behavior := range rule.Behaviors {
+			if behavior.Name == "caching" && strings.EqualFold(stringOption(behavior.Options, "behavior"), "NO_STORE") {
+				findings = append(findings, &Finding{
+					Path:    path,
+					Name:    behavior.Name,
+					Message: fmt.Sprintf("static content (%s criteria) is not cached", static),
+				})
+			}
+		}
+	})
+
+	return findings
+}
+
+// isStaticCriteria reports whether criteria matches static content
+func isStaticCriteria(criteria *papi.Criteria) bool {
+	operator := stringOption(criteria.Options, "matchOperator")
+	if strings.Contains(operator, "NOT") {
+		return false
+	}
+
+	values := stringsOption(criteria.Options, "values")
+	switch criteria.Name {
+	case "fileExtension":
+		for _, value := range values {
+			if isStaticExtension(value) {
+				return true
+			}
+		}
+	case "path":
+		for _, value := range values {
+			value = strings.ToLower(value)
+			for _, static := range staticPaths {
+				if strings.Contains(value, static) {
+					return true
+				}
+			}
+			if dot := strings.LastIndex(value, "."); dot != -1 && isStaticExtension(value[dot+1:]) {
+				return true
+			}
+		}
+	case "contentType":
+		for _, value := range values {
+			value = strings.ToLower(value)
+			for _, static := range staticContentTypes {
+				if strings.HasPrefix(value, static) {
+					return true
+				}
+			}
+		}
+	}
+
+	return false
+}
+
+func isStaticExtension(extension string) bool {
+	extension = strings.ToLower(strings.TrimPrefix(extension, "."))
+	for _, static := range StaticExtensions {
+		if extension == static {
+			return true
+		}
+	}
+
+	return false
+}
+
+func checkDuplicateBehavior(rules *papi.Rules) Findings {
+	var findings Findings
+	Walk(rules, func(path string, rule *papi.Rule, ancestors []*papi.Rule) {
+		counts := map[string]int{}
+		for _, behavior := range rule.Behaviors {
+			counts[behavior.Name]++
+		}
+
+		for _, behavior := range rule.Behaviors {
+			count := counts[behavior.Name]
+			if count < 2 || RepeatableBehaviors[behavior.Name] {
+				continue
+			}
+			counts[behavior.Name] = 0
+
+			findings = append(findings, &Finding{
+				Path:    path,
+				Name:    behavior.Name,
+				Message: fmt.Sprintf("behavior appears %d times in the same rule", count),
+			})
+		}
+	})
+
+	return findings
+}
+
+// exactCriteria are the criteria the unreachable-rule check compares, with
+// the option holding their values
+var exactCriteria = map[string]string{
+	"requestProtocol": "value",
+	"requestMethod":   "value",
+	"hostname":        "values",
+	"fileExtension":   "values",
+}
+
+func checkUnreachableRule(rules *papi.Rules) Findings {
+	var findings Findings
+	var unreachable []string
+	Walk(rules, func(path string, rule *papi.Rule, ancestors []*papi.Rule) {
+		for _, prefix := range unreachable {
+			if strings.HasPrefix(path, prefix+"/") {
+				return
+			}
+		}
+
+		// The values each criterion allows, intersected along the path
+		allowed := map[string]map[string]bool{}
+		for _, scope := range append(ancestors[:len(ancestors):len(ancestors)], rule) {
+			if scope.CriteriaMustSatisfy == papi.RuleCriteriaMustSatisfyAny && len(scope.Criteria) > 1 {
+				continue
+			}
+
+			for _, criteria := range scope.Criteria {
+				values, ok := criteriaValues(criteria)
+				if !ok {
+					continue
+				}
+
+				if previous, ok := allowed[criteria.Name]; ok {
+					for value := range values {
+						if !previous[value] {
+							delete(values, value)
+						}
+					}
+				}
+				allowed[criteria.Name] = values
+			}
+		}
+
+		names := make([]string, 0, len(allowed))
+		for name, values := range allowed {
+			if len(values) == 0 {
+				names = append(names, name)
+			}
+		}
+		if len(names) == 0 {
+			return
+		}
+
+		sort.Strings(names)
+		unreachable = append(unreachable, path)
+		findings = append(findings, &Finding{
+			Path:    path,
+			Name:    names[0],
+			Message: fmt.Sprintf("%s criteria can never match together with the parent rules", strings.Join(names, ", ")),
+		})
+	})
+
+	return findings
+}
+
+// criteriaValues returns the set of values an exact criterion matches, or
+// false if the criterion is not exact, negated or uses wildcards
+func criteriaValues(criteria *papi.Criteria) (map[string]bool, bool) {
+	option, ok := exactCriteria[criteria.Name]
+	if !ok {
+		return nil, false
+	}
+
+	operator := stringOption(criteria.Options, "matchOperator")
+	if operator != "" && operator != "IS_ONE_OF" && operator != "IS" {
+		return nil, false
+	}
+
+	var values []string
+	if option == "value" {
+		value := stringOption(criteria.Options, option)
+		if value == "" {
+			return nil, false
+		}
+		values = []string{value}
+	} else {
+		values = stringsOption(criteria.Options, option)
+	}
+
+	set := map[string]bool{}
+	for _, value := range values {
+		if strings.ContainsAny(value, "*?") {
+			return nil, false
+		}
+		set[strings.ToLower(value)] = true
+	}
+
+	return set, true
+}
+
+func checkInsecureOriginTLS(rules *papi.Rules) Findings {
+	var findings Findings
+	Walk(rules, func(path string, rule *papi.Rule, ancestors []*papi.Rule) {
+		for _, behavior := range rule.Behaviors {
+			if behavior.Name != "origin" {
+				continue
+			}
+
+			if sni, ok := behavior.Options["originSni"].(bool); ok && !sni {
+				findings = append(findings, &Finding{
+					Path:    path,
+					Name:    behavior.Name,
+					Message: "originSni is disabled; the origin cannot select a certificate for the hostname",
+				})
+			}
+
+			if stringOption(behavior.Options, "verificationMode") == "CUSTOM" {
+				for _, value := range stringsOption(behavior.Options, "customValidCnValues") {
+					if value == "*" {
+						findings = append(findings, &Finding{
+							Path:    path,
+							Name:    behavior.Name,
+							Message: "customValidCnValues accepts a certificate for any name",
+						})
+						break
+					}
+				}
+			}
+		}
+	})
+
+	return findings
+}
+
+func checkUnusedVariable(rules *papi.Rules) Findings {
+	used := map[string]bool{}
+	Walk(rules, func(path string, rule *papi.Rule, ancestors []*papi.Rule) {
+		for _, behavior := range rule.Behaviors {
+			collectVariables(behavior.Options, used)
+		}
+		for _, criteria := range rule.Criteria {
+			collectVariables(criteria.Options, used)
+			if criteria.Name == "matchVariable" {
+				used[stringOption(criteria.Options, "variableName")] = true
+			}
+		}
+		for _, variable := range rule.Variables {
+			collectVariables(variable.Value, used)
+		}
+	})
+
+	var findings Findings
+	Walk(rules, func(path string, rule *papi.Rule, ancestors []*papi.Rule) {
+		for _, variable := range rule.Variables {
+			if !used[variable.Name] {
+				findings = append(findings, &Finding{
+					Path:    path,
+					Name:    variable.Name,
+					Message: "variable is declared but never used",
+				})
+			}
+		}
+	})
+
+	return findings
+}
+
+// collectVariables adds the user variables referenced in value, searching
+// nested options
+func collectVariables(value interface{}, used map[string]bool) {
+	switch value := value.(type) {
+	case string:
+		for _, match := range variableReference.FindAllStringSubmatch(value, -1) {
+			used[match[1]] = true
+		}
+	case papi.OptionValue:
+		collectVariables(map[string]interface{}(value), used)
+	case map[string]interface{}:
+		for _, nested := range value {
+			collectVariables(nested, used)
+		}
+	case []interface{}:
+		for _, nested := range value {
+			collectVariables(nested, used)
+		}
+	}
+}
+
+func stringOption(options papi.OptionValue, key string) string {
+	value, _ := options[key].(string)
+	return value
+}
+
+// stringsOption returns an option holding a list of strings
+func stringsOption(options papi.OptionValue, key string) []string {
+	switch value := options[key].(type) {
+	case []string:
+		return value
+	case []interface{}:
+		values := make([]string, 0, len(value))
+		for _, item := range value {
+			if s, ok := item.(string); ok {
+				values = append(values, s)
+			}
+		}
+		return values
+	}
+
+	return nil
+}
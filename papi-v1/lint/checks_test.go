@@ -0,0 +1,139 @@
+package lint
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func runCheck(t *testing.T, name string, data string) Findings {
+	for _, check := range BuiltinChecks() {
+		if check.Name == name {
+			return check.Run(rulesFromJSON(t, data))
+		}
+	}
+
+	t.Fatalf("no check %s", name)
+	return nil
+}
+
+func findingPaths(findings Findings) []string {
+	paths := []string{}
+	for _, finding := range findings {
+		paths = append(paths, finding.Path)
+	}
+
+	return paths
+}
+
+func TestCheck_OriginWithoutCpCode(t *testing.T) {
+	findings := runCheck(t, "origin-without-cpcode", `{"rules": {
+		"name": "default",
+		"children": [
+			{"name": "API", "behaviors": [{"name": "origin", "options": {}}]},
+			{"name": "Images", "behaviors": [{"name": "cpCode", "options": {}}], "children": [
+				{"name": "Thumbnails", "behaviors": [{"name": "origin", "options": {}}]}
+			]}
+		]
+	}}`)
+
+	assert.Equal(t, []string{"default/API"}, findingPaths(findings))
+}
+
+func TestCheck_NoStoreStaticContent(t *testing.T) {
+	findings := runCheck(t, "no-store-static-content", `{"rules": {
+		"name": "default",
+		"behaviors": [{"name": "caching", "options": {"behavior": "NO_STORE"}}],
+		"children": [
+			{"name": "Static", "criteria": [{"name": "fileExtension", "options": {"matchOperator": "IS_ONE_OF", "values": ["css", "js"]}}], "children": [
+				{"name": "No cache", "behaviors": [{"name": "caching", "options": {"behavior": "NO_STORE"}}]}
+			]},
+			{"name": "Assets", "criteria": [{"name": "path", "options": {"matchOperator": "MATCHES_ONE_OF", "values": ["/assets/*"]}}], "behaviors": [{"name": "caching", "options": {"behavior": "NO_STORE"}}]},
+			{"name": "Dynamic", "criteria": [{"name": "fileExtension", "options": {"matchOperator": "IS_NOT_ONE_OF", "values": ["css"]}}], "behaviors": [{"name": "caching", "options": {"behavior": "NO_STORE"}}]},
+			{"name": "Images", "criteria": [{"name": "contentType", "options": {"matchOperator": "IS_ONE_OF", "values": ["image/*"]}}], "behaviors": [{"name": "caching", "options": {"behavior": "MAX_AGE", "ttl": "7d"}}]}
+		]
+	}}`)
+
+	assert.Equal(t, []string{"default/Static/No cache", "default/Assets"}, findingPaths(findings))
+	assert.Equal(t, "static content (fileExtension criteria) is not cached", findings[0].Message)
+}
+
+func TestCheck_DuplicateBehavior(t *testing.T) {
+	findings := runCheck(t, "duplicate-behavior", `{"rules": {
+		"name": "default",
+		"behaviors": [
+			{"name": "caching", "options": {}},
+			{"name": "modifyOutgoingResponseHeader", "options": {}},
+			{"name": "caching", "options": {}},
+			{"name": "modifyOutgoingResponseHeader", "options": {}},
+			{"name": "caching", "options": {}}
+		]
+	}}`)
+
+	assert.Len(t, findings, 1)
+	assert.Equal(t, "caching", findings[0].Name)
+	assert.Equal(t, "behavior appears 3 times in the same rule", findings[0].Message)
+}
+
+func TestCheck_UnreachableRule(t *testing.T) {
+	findings := runCheck(t, "unreachable-rule", `{"rules": {
+		"name": "default",
+		"children": [
+			{"name": "HTTPS", "criteria": [{"name": "requestProtocol", "options": {"value": "HTTPS"}}], "children": [
+				{"name": "HTTP", "criteria": [{"name": "requestProtocol", "options": {"value": "HTTP"}}], "children": [
+					{"name": "Nested"}
+				]},
+				{"name": "Either", "criteriaMustSatisfy": "any", "criteria": [
+					{"name": "requestProtocol", "options": {"value": "HTTP"}},
+					{"name": "path", "options": {"matchOperator": "MATCHES_ONE_OF", "values": ["/a"]}}
+				]}
+			]},
+			{"name": "Hosts", "criteria": [{"name": "hostname", "options": {"matchOperator": "IS_ONE_OF", "values": ["a.example.com", "b.example.com"]}}], "children": [
+				{"name": "B", "criteria": [{"name": "hostname", "options": {"matchOperator": "IS_ONE_OF", "values": ["B.example.com"]}}]},
+				{"name": "C", "criteria": [{"name": "hostname", "options": {"matchOperator": "IS_ONE_OF", "values": ["c.example.com"]}}]},
+				{"name": "Wildcard", "criteria": [{"name": "hostname", "options": {"matchOperator": "IS_ONE_OF", "values": ["*.example.org"]}}]}
+			]}
+		]
+	}}`)
+
+	assert.Equal(t, []string{"default/HTTPS/HTTP", "default/Hosts/C"}, findingPaths(findings))
+	assert.Equal(t, "requestProtocol", findings[0].Name)
+}
+
+func TestCheck_InsecureOriginTLS(t *testing.T) {
+	findings := runCheck(t, "insecure-origin-tls", `{"rules": {
+		"name": "default",
+		"behaviors": [{"name": "origin", "options": {"originSni": true, "verificationMode": "PLATFORM_SETTINGS"}}],
+		"children": [
+			{"name": "Legacy", "behaviors": [{"name": "origin", "options": {"originSni": false, "verificationMode": "CUSTOM", "customValidCnValues": ["{{Origin Hostname}}", "*"]}}]}
+		]
+	}}`)
+
+	assert.Equal(t, []string{"default/Legacy", "default/Legacy"}, findingPaths(findings))
+}
+
+func TestCheck_UnusedVariable(t *testing.T) {
+	findings := runCheck(t, "unused-variable", `{"rules": {
+		"name": "default",
+		"variables": [
+			{"name": "PMUSER_ORIGIN", "value": ""},
+			{"name": "PMUSER_COUNTRY", "value": ""},
+			{"name": "PMUSER_DERIVED", "value": "{{user.PMUSER_COUNTRY}}"},
+			{"name": "PMUSER_FLAG", "value": ""},
+			{"name": "PMUSER_UNUSED", "value": ""}
+		],
+		"behaviors": [
+			{"name": "origin", "options": {"hostname": "{{user.PMUSER_ORIGIN}}"}},
+			{"name": "setVariable", "options": {"variableName": "PMUSER_UNUSED", "valueSource": "EXPRESSION", "variableValue": "x"}}
+		],
+		"children": [
+			{"name": "Flagged", "criteria": [{"name": "matchVariable", "options": {"variableName": "PMUSER_FLAG", "matchOperator": "IS", "variableExpression": "on"}}]}
+		]
+	}}`)
+
+	var names []string
+	for _, finding := range findings {
+		names = append(names, finding.Name)
+	}
+	assert.Equal(t, []string{"PMUSER_DERIVED", "PMUSER_UNUSED"}, names)
+}
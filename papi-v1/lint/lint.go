@@ -0,0 +1,239 @@
+// Package lint runs best-practice checks over PAPI rule trees
+//
+// A Linter holds a set of checks, each producing findings with the path of
+// the rule they apply to and a severity. NewLinter returns a linter with the
+// built-in checks; custom checks are added with Register:
+//
+//	linter := lint.NewLinter()
+//	linter.Register(lint.Check{
+//		Name:     "no-debug-headers",
+//		Severity: lint.SeverityWarning,
+//		Run: func(rules *papi.Rules) lint.Findings {
+//			var findings lint.Findings
+//			lint.Walk(rules, func(path string, rule *papi.Rule, ancestors []*papi.Rule) {
+//				...
+//			})
+//			return findings
+//		},
+//	})
+//
+//	for _, finding := range linter.Lint(rules) {
+//		fmt.Println(finding)
+//	}
+package lint
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"sync"
+
+	papi "github.com/akamai/AkamaiOPEN-edgegrid-golang/papi-v1"
+)
+
+// SeverityValue is used to create an "enum" of possible Finding.Severity values
+type SeverityValue string
+
+const (
+	// SeverityError Finding.Severity value error
+	SeverityError SeverityValue = "error"
+	// SeverityWarning Finding.Severity value warning
+	SeverityWarning SeverityValue = "warning"
+	// SeverityInfo Finding.Severity value info
+	SeverityInfo SeverityValue = "info"
+)
+
+// rank orders severities, most severe first
+func (severity SeverityValue) rank() int {
+	switch severity {
+	case SeverityError:
+		return 0
+	case SeverityWarning:
+		return 1
+	}
+
+	return 2
+}
+
+// Finding is a problem found by a check
+//
+// Path is the slash separated path of rule names, starting at the default
+// rule, as used by papi.Rules.Walk(). Name is the behavior, criteria or
+// variable the finding is about, if any.
+type Finding struct {
+	Check    string        `json:"check"`
+	Severity SeverityValue `json:"severity"`
+	Path     string        `json:"path"`
+	Name     string        `json:"name,omitempty"`
+	Message  string        `json:"message"`
+}
+
+// String returns a single line description of the finding
+func (finding *Finding) String() string {
+	if finding.Name != "" {
+		return fmt.Sprintf("%s: %s [%s] %s: %s", finding.Severity, finding.Path, finding.Name, finding.Check, finding.Message)
+	}
+
+	return fmt.Sprintf("%s: %s %s: %s", finding.Severity, finding.Path, finding.Check, finding.Message)
+}
+
+// Findings is a list of findings
+type Findings []*Finding
+
+// Filter returns the findings at least as severe as severity
+func (findings Findings) Filter(severity SeverityValue) Findings {
+	var filtered Findings
+	for _, finding := range findings {
+		if finding.Severity.rank() <= severity.rank() {
+			filtered = append(filtered, finding)
+		}
+	}
+
+	return filtered
+}
+
+// HasErrors reports whether any finding has SeverityError
+func (findings Findings) HasErrors() bool {
+	return len(findings.Filter(SeverityError)) != 0
+}
+
+// Check is a named check over a rule tree
+//
+// Run returns the check's findings. Check and Severity are filled in from
+// the Check for findings that leave them empty.
+type Check struct {
+	Name        string
+	Description string
+	Severity    SeverityValue
+	Run         func(rules *papi.Rules) Findings
+}
+
+// Linter runs a set of checks
+type Linter struct {
+	mutex  sync.RWMutex
+	checks []Check
+}
+
+// NewLinter creates a Linter with the built-in checks
+//
+// See: BuiltinChecks()
+func NewLinter() *Linter {
+	return &Linter{checks: BuiltinChecks()}
+}
+
+// Register adds a check. Check names must be unique.
+func (linter *Linter) Register(check Check) error {
+	if check.Name == "" || check.Run == nil {
+		return fmt.Errorf("check must have a Name and Run")
+	}
+
+	linter.mutex.Lock()
+	defer linter.mutex.Unlock()
+
+	for _, registered := range linter.checks {
+		if registered.Name == check.Name {
+			return fmt.Errorf("check %q is already registered", check.Name)
+		}
+	}
+
+	linter.checks = append(linter.checks, check)
+
+	return nil
+}
+
+// Disable removes the checks with the given names
+func (linter *Linter) Disable(names ...string) {
+	linter.mutex.Lock()
+	defer linter.mutex.Unlock()
+
+	var checks []Check
+	for _, check := range linter.checks {
+		disabled := false
+		for _, name := range names {
+			disabled = disabled || check.Name == name
+		}
+		if !disabled {
+			checks = append(checks, check)
+		}
+	}
+
+	linter.checks = checks
+}
+
+// Checks returns the registered checks
+func (linter *Linter) Checks() []Check {
+	linter.mutex.RLock()
+	defer linter.mutex.RUnlock()
+
+	return append([]Check(nil), linter.checks...)
+}
+
+// Lint runs every check over the rule tree and returns the findings, most
+// severe first, then by path
+func (linter *Linter) Lint(rules *papi.Rules) Findings {
+	findings := Findings{}
+	if rules == nil || rules.Rule == nil {
+		return findings
+	}
+
+	for _, check := range linter.Checks() {
+		for _, finding := range check.Run(rules) {
+			if finding.Check == "" {
+				finding.Check = check.Name
+			}
+			if finding.Severity == "" {
+				finding.Severity = check.Severity
+			}
+			findings = append(findings, finding)
+		}
+	}
+
+	sort.SliceStable(findings, func(i, j int) bool {
+		if findings[i].Severity.rank() != findings[j].Severity.rank() {
+			return findings[i].Severity.rank() < findings[j].Severity.rank()
+		}
+		if findings[i].Path != findings[j].Path {
+			return findings[i].Path < findings[j].Path
+		}
+		return findings[i].Check < findings[j].Check
+	})
+
+	return findings
+}
+
+// WalkFunc is called by Walk for each rule, with the rule's ancestors
+// starting at the default rule
+type WalkFunc func(path string, rule *papi.Rule, ancestors []*papi.Rule)
+
+// Walk calls fn for every rule in the tree, parents before their children
+func Walk(rules *papi.Rules, fn WalkFunc) {
+	if rules == nil || rules.Rule == nil {
+		return
+	}
+
+	walk(rules.Rule, rules.Rule.Name, nil, fn)
+}
+
+func walk(rule *papi.Rule, path string, ancestors []*papi.Rule, fn WalkFunc) {
+	fn(path, rule, ancestors)
+
+	ancestors = append(ancestors[:len(ancestors):len(ancestors)], rule)
+	for _, child := range rule.Children {
+		walk(child, path+"/"+child.Name, ancestors, fn)
+	}
+}
+
+// inScope returns the behaviors named name that apply to a rule: its own
+// and its ancestors'
+func inScope(rule *papi.Rule, ancestors []*papi.Rule, name string) []*papi.Behavior {
+	var behaviors []*papi.Behavior
+	for _, scope := range append(ancestors[:len(ancestors):len(ancestors)], rule) {
+		for _, behavior := range scope.Behaviors {
+			if strings.EqualFold(behavior.Name, name) {
+				behaviors = append(behaviors, behavior)
+			}
+		}
+	}
+
+	return behaviors
+}
@@ -0,0 +1,81 @@
+package lint
+
+import (
+	"testing"
+
+	"github.com/akamai/AkamaiOPEN-edgegrid-golang/jsonhooks-v1"
+	papi "github.com/akamai/AkamaiOPEN-edgegrid-golang/papi-v1"
+	"github.com/stretchr/testify/assert"
+)
+
+func rulesFromJSON(t *testing.T, data string) *papi.Rules {
+	rules := papi.NewRules()
+	assert.NoError(t, jsonhooks.Unmarshal([]byte(data), rules))
+
+	return rules
+}
+
+func TestLinter_Register(t *testing.T) {
+	rules := rulesFromJSON(t, `{"rules": {
+		"name": "default",
+		"behaviors": [{"name": "cpCode", "options": {"value": {"id": 1}}}],
+		"children": [{"name": "Debug", "behaviors": [{"name": "modifyOutgoingResponseHeader", "options": {"customHeaderName": "X-Debug"}}]}]
+	}}`)
+
+	linter := NewLinter()
+	err := linter.Register(Check{
+		Name:     "no-debug-headers",
+		Severity: SeverityError,
+		Run: func(rules *papi.Rules) Findings {
+			var findings Findings
+			Walk(rules, func(path string, rule *papi.Rule, ancestors []*papi.Rule) {
+				for _, behavior := range rule.Behaviors {
+					if behavior.Options["customHeaderName"] == "X-Debug" {
+						findings = append(findings, &Finding{Path: path, Name: behavior.Name, Message: "debug header"})
+					}
+				}
+			})
+			return findings
+		},
+	})
+	assert.NoError(t, err)
+	assert.Error(t, linter.Register(Check{Name: "no-debug-headers", Run: func(*papi.Rules) Findings { return nil }}))
+	assert.Error(t, linter.Register(Check{Name: "no-run"}))
+
+	findings := linter.Lint(rules)
+	assert.Len(t, findings, 1)
+	assert.Equal(t, "no-debug-headers", findings[0].Check)
+	assert.Equal(t, SeverityError, findings[0].Severity)
+	assert.Equal(t, "default/Debug", findings[0].Path)
+	assert.True(t, findings.HasErrors())
+	assert.Equal(t, "error: default/Debug [modifyOutgoingResponseHeader] no-debug-headers: debug header", findings[0].String())
+
+	linter.Disable("no-debug-headers")
+	assert.Empty(t, linter.Lint(rules))
+	assert.Len(t, linter.Checks(), len(BuiltinChecks()))
+}
+
+func TestLinter_Lint_Order(t *testing.T) {
+	rules := rulesFromJSON(t, `{"rules": {
+		"name": "default",
+		"variables": [{"name": "PMUSER_UNUSED", "value": ""}],
+		"children": [
+			{"name": "B", "behaviors": [{"name": "origin", "options": {}}]},
+			{"name": "A", "behaviors": [{"name": "gzipResponse", "options": {}}, {"name": "gzipResponse", "options": {}}]}
+		]
+	}}`)
+
+	findings := NewLinter().Lint(rules)
+
+	var checks []string
+	for _, finding := range findings {
+		checks = append(checks, string(finding.Severity)+" "+finding.Path+" "+finding.Check)
+	}
+	assert.Equal(t, []string{
+		"error default/B origin-without-cpcode",
+		"warning default/A duplicate-behavior",
+		"info default unused-variable",
+	}, checks)
+
+	assert.Len(t, findings.Filter(SeverityWarning), 2)
+}
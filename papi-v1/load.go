@@ -0,0 +1,313 @@
+package papi
+
+import (
+	"fmt"
+	"strings"
+	"sync"
+)
+
+// DefaultLoadParallelism is the number of concurrent requests made by Load
+// when LoadOptions.Parallelism is not set
+const DefaultLoadParallelism = 4
+
+// LoadOptions chooses the related resources resolved by Load
+//
+// Decoding a property or CP code only sets the IDs of its group and
+// contract. Load fetches the chosen resources, each distinct resource once,
+// making at most Parallelism requests at a time.
+type LoadOptions struct {
+	// Group populates Property.Group or CpCodes.Group
+	Group bool
+	// Contract populates Property.Contract or CpCodes.Contract
+	Contract bool
+	// Product populates Property.Product
+	Product bool
+	// LatestVersion populates Property.Latest
+	LatestVersion bool
+	// Parallelism is the maximum number of concurrent requests. Defaults to
+	// DefaultLoadParallelism
+	Parallelism int
+}
+
+// LoadErrors are the errors from resolving related resources
+type LoadErrors []error
+
+func (errs LoadErrors) Error() string {
+	messages := make([]string, 0, len(errs))
+	for _, err := range errs {
+		messages = append(messages, err.Error())
+	}
+
+	return strings.Join(messages, "; ")
+}
+
+// Load resolves the related resources chosen by options for every property
+// in the collection
+//
+// All errors are returned as LoadErrors; resources that could be resolved
+// are populated even if others fail.
+func (properties *Properties) Load(options LoadOptions, correlationid string) error {
+	return loadProperties(properties.Properties.Items, options, correlationid)
+}
+
+// Load resolves the related resources chosen by options
+//
+// See: Properties.Load()
+func (property *Property) Load(options LoadOptions, correlationid string) error {
+	return loadProperties([]*Property{property}, options, correlationid)
+}
+
+// Load resolves the group and contract of the CP codes, if chosen by options
+//
+// See: Properties.Load()
+func (cpcodes *CpCodes) Load(options LoadOptions, correlationid string) error {
+	loader := newLoader(options.Parallelism)
+	lookups := loader.fetchLookups(options, nil, correlationid)
+	loader.wait()
+
+	if options.Group {
+		group, err := lookups.group(cpcodes.GroupID)
+		if err != nil {
+			loader.fail(fmt.Errorf("cpcodes: %w", err))
+		} else if group != nil {
+			cpcodes.Group = group
+		}
+	}
+
+	if options.Contract {
+		contract, err := lookups.contract(cpcodes.ContractID)
+		if err != nil {
+			loader.fail(fmt.Errorf("cpcodes: %w", err))
+		} else if contract != nil {
+			cpcodes.Contract = contract
+		}
+	}
+
+	return loader.err()
+}
+
+func loadProperties(properties []*Property, options LoadOptions, correlationid string) error {
+	loader := newLoader(options.Parallelism)
+
+	var contractIDs []string
+	if options.Product {
+		for _, property := range properties {
+			contractIDs = append(contractIDs, property.ContractID)
+		}
+	}
+
+	lookups := loader.fetchLookups(options, contractIDs, correlationid)
+
+	latest := make([]*Version, len(properties))
+	if options.LatestVersion {
+		for key, property := range properties {
+			key, property := key, property
+			loader.run(fmt.Sprintf("version:%d", key), func() error {
+				version, err := property.GetLatestVersion("", correlationid)
+				if err != nil {
+					return fmt.Errorf("property %s: latest version: %w", property.PropertyID, err)
+				}
+				latest[key] = version
+				return nil
+			})
+		}
+	}
+
+	loader.wait()
+
+	for key, property := range properties {
+		if options.Group {
+			if group, err := lookups.group(property.GroupID); err != nil {
+				loader.fail(fmt.Errorf("property %s: %w", property.PropertyID, err))
+			} else if group != nil {
+				property.Group = group
+			}
+		}
+
+		if options.Contract {
+			if contract, err := lookups.contract(property.ContractID); err != nil {
+				loader.fail(fmt.Errorf("property %s: %w", property.PropertyID, err))
+			} else if contract != nil {
+				property.Contract = contract
+			}
+		}
+
+		if options.Product {
+			if product, err := lookups.product(property.ContractID, property.ProductID); err != nil {
+				loader.fail(fmt.Errorf("property %s: %w", property.PropertyID, err))
+			} else if product != nil {
+				property.Product = product
+			}
+		}
+
+		if latest[key] != nil {
+			property.Latest = latest[key]
+		}
+	}
+
+	return loader.err()
+}
+
+// loader runs fetches concurrently, each distinct fetch once, and collects
+// their errors
+type loader struct {
+	semaphore chan struct{}
+	waitGroup sync.WaitGroup
+	mutex     sync.Mutex
+	started   map[string]bool
+	errs      LoadErrors
+}
+
+func newLoader(parallelism int) *loader {
+	if parallelism <= 0 {
+		parallelism = DefaultLoadParallelism
+	}
+
+	return &loader{
+		semaphore: make(chan struct{}, parallelism),
+		started:   map[string]bool{},
+	}
+}
+
+// run starts fetch unless a fetch with the same key was started
+func (loader *loader) run(key string, fetch func() error) {
+	loader.mutex.Lock()
+	defer loader.mutex.Unlock()
+
+	if loader.started[key] {
+		return
+	}
+	loader.started[key] = true
+
+	loader.waitGroup.Add(1)
+	go func() {
+		defer loader.waitGroup.Done()
+
+		loader.semaphore <- struct{}{}
+		defer func() { <-loader.semaphore }()
+
+		if err := fetch(); err != nil {
+			loader.fail(err)
+		}
+	}()
+}
+
+func (loader *loader) wait() {
+	loader.waitGroup.Wait()
+}
+
+func (loader *loader) fail(err error) {
+	loader.mutex.Lock()
+	defer loader.mutex.Unlock()
+
+	loader.errs = append(loader.errs, err)
+}
+
+// errorCount returns the number of errors collected so far
+func (loader *loader) errorCount() int {
+	loader.mutex.Lock()
+	defer loader.mutex.Unlock()
+
+	return len(loader.errs)
+}
+
+func (loader *loader) err() error {
+	if len(loader.errs) == 0 {
+		return nil
+	}
+
+	return loader.errs
+}
+
+// loadLookups are the collections fetched by a loader to resolve IDs from
+//
+// A lookup whose collection failed to load finds nothing, without an error,
+// as the loader already has the error.
+type loadLookups struct {
+	groups    *Groups
+	contracts *Contracts
+	products  map[string]*Products
+	mutex     sync.Mutex
+}
+
+// fetchLookups starts fetching the collections needed for options, with the
+// products of each of contractIDs
+func (loader *loader) fetchLookups(options LoadOptions, contractIDs []string, correlationid string) *loadLookups {
+	lookups := &loadLookups{products: map[string]*Products{}}
+
+	if options.Group {
+		loader.run("groups", func() error {
+			groups := NewGroups()
+			if err := groups.GetGroups(correlationid); err != nil {
+				return fmt.Errorf("groups: %w", err)
+			}
+			lookups.groups = groups
+			return nil
+		})
+	}
+
+	if options.Contract {
+		loader.run("contracts", func() error {
+			contracts := NewContracts()
+			if err := contracts.GetContracts(correlationid); err != nil {
+				return fmt.Errorf("contracts: %w", err)
+			}
+			lookups.contracts = contracts
+			return nil
+		})
+	}
+
+	for _, contractID := range contractIDs {
+		contractID := contractID
+		loader.run("products:"+contractID, func() error {
+			contract := NewContract(NewContracts())
+			contract.ContractID = contractID
+
+			products := NewProducts()
+			if err := products.GetProducts(contract, correlationid); err != nil {
+				return fmt.Errorf("products of %s: %w", contractID, err)
+			}
+
+			lookups.mutex.Lock()
+			lookups.products[contractID] = products
+			lookups.mutex.Unlock()
+			return nil
+		})
+	}
+
+	return lookups
+}
+
+func (lookups *loadLookups) group(id string) (*Group, error) {
+	if lookups.groups == nil {
+		return nil, nil
+	}
+
+	return lookups.groups.FindGroup(id)
+}
+
+func (lookups *loadLookups) contract(id string) (*Contract, error) {
+	if lookups.contracts == nil {
+		return nil, nil
+	}
+
+	return lookups.contracts.FindContract(id)
+}
+
+func (lookups *loadLookups) product(contractID, id string) (*Product, error) {
+	products, ok := lookups.products[contractID]
+	if !ok {
+		return nil, nil
+	}
+
+	return products.FindProduct(id)
+}
+
+// signalComplete sends on a Complete channel without blocking when the
+// channel is already full because nobody read it
+func signalComplete(complete chan bool, value bool) {
+	select {
+	case complete <- value:
+	default:
+	}
+}
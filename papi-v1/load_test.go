@@ -0,0 +1,132 @@
+package papi
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"gopkg.in/h2non/gock.v1"
+)
+
+func mockLoadProperties() *Properties {
+	gock.New("https://akaa-baseurl-xxxxxxxxxxx-xxxxxxxxxxxxx.luna.akamaiapis.net").
+		Get("/papi/v1/properties").
+		MatchParam("contractId", "ctr_1").
+		MatchParam("groupId", "grp_1").
+		Reply(200).
+		SetHeader("Content-Type", "application/json").
+		BodyString(`{"properties": {"items": [
+			{"propertyId": "prp_1", "propertyName": "a.example.com", "contractId": "ctr_1", "groupId": "grp_1", "productId": "prd_Fresca", "latestVersion": 3},
+			{"propertyId": "prp_2", "propertyName": "b.example.com", "contractId": "ctr_1", "groupId": "grp_1", "productId": "prd_Site_Accel", "latestVersion": 7}
+		]}}`)
+
+	contract := NewContract(NewContracts())
+	contract.ContractID = "ctr_1"
+	group := NewGroup(NewGroups())
+	group.GroupID = "grp_1"
+
+	properties := NewProperties()
+	properties.GetProperties(contract, group, "")
+
+	return properties
+}
+
+func TestProperties_GetProperties_NoRelatedRequests(t *testing.T) {
+	defer gock.Off()
+	Init(config)
+
+	properties := mockLoadProperties()
+	assert.Len(t, properties.Properties.Items, 2)
+	assert.Equal(t, "grp_1", properties.Properties.Items[0].Group.GroupID)
+	assert.Equal(t, "", properties.Properties.Items[0].Group.GroupName)
+	assert.True(t, <-properties.Properties.Items[0].Complete)
+	assert.True(t, gock.IsDone())
+	assert.False(t, gock.HasUnmatchedRequest())
+}
+
+func TestProperties_Load(t *testing.T) {
+	defer gock.Off()
+	ResponseCache = NewMemoryCache()
+	Init(config)
+
+	properties := mockLoadProperties()
+
+	gock.New("https://akaa-baseurl-xxxxxxxxxxx-xxxxxxxxxxxxx.luna.akamaiapis.net").
+		Get("/papi/v1/groups").
+		Reply(200).
+		SetHeader("Content-Type", "application/json").
+		BodyString(`{"groups": {"items": [{"groupId": "grp_1", "groupName": "Web", "contractIds": ["ctr_1"]}]}}`)
+
+	gock.New("https://akaa-baseurl-xxxxxxxxxxx-xxxxxxxxxxxxx.luna.akamaiapis.net").
+		Get("/papi/v1/contracts").
+		Reply(200).
+		SetHeader("Content-Type", "application/json").
+		BodyString(`{"contracts": {"items": [{"contractId": "ctr_1", "contractTypeName": "DIRECT_CUSTOMER"}]}}`)
+
+	gock.New("https://akaa-baseurl-xxxxxxxxxxx-xxxxxxxxxxxxx.luna.akamaiapis.net").
+		Get("/papi/v1/products").
+		MatchParam("contractId", "ctr_1").
+		Reply(200).
+		SetHeader("Content-Type", "application/json").
+		BodyString(`{"products": {"items": [
+			{"productId": "prd_Fresca", "productName": "Ion Standard"},
+			{"productId": "prd_Site_Accel", "productName": "DSA"}
+		]}}`)
+
+	for _, property := range []string{"prp_1", "prp_2"} {
+		version := "3"
+		if property == "prp_2" {
+			version = "7"
+		}
+		gock.New("https://akaa-baseurl-xxxxxxxxxxx-xxxxxxxxxxxxx.luna.akamaiapis.net").
+			Get("/papi/v1/properties/" + property + "/versions/latest").
+			Reply(200).
+			SetHeader("Content-Type", "application/json").
+			BodyString(`{"propertyId": "` + property + `", "versions": {"items": [{"propertyVersion": ` + version + `, "productionStatus": "ACTIVE"}]}}`)
+	}
+
+	err := properties.Load(LoadOptions{Group: true, Contract: true, Product: true, LatestVersion: true, Parallelism: 2}, "")
+	assert.NoError(t, err)
+
+	for _, property := range properties.Properties.Items {
+		assert.Equal(t, "Web", property.Group.GroupName)
+		assert.Equal(t, "DIRECT_CUSTOMER", property.Contract.ContractTypeName)
+		assert.Equal(t, property.ProductID, property.Product.ProductID)
+		assert.Equal(t, property.LatestVersion, property.Latest.PropertyVersion)
+	}
+	assert.Equal(t, "DSA", properties.Properties.Items[1].Product.ProductName)
+	assert.True(t, gock.IsDone())
+}
+
+func TestProperties_Load_Errors(t *testing.T) {
+	defer gock.Off()
+	ResponseCache = NewMemoryCache()
+	Init(config)
+
+	properties := mockLoadProperties()
+
+	gock.New("https://akaa-baseurl-xxxxxxxxxxx-xxxxxxxxxxxxx.luna.akamaiapis.net").
+		Get("/papi/v1/groups").
+		Reply(500).
+		SetHeader("Content-Type", "application/problem+json").
+		BodyString(`{"type": "internal_error", "title": "Internal Server Error", "status": 500}`)
+
+	gock.New("https://akaa-baseurl-xxxxxxxxxxx-xxxxxxxxxxxxx.luna.akamaiapis.net").
+		Get("/papi/v1/products").
+		MatchParam("contractId", "ctr_1").
+		Reply(200).
+		SetHeader("Content-Type", "application/json").
+		BodyString(`{"products": {"items": [{"productId": "prd_Fresca", "productName": "Ion Standard"}]}}`)
+
+	err := properties.Load(LoadOptions{Group: true, Product: true}, "")
+	assert.Error(t, err)
+
+	var loadErrors LoadErrors
+	assert.True(t, errors.As(err, &loadErrors))
+	assert.Len(t, loadErrors, 2)
+	assert.Contains(t, err.Error(), "groups:")
+	assert.Contains(t, err.Error(), `property prp_2: Unable to find product: "prd_Site_Accel"`)
+
+	assert.Equal(t, "Ion Standard", properties.Properties.Items[0].Product.ProductName)
+	assert.Equal(t, "", properties.Properties.Items[0].Group.GroupName)
+}
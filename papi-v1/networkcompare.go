@@ -0,0 +1,186 @@
+package papi
+
+import (
+	"fmt"
+	"strings"
+)
+
+// NetworkComparisonStatusValue is used to create an "enum" of possible NetworkComparison.Status values
+type NetworkComparisonStatusValue string
+
+const (
+	// NetworkComparisonInSync NetworkComparison.Status value IN_SYNC
+	NetworkComparisonInSync NetworkComparisonStatusValue = "IN_SYNC"
+	// NetworkComparisonStagingAhead NetworkComparison.Status value STAGING_AHEAD
+	NetworkComparisonStagingAhead NetworkComparisonStatusValue = "STAGING_AHEAD"
+	// NetworkComparisonStagingBehind NetworkComparison.Status value STAGING_BEHIND
+	NetworkComparisonStagingBehind NetworkComparisonStatusValue = "STAGING_BEHIND"
+	// NetworkComparisonDiverged NetworkComparison.Status value DIVERGED
+	NetworkComparisonDiverged NetworkComparisonStatusValue = "DIVERGED"
+)
+
+// NetworkComparison is the difference between the versions of a property
+// active on staging and production
+//
+// Changes are from production to staging: Hostnames.Add are only on
+// staging, Hostnames.Remove only on production, and Hostnames.Change have the
+// staging values. A version of 0 means
+// nothing is active on that network.
+//
+// See: Property.CompareNetworks()
+type NetworkComparison struct {
+	Status               NetworkComparisonStatusValue
+	StagingVersion       int
+	ProductionVersion    int
+	StagingRuleFormat    string
+	ProductionRuleFormat string
+	// Rules are the rule tree changes, except variables
+	Rules RuleChanges
+	// Variables are the changes to variables
+	Variables RuleChanges
+	Hostnames *HostnameChanges
+}
+
+// IsIdentical reports whether both networks serve the same configuration
+func (comparison *NetworkComparison) IsIdentical() bool {
+	return len(comparison.Rules) == 0 &&
+		len(comparison.Variables) == 0 &&
+		comparison.Hostnames.IsEmpty() &&
+		comparison.StagingRuleFormat == comparison.ProductionRuleFormat
+}
+
+// String returns a human readable report of the comparison
+func (comparison *NetworkComparison) String() string {
+	var report strings.Builder
+
+	fmt.Fprintf(&report, "%s: staging v%d, production v%d\n", comparison.Status, comparison.StagingVersion, comparison.ProductionVersion)
+
+	if comparison.StagingRuleFormat != comparison.ProductionRuleFormat {
+		fmt.Fprintf(&report, "rule format: %s -> %s\n", comparison.ProductionRuleFormat, comparison.StagingRuleFormat)
+	}
+
+	for _, hostname := range comparison.Hostnames.Add {
+		fmt.Fprintf(&report, "+ hostname %s -> %s\n", hostname.CnameFrom, hostname.CnameTo)
+	}
+	for _, hostname := range comparison.Hostnames.Change {
+		fmt.Fprintf(&report, "~ hostname %s -> %s\n", hostname.CnameFrom, hostname.CnameTo)
+	}
+	for _, hostname := range comparison.Hostnames.Remove {
+		fmt.Fprintf(&report, "- hostname %s -> %s\n", hostname.CnameFrom, hostname.CnameTo)
+	}
+
+	if len(comparison.Variables) != 0 {
+		report.WriteString(comparison.Variables.String())
+	}
+	if len(comparison.Rules) != 0 {
+		report.WriteString(comparison.Rules.String())
+	}
+
+	return report.String()
+}
+
+// CompareNetworks compares the versions of the property active on staging
+// and production
+//
+// The active versions are found with Activations.GetLatestActivation().
+// Staging is ahead when its version is newer and the production version was
+// activated on staging at some point, and diverged when it is newer but the
+// production version never was, e.g. after a hotfix activated straight to
+// production. Staging is behind when its version is older. The networks are
+// in sync when they serve the same configuration, whatever the versions.
+func (property *Property) CompareNetworks(correlationid string) (*NetworkComparison, error) {
+	activations, err := property.GetActivations()
+	if err != nil {
+		return nil, err
+	}
+
+	comparison := &NetworkComparison{
+		StagingVersion:    activeVersion(activations, NetworkStaging),
+		ProductionVersion: activeVersion(activations, NetworkProduction),
+	}
+
+	if comparison.StagingVersion == 0 && comparison.ProductionVersion == 0 {
+		return nil, fmt.Errorf("property %s is not active on either network", property.PropertyID)
+	}
+
+	staging, err := networkBundle(property, comparison.StagingVersion, correlationid)
+	if err != nil {
+		return nil, fmt.Errorf("staging version %d: %w", comparison.StagingVersion, err)
+	}
+
+	production, err := networkBundle(property, comparison.ProductionVersion, correlationid)
+	if err != nil {
+		return nil, fmt.Errorf("production version %d: %w", comparison.ProductionVersion, err)
+	}
+
+	comparison.StagingRuleFormat = staging.RuleFormat
+	comparison.ProductionRuleFormat = production.RuleFormat
+	comparison.Hostnames = DiffHostnames(production.Hostnames, staging.Hostnames)
+
+	changes := DiffRules(production.rules(), staging.rules())
+	for _, change := range changes {
+		if change.Kind == ChangeKindVariable {
+			comparison.Variables = append(comparison.Variables, change)
+		} else {
+			comparison.Rules = append(comparison.Rules, change)
+		}
+	}
+
+	switch {
+	case comparison.StagingVersion == comparison.ProductionVersion || comparison.IsIdentical():
+		comparison.Status = NetworkComparisonInSync
+	case comparison.StagingVersion < comparison.ProductionVersion:
+		comparison.Status = NetworkComparisonStagingBehind
+	case comparison.ProductionVersion == 0 || wasActivated(activations, NetworkStaging, comparison.ProductionVersion):
+		comparison.Status = NetworkComparisonStagingAhead
+	default:
+		comparison.Status = NetworkComparisonDiverged
+	}
+
+	return comparison, nil
+}
+
+// activeVersion returns the version active on network, or 0
+func activeVersion(activations *Activations, network NetworkValue) int {
+	activation, err := activations.GetLatestActivation(network, StatusActive)
+	if err != nil || activation.ActivationType == ActivationTypeDeactivate {
+		return 0
+	}
+
+	return activation.PropertyVersion
+}
+
+// wasActivated reports whether version was ever activated on network
+func wasActivated(activations *Activations, network NetworkValue, version int) bool {
+	for _, activation := range activations.Activations.Items {
+		if activation.Network == network && activation.PropertyVersion == version && activation.ActivationType != ActivationTypeDeactivate {
+			return true
+		}
+	}
+
+	return false
+}
+
+// networkBundle fetches the bundle of a version active on a network, or an
+// empty bundle for version 0
+func networkBundle(property *Property, version int, correlationid string) (*PropertyBundle, error) {
+	if version == 0 {
+		return &PropertyBundle{}, nil
+	}
+
+	return GetPropertyBundle(property, version, correlationid)
+}
+
+// rules returns the bundle's rule tree with its variables
+func (bundle *PropertyBundle) rules() *Rules {
+	rules := NewRules()
+	rules.RuleFormat = bundle.RuleFormat
+
+	if bundle.Rule != nil {
+		rule := *bundle.Rule
+		rule.Variables = bundle.Variables
+		rules.Rule = &rule
+	}
+
+	return rules
+}
@@ -0,0 +1,153 @@
+package papi
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"gopkg.in/h2non/gock.v1"
+)
+
+func mockCompareActivations(activations string) {
+	gock.New("https://akaa-baseurl-xxxxxxxxxxx-xxxxxxxxxxxxx.luna.akamaiapis.net").
+		Get("/papi/v1/properties/prp_1/activations").
+		Reply(200).
+		SetHeader("Content-Type", "application/json").
+		BodyString(`{"activations": {"items": [` + activations + `]}}`)
+}
+
+func mockCompareVersion(version string, ruleFormat string, ttl string, hostnames string) {
+	gock.New("https://akaa-baseurl-xxxxxxxxxxx-xxxxxxxxxxxxx.luna.akamaiapis.net").
+		Get("/papi/v1/properties/prp_1/versions/"+version+"/rules").
+		Reply(200).
+		SetHeader("Content-Type", "application/json").
+		BodyString(`{"propertyId": "prp_1", "propertyVersion": ` + version + `, "ruleFormat": "` + ruleFormat + `", "rules": {
+			"name": "default",
+			"variables": [{"name": "PMUSER_TTL", "value": "` + ttl + `", "description": "", "hidden": false, "sensitive": false}],
+			"behaviors": [{"name": "caching", "options": {"behavior": "MAX_AGE", "ttl": "` + ttl + `"}}]
+		}}`)
+
+	gock.New("https://akaa-baseurl-xxxxxxxxxxx-xxxxxxxxxxxxx.luna.akamaiapis.net").
+		Get("/papi/v1/properties/prp_1/versions/"+version+"/hostnames").
+		Reply(200).
+		SetHeader("Content-Type", "application/json").
+		BodyString(`{"propertyId": "prp_1", "hostnames": {"items": [` + hostnames + `]}}`)
+}
+
+func newCompareTestProperty() *Property {
+	property := NewProperty(NewProperties())
+	property.PropertyID = "prp_1"
+	property.PropertyName = "example.com"
+	property.ProductID = "prd_Fresca"
+	property.Contract.ContractID = "ctr_1"
+	property.Group.GroupID = "grp_1"
+
+	return property
+}
+
+const compareTestHostname = `{"cnameType": "EDGE_HOSTNAME", "cnameFrom": "www.example.com", "cnameTo": "www.example.com.edgesuite.net"}`
+
+func TestProperty_CompareNetworks_Ahead(t *testing.T) {
+	defer gock.Off()
+
+	mockCompareActivations(`
+		{"activationId": "atv_3", "propertyVersion": 5, "network": "STAGING", "status": "ACTIVE", "activationType": "ACTIVATE"},
+		{"activationId": "atv_2", "propertyVersion": 4, "network": "PRODUCTION", "status": "ACTIVE", "activationType": "ACTIVATE"},
+		{"activationId": "atv_1", "propertyVersion": 4, "network": "STAGING", "status": "INACTIVE", "activationType": "ACTIVATE"}`)
+	mockCompareVersion("5", "v2020-11-02", "1d", compareTestHostname+`, {"cnameType": "EDGE_HOSTNAME", "cnameFrom": "api.example.com", "cnameTo": "api.example.com.edgesuite.net"}`)
+	mockCompareVersion("4", "v2018-02-27", "1h", compareTestHostname)
+
+	Init(config)
+
+	comparison, err := newCompareTestProperty().CompareNetworks("")
+	assert.NoError(t, err)
+	assert.True(t, gock.IsDone())
+
+	assert.Equal(t, NetworkComparisonStagingAhead, comparison.Status)
+	assert.Equal(t, 5, comparison.StagingVersion)
+	assert.Equal(t, 4, comparison.ProductionVersion)
+	assert.Equal(t, "v2020-11-02", comparison.StagingRuleFormat)
+	assert.Equal(t, "v2018-02-27", comparison.ProductionRuleFormat)
+	assert.Len(t, comparison.Hostnames.Add, 1)
+	assert.Equal(t, "api.example.com", comparison.Hostnames.Add[0].CnameFrom)
+	assert.Empty(t, comparison.Hostnames.Remove)
+	assert.Len(t, comparison.Variables, 1)
+	assert.Equal(t, "PMUSER_TTL", comparison.Variables[0].Name)
+	assert.Len(t, comparison.Rules, 1)
+	assert.Equal(t, ChangeKindBehavior, comparison.Rules[0].Kind)
+	assert.False(t, comparison.IsIdentical())
+	assert.Contains(t, comparison.String(), "STAGING_AHEAD: staging v5, production v4")
+	assert.Contains(t, comparison.String(), "+ hostname api.example.com")
+}
+
+func TestProperty_CompareNetworks_Diverged(t *testing.T) {
+	defer gock.Off()
+
+	mockCompareActivations(`
+		{"activationId": "atv_3", "propertyVersion": 7, "network": "STAGING", "status": "ACTIVE", "activationType": "ACTIVATE"},
+		{"activationId": "atv_2", "propertyVersion": 6, "network": "PRODUCTION", "status": "ACTIVE", "activationType": "ACTIVATE"}`)
+	mockCompareVersion("7", "v2020-11-02", "1d", compareTestHostname)
+	mockCompareVersion("6", "v2020-11-02", "1h", compareTestHostname)
+
+	Init(config)
+
+	comparison, err := newCompareTestProperty().CompareNetworks("")
+	assert.NoError(t, err)
+	assert.Equal(t, NetworkComparisonDiverged, comparison.Status)
+}
+
+func TestProperty_CompareNetworks_Status(t *testing.T) {
+	tests := map[string]struct {
+		activations string
+		versions    []string
+		ttl         []string
+		status      NetworkComparisonStatusValue
+	}{
+		"behind": {
+			activations: `{"propertyVersion": 3, "network": "STAGING", "status": "ACTIVE"}, {"propertyVersion": 4, "network": "PRODUCTION", "status": "ACTIVE"}`,
+			versions:    []string{"3", "4"},
+			ttl:         []string{"1h", "1d"},
+			status:      NetworkComparisonStagingBehind,
+		},
+		"identical versions": {
+			activations: `{"propertyVersion": 3, "network": "STAGING", "status": "ACTIVE"}, {"propertyVersion": 2, "network": "PRODUCTION", "status": "ACTIVE"}`,
+			versions:    []string{"3", "2"},
+			ttl:         []string{"1h", "1h"},
+			status:      NetworkComparisonInSync,
+		},
+		"nothing in production": {
+			activations: `{"propertyVersion": 1, "network": "STAGING", "status": "ACTIVE"}`,
+			versions:    []string{"1"},
+			ttl:         []string{"1h"},
+			status:      NetworkComparisonStagingAhead,
+		},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			defer gock.Off()
+
+			mockCompareActivations(test.activations)
+			for key, version := range test.versions {
+				mockCompareVersion(version, "v2020-11-02", test.ttl[key], compareTestHostname)
+			}
+
+			Init(config)
+
+			comparison, err := newCompareTestProperty().CompareNetworks("")
+			assert.NoError(t, err)
+			assert.Equal(t, test.status, comparison.Status)
+			assert.True(t, gock.IsDone())
+		})
+	}
+}
+
+func TestProperty_CompareNetworks_NotActive(t *testing.T) {
+	defer gock.Off()
+
+	mockCompareActivations(`{"propertyVersion": 1, "network": "STAGING", "status": "FAILED"}`)
+
+	Init(config)
+
+	_, err := newCompareTestProperty().CompareNetworks("")
+	assert.Error(t, err)
+}
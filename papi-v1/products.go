@@ -1,12 +1,9 @@
 package papi
 
 import (
-	"encoding/json"
 	"fmt"
 
 	"github.com/akamai/AkamaiOPEN-edgegrid-golang/client-v1"
-	edge "github.com/akamai/AkamaiOPEN-edgegrid-golang/edgegrid"
-	"github.com/patrickmn/go-cache"
 )
 
 // Products represents a collection of products
@@ -48,46 +45,14 @@ func (products *Products) PostUnmarshalJSON() error {
 // API Docs: https://developer.akamai.com/api/luna/papi/resources.html#listproducts
 // Endpoint: GET /papi/v1/products/{?contractId}
 func (products *Products) GetProducts(contract *Contract, correlationid string) error {
-	cacheproducts, found := Profilecache.Get("products")
-	if found {
-		json.Unmarshal(cacheproducts.([]byte), products)
-		return nil
-	} else {
-		req, err := client.NewRequest(
-			Config,
-			"GET",
-			fmt.Sprintf(
-				"/papi/v1/products?contractId=%s",
-				contract.ContractID,
-			),
-			nil,
-		)
-		if err != nil {
-			return err
-		}
-
-		edge.PrintHttpRequestCorrelation(req, true, correlationid)
-
-		res, err := client.Do(Config, req)
-		if err != nil {
-			return err
-		}
-
-		edge.PrintHttpResponseCorrelation(res, true, correlationid)
-
-		if client.IsError(res) {
-			return client.NewAPIError(res)
-		}
-
-		if err = client.BodyJSON(res, products); err != nil {
-			return err
-		}
-
-		byt, _ := json.Marshal(products)
-		Profilecache.Set("products", byt, cache.DefaultExpiration)
-		return nil
-	}
-
+	return getCached(
+		fmt.Sprintf(
+			"/papi/v1/products?contractId=%s",
+			contract.ContractID,
+		),
+		products,
+		correlationid,
+	)
 }
 
 // FindProduct finds a specific product by ID
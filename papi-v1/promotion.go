@@ -0,0 +1,386 @@
+package papi
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+)
+
+// PromotionStepValue is used to create an "enum" of possible Promotion steps
+type PromotionStepValue string
+
+// PromotionEventValue is used to create an "enum" of possible PromotionEvent.Type values
+type PromotionEventValue string
+
+const (
+	// PromotionStepCreateVersion creates a new version from the latest version
+	PromotionStepCreateVersion PromotionStepValue = "CREATE_VERSION"
+	// PromotionStepSaveRules saves the desired rule tree to the new version
+	PromotionStepSaveRules PromotionStepValue = "SAVE_RULES"
+	// PromotionStepSaveHostnames saves the desired hostnames to the new version
+	PromotionStepSaveHostnames PromotionStepValue = "SAVE_HOSTNAMES"
+	// PromotionStepValidate checks the new version is in the property's contract
+	// and group, that PAPI reports no errors for it, and that its rule tree
+	// matches the rule format schema
+	PromotionStepValidate PromotionStepValue = "VALIDATE"
+	// PromotionStepActivateStaging activates the new version on staging
+	PromotionStepActivateStaging PromotionStepValue = "ACTIVATE_STAGING"
+	// PromotionStepWaitStaging waits for the staging activation
+	PromotionStepWaitStaging PromotionStepValue = "WAIT_STAGING"
+	// PromotionStepVerify runs Promotion.Verify against staging
+	PromotionStepVerify PromotionStepValue = "VERIFY"
+	// PromotionStepActivateProduction activates the new version on production
+	PromotionStepActivateProduction PromotionStepValue = "ACTIVATE_PRODUCTION"
+	// PromotionStepWaitProduction waits for the production activation
+	PromotionStepWaitProduction PromotionStepValue = "WAIT_PRODUCTION"
+
+	// PromotionEventStarted PromotionEvent.Type value STARTED
+	PromotionEventStarted PromotionEventValue = "STARTED"
+	// PromotionEventCompleted PromotionEvent.Type value COMPLETED
+	PromotionEventCompleted PromotionEventValue = "COMPLETED"
+	// PromotionEventSkipped PromotionEvent.Type value SKIPPED, for steps
+	// completed by an earlier run
+	PromotionEventSkipped PromotionEventValue = "SKIPPED"
+	// PromotionEventFailed PromotionEvent.Type value FAILED
+	PromotionEventFailed PromotionEventValue = "FAILED"
+	// PromotionEventActivation PromotionEvent.Type value ACTIVATION, for
+	// activation progress while waiting
+	PromotionEventActivation PromotionEventValue = "ACTIVATION"
+)
+
+// PromotionSteps lists the steps of a Promotion in the order they run
+var PromotionSteps = []PromotionStepValue{
+	PromotionStepCreateVersion,
+	PromotionStepSaveRules,
+	PromotionStepSaveHostnames,
+	PromotionStepValidate,
+	PromotionStepActivateStaging,
+	PromotionStepWaitStaging,
+	PromotionStepVerify,
+	PromotionStepActivateProduction,
+	PromotionStepWaitProduction,
+}
+
+// PromotionState records the progress of a Promotion
+//
+// It marshals to JSON, so it can be persisted from PromotionEvents and passed
+// back in Promotion.State to resume a promotion that was interrupted.
+type PromotionState struct {
+	PropertyID             string               `json:"propertyId"`
+	PropertyVersion        int                  `json:"propertyVersion,omitempty"`
+	StagingActivationID    string               `json:"stagingActivationId,omitempty"`
+	ProductionActivationID string               `json:"productionActivationId,omitempty"`
+	Completed              []PromotionStepValue `json:"completed,omitempty"`
+}
+
+// IsCompleted reports whether a step has completed
+func (state *PromotionState) IsCompleted(step PromotionStepValue) bool {
+	for _, completed := range state.Completed {
+		if completed == step {
+			return true
+		}
+	}
+
+	return false
+}
+
+// PromotionEvent reports the progress of a Promotion
+type PromotionEvent struct {
+	Type  PromotionEventValue
+	Step  PromotionStepValue
+	State PromotionState
+	// Err is set for PromotionEventFailed
+	Err error
+	// Activation is set for PromotionEventActivation
+	Activation *ActivationEvent
+}
+
+// Promotion rolls a rule tree and hostnames out to a property
+//
+// Run creates a new version from the latest version, saves the rules and
+// hostnames, validates the version, activates it on staging, waits, runs
+// Verify, then activates it on production and waits again. The Property must
+// have its ContractID and GroupID set.
+//
+//	promotion := &papi.Promotion{
+//		Property:     property,
+//		Rules:        rules,
+//		NotifyEmails: []string{"ops@example.com"},
+//		Verify: func(ctx context.Context, state *papi.PromotionState) error {
+//			return runSmokeTests(ctx, "staging")
+//		},
+//	}
+//	err := promotion.Run(ctx, "")
+//
+// If Run fails, calling it again with the same State resumes from the failed
+// step, or from the activation if an activation failed. Only the rule tree of
+// Rules is used.
+type Promotion struct {
+	Property  *Property
+	Rules     *Rules
+	Hostnames []*Hostname
+
+	// Note is the note of both activations
+	Note                string
+	NotifyEmails        []string
+	AcknowledgeWarnings bool
+
+	// WarningPolicy, if set, decides which activation warnings to acknowledge
+	// instead of AcknowledgeWarnings
+	WarningPolicy ActivationWarningPolicy
+
+	// Verify is called once the staging activation is active. Returning an
+	// error stops the promotion before production
+	Verify func(ctx context.Context, state *PromotionState) error
+
+	// Events, if set, receives an event as each step starts and finishes and
+	// while activations progress. Sends block, so the channel should be
+	// drained or buffered
+	Events chan<- PromotionEvent
+
+	// WaitOptions configures the activation polling; its Property and Events
+	// are ignored
+	WaitOptions ActivationWaitOptions
+
+	// State is the progress so far. It is created by Run if nil
+	State *PromotionState
+}
+
+// Run runs the remaining steps of the promotion
+func (promotion *Promotion) Run(ctx context.Context, correlationid string) error {
+	property := promotion.Property
+	if promotion.State == nil {
+		promotion.State = &PromotionState{PropertyID: property.PropertyID}
+	}
+	if promotion.State.PropertyID != property.PropertyID {
+		return fmt.Errorf("promotion state is for property %s, not %s", promotion.State.PropertyID, property.PropertyID)
+	}
+
+	// Copied, as it is appended to
+	promotion.State.Completed = append([]PromotionStepValue{}, promotion.State.Completed...)
+
+	steps := map[PromotionStepValue]func(context.Context, string) error{
+		PromotionStepCreateVersion:      promotion.createVersion,
+		PromotionStepSaveRules:          promotion.saveRules,
+		PromotionStepSaveHostnames:      promotion.saveHostnames,
+		PromotionStepValidate:           promotion.validate,
+		PromotionStepActivateStaging:    promotion.activator(NetworkStaging, &promotion.State.StagingActivationID),
+		PromotionStepWaitStaging:        promotion.waiter(PromotionStepWaitStaging, PromotionStepActivateStaging, &promotion.State.StagingActivationID),
+		PromotionStepVerify:             promotion.verify,
+		PromotionStepActivateProduction: promotion.activator(NetworkProduction, &promotion.State.ProductionActivationID),
+		PromotionStepWaitProduction:     promotion.waiter(PromotionStepWaitProduction, PromotionStepActivateProduction, &promotion.State.ProductionActivationID),
+	}
+
+	for _, step := range PromotionSteps {
+		if promotion.State.IsCompleted(step) {
+			promotion.emit(ctx, PromotionEvent{Type: PromotionEventSkipped, Step: step})
+			continue
+		}
+
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+
+		promotion.emit(ctx, PromotionEvent{Type: PromotionEventStarted, Step: step})
+
+		if err := steps[step](ctx, correlationid); err != nil {
+			err = fmt.Errorf("promotion step %s: %w", step, err)
+			promotion.emit(ctx, PromotionEvent{Type: PromotionEventFailed, Step: step, Err: err})
+			return err
+		}
+
+		promotion.State.Completed = append(promotion.State.Completed, step)
+		promotion.emit(ctx, PromotionEvent{Type: PromotionEventCompleted, Step: step})
+	}
+
+	return nil
+}
+
+// emit sends an event with a snapshot of the state
+func (promotion *Promotion) emit(ctx context.Context, event PromotionEvent) {
+	if promotion.Events == nil {
+		return
+	}
+
+	event.State = *promotion.State
+	event.State.Completed = append([]PromotionStepValue{}, promotion.State.Completed...)
+
+	select {
+	case promotion.Events <- event:
+	case <-ctx.Done():
+	}
+}
+
+func (promotion *Promotion) createVersion(ctx context.Context, correlationid string) error {
+	versions := NewVersions()
+	versions.PropertyID = promotion.Property.PropertyID
+
+	latest, err := versions.GetLatestVersion("", correlationid)
+	if err != nil {
+		return err
+	}
+
+	version := versions.NewVersion(latest, true, correlationid)
+	if err = version.Save(correlationid); err != nil {
+		return err
+	}
+
+	promotion.State.PropertyVersion = version.PropertyVersion
+
+	return nil
+}
+
+func (promotion *Promotion) saveRules(ctx context.Context, correlationid string) error {
+	if promotion.Rules == nil {
+		return nil
+	}
+
+	rules := NewRules()
+	rules.PropertyID = promotion.Property.PropertyID
+	rules.PropertyVersion = promotion.State.PropertyVersion
+	rules.ContractID = promotion.Property.ContractID
+	rules.GroupID = promotion.Property.GroupID
+	rules.RuleFormat = promotion.Rules.RuleFormat
+	rules.Rule = promotion.Rules.Rule
+
+	return rules.Save(correlationid)
+}
+
+func (promotion *Promotion) saveHostnames(ctx context.Context, correlationid string) error {
+	if promotion.Hostnames == nil {
+		return nil
+	}
+
+	hostnames := NewHostnames()
+	hostnames.PropertyID = promotion.Property.PropertyID
+	hostnames.PropertyVersion = promotion.State.PropertyVersion
+	hostnames.ContractID = promotion.Property.ContractID
+	hostnames.GroupID = promotion.Property.GroupID
+	hostnames.Hostnames.Items = promotion.Hostnames
+
+	return hostnames.Save()
+}
+
+func (promotion *Promotion) validate(ctx context.Context, correlationid string) error {
+	if promotion.Property.ContractID == "" || promotion.Property.GroupID == "" {
+		return errors.New("You must provide the contract and group of the property")
+	}
+
+	property := NewProperty(NewProperties())
+	property.PropertyID = promotion.Property.PropertyID
+	property.ContractID = promotion.Property.ContractID
+	property.GroupID = promotion.Property.GroupID
+	property.ProductID = promotion.Property.ProductID
+	property.LatestVersion = promotion.State.PropertyVersion
+
+	rules, err := property.GetRules(correlationid)
+	if err != nil {
+		return err
+	}
+
+	if (rules.ContractID != "" && rules.ContractID != property.ContractID) || (rules.GroupID != "" && rules.GroupID != property.GroupID) {
+		return fmt.Errorf("version %d is in contract %s and group %s, not %s and %s", property.LatestVersion, rules.ContractID, rules.GroupID, property.ContractID, property.GroupID)
+	}
+
+	if len(rules.Errors) != 0 {
+		return ErrorMap[ErrInvalidRules]
+	}
+
+	if property.ProductID == "" {
+		version := NewVersion(NewVersions())
+		if err := version.GetVersion(property, property.LatestVersion); err != nil {
+			return err
+		}
+		property.ProductID = version.ProductID
+	}
+
+	return rules.Validate(property.ProductID, rules.RuleFormat)
+}
+
+// activator returns a step activating the new version on network, recording
+// the activation ID in activationID
+func (promotion *Promotion) activator(network NetworkValue, activationID *string) func(context.Context, string) error {
+	return func(ctx context.Context, correlationid string) error {
+		activation := NewActivation(NewActivations())
+		activation.PropertyVersion = promotion.State.PropertyVersion
+		activation.Network = network
+		activation.Note = promotion.Note
+		activation.NotifyEmails = promotion.NotifyEmails
+
+		policy := promotion.WarningPolicy
+		if policy == nil && promotion.AcknowledgeWarnings {
+			policy = AcknowledgeAllWarnings
+		}
+
+		if err := activation.SaveWithPolicy(promotion.Property, policy); err != nil {
+			return err
+		}
+
+		*activationID = activation.ActivationID
+
+		return nil
+	}
+}
+
+// waiter returns a step waiting for the activation recorded in activationID,
+// forwarding its progress as PromotionEvents
+//
+// If the activation fails, activateStep is marked incomplete so resuming
+// activates again.
+func (promotion *Promotion) waiter(step PromotionStepValue, activateStep PromotionStepValue, activationID *string) func(context.Context, string) error {
+	return func(ctx context.Context, correlationid string) error {
+		activation := NewActivation(NewActivations())
+		activation.ActivationID = *activationID
+		activation.PropertyID = promotion.Property.PropertyID
+
+		opts := promotion.WaitOptions
+		opts.Property = promotion.Property
+		opts.Events = nil
+
+		var forwarding sync.WaitGroup
+		var events chan ActivationEvent
+		if promotion.Events != nil {
+			events = make(chan ActivationEvent)
+			opts.Events = events
+			forwarding.Add(1)
+			go func() {
+				defer forwarding.Done()
+				for event := range events {
+					event := event
+					promotion.emit(ctx, PromotionEvent{Type: PromotionEventActivation, Step: step, Activation: &event})
+				}
+			}()
+		}
+
+		err := activation.Wait(ctx, &opts)
+
+		// The forwarder reads the state, so it is stopped before the state
+		// changes
+		if events != nil {
+			close(events)
+			forwarding.Wait()
+		}
+
+		if errors.Is(err, ErrorMap[ErrActivationFailed]) {
+			var completed []PromotionStepValue
+			for _, completedStep := range promotion.State.Completed {
+				if completedStep != activateStep {
+					completed = append(completed, completedStep)
+				}
+			}
+			promotion.State.Completed = completed
+			*activationID = ""
+		}
+
+		return err
+	}
+}
+
+func (promotion *Promotion) verify(ctx context.Context, correlationid string) error {
+	if promotion.Verify == nil {
+		return nil
+	}
+
+	return promotion.Verify(ctx, promotion.State)
+}
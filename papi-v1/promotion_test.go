@@ -0,0 +1,209 @@
+package papi
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"gopkg.in/h2non/gock.v1"
+)
+
+func mockPromotionActivation(network NetworkValue, activationID string) {
+	gock.New("https://akaa-baseurl-xxxxxxxxxxx-xxxxxxxxxxxxx.luna.akamaiapis.net").
+		Post("/papi/v1/properties/prp_1/activations").
+		MatchParam("contractId", "ctr_1").
+		MatchParam("groupId", "grp_1").
+		Reply(201).
+		SetHeader("Content-Type", "application/json").
+		BodyString(`{"activationLink": "/papi/v1/properties/prp_1/activations/` + activationID + `"}`)
+
+	for _, status := range []StatusValue{StatusPending, StatusActive} {
+		gock.New("https://akaa-baseurl-xxxxxxxxxxx-xxxxxxxxxxxxx.luna.akamaiapis.net").
+			Get("/papi/v1/properties/prp_1/activations/"+activationID).
+			Reply(200).
+			SetHeader("Content-Type", "application/json").
+			SetHeader("Retry-After", "0").
+			BodyString(`{"activations": {"items": [{
+				"activationId": "` + activationID + `",
+				"propertyId": "prp_1",
+				"propertyVersion": 4,
+				"network": "` + string(network) + `",
+				"status": "` + string(status) + `"
+			}]}}`)
+	}
+}
+
+func TestPromotion_Run(t *testing.T) {
+	defer gock.Off()
+
+	gock.New("https://akaa-baseurl-xxxxxxxxxxx-xxxxxxxxxxxxx.luna.akamaiapis.net").
+		Get("/papi/v1/properties/prp_1/versions/latest").
+		Reply(200).
+		SetHeader("Content-Type", "application/json").
+		BodyString(`{"propertyId": "prp_1", "versions": {"items": [{"propertyVersion": 3, "etag": "e3"}]}}`)
+
+	gock.New("https://akaa-baseurl-xxxxxxxxxxx-xxxxxxxxxxxxx.luna.akamaiapis.net").
+		Post("/papi/v1/properties/prp_1/versions").
+		Reply(201).
+		SetHeader("Content-Type", "application/json").
+		BodyString(`{"versionLink": "/papi/v1/properties/prp_1/versions/4"}`)
+
+	gock.New("https://akaa-baseurl-xxxxxxxxxxx-xxxxxxxxxxxxx.luna.akamaiapis.net").
+		Get("/papi/v1/properties/prp_1/versions/4").
+		Reply(200).
+		SetHeader("Content-Type", "application/json").
+		BodyString(`{"propertyId": "prp_1", "versions": {"items": [{"propertyVersion": 4, "etag": "e4"}]}}`)
+
+	gock.New("https://akaa-baseurl-xxxxxxxxxxx-xxxxxxxxxxxxx.luna.akamaiapis.net").
+		Put("/papi/v1/properties/prp_1/versions/4/rules").
+		Reply(200).
+		SetHeader("Content-Type", "application/json").
+		BodyString(`{"propertyId": "prp_1", "propertyVersion": 4, "rules": {"name": "default"}}`)
+
+	gock.New("https://akaa-baseurl-xxxxxxxxxxx-xxxxxxxxxxxxx.luna.akamaiapis.net").
+		Put("/papi/v1/properties/prp_1/versions/4/hostnames").
+		Reply(200).
+		SetHeader("Content-Type", "application/json").
+		BodyString(`{"propertyId": "prp_1", "propertyVersion": 4, "hostnames": {"items": [{"cnameFrom": "www.example.com", "cnameTo": "www.example.com.edgesuite.net"}]}}`)
+
+	gock.New("https://akaa-baseurl-xxxxxxxxxxx-xxxxxxxxxxxxx.luna.akamaiapis.net").
+		Get("/papi/v1/properties/prp_1/versions/4/rules").
+		Reply(200).
+		SetHeader("Content-Type", "application/json").
+		BodyString(`{"propertyId": "prp_1", "propertyVersion": 4, "contractId": "ctr_1", "groupId": "grp_1", "ruleFormat": "v2021-01-01", "rules": {"name": "default"}}`)
+
+	gock.New("https://akaa-baseurl-xxxxxxxxxxx-xxxxxxxxxxxxx.luna.akamaiapis.net").
+		Get("/papi/v1/schemas/products/prd_Promotion/v2021-01-01").
+		Reply(200).
+		SetHeader("Content-Type", "application/json").
+		BodyString(`{"type": "object", "required": ["rules"]}`)
+
+	mockPromotionActivation(NetworkStaging, "atv_stg")
+
+	Init(config)
+
+	property := NewProperty(NewProperties())
+	property.PropertyID = "prp_1"
+	property.ContractID = "ctr_1"
+	property.GroupID = "grp_1"
+	property.ProductID = "prd_Promotion"
+
+	hostname := NewHostname(nil)
+	hostname.CnameFrom = "www.example.com"
+	hostname.CnameTo = "www.example.com.edgesuite.net"
+
+	verified := 0
+	events := make(chan PromotionEvent, 100)
+	promotion := &Promotion{
+		Property:    property,
+		Rules:       NewRules(),
+		Hostnames:   []*Hostname{hostname},
+		Events:      events,
+		WaitOptions: ActivationWaitOptions{PollInterval: time.Millisecond},
+		Verify: func(ctx context.Context, state *PromotionState) error {
+			verified++
+			if verified == 1 {
+				return errors.New("smoke tests failed")
+			}
+			return nil
+		},
+	}
+
+	err := promotion.Run(context.Background(), "")
+
+	assert.EqualError(t, err, "promotion step VERIFY: smoke tests failed")
+	assert.Equal(t, 4, promotion.State.PropertyVersion)
+	assert.Equal(t, "atv_stg", promotion.State.StagingActivationID)
+	assert.Equal(t, PromotionSteps[:6], promotion.State.Completed)
+	assert.True(t, gock.IsDone())
+
+	// Resuming only verifies and promotes to production
+	mockPromotionActivation(NetworkProduction, "atv_prd")
+
+	err = promotion.Run(context.Background(), "")
+
+	assert.NoError(t, err)
+	assert.Equal(t, "atv_prd", promotion.State.ProductionActivationID)
+	assert.Equal(t, PromotionSteps, promotion.State.Completed)
+	assert.True(t, gock.IsDone())
+	close(events)
+
+	var types []PromotionEventValue
+	activationEvents := 0
+	for event := range events {
+		if event.Type == PromotionEventActivation {
+			activationEvents++
+			continue
+		}
+		if event.Step == PromotionStepVerify {
+			types = append(types, event.Type)
+		}
+	}
+	assert.Equal(t, []PromotionEventValue{PromotionEventStarted, PromotionEventFailed, PromotionEventStarted, PromotionEventCompleted}, types)
+	assert.Equal(t, 2, activationEvents)
+}
+
+func TestPromotion_Run_ActivationFailed(t *testing.T) {
+	defer gock.Off()
+
+	gock.New("https://akaa-baseurl-xxxxxxxxxxx-xxxxxxxxxxxxx.luna.akamaiapis.net").
+		Get("/papi/v1/properties/prp_1/activations/atv_stg").
+		Reply(200).
+		SetHeader("Content-Type", "application/json").
+		BodyString(`{"activations": {"items": [{"activationId": "atv_stg", "network": "STAGING", "status": "FAILED"}]}}`)
+
+	Init(config)
+
+	property := NewProperty(NewProperties())
+	property.PropertyID = "prp_1"
+
+	promotion := &Promotion{
+		Property: property,
+		State: &PromotionState{
+			PropertyID:          "prp_1",
+			PropertyVersion:     4,
+			StagingActivationID: "atv_stg",
+			Completed:           PromotionSteps[:5],
+		},
+		WaitOptions: ActivationWaitOptions{PollInterval: time.Millisecond},
+	}
+
+	err := promotion.Run(context.Background(), "")
+
+	assert.True(t, errors.Is(err, ErrorMap[ErrActivationFailed]))
+	assert.Equal(t, PromotionSteps[:4], promotion.State.Completed)
+	assert.Empty(t, promotion.State.StagingActivationID)
+}
+
+func TestPromotion_Run_WrongGroup(t *testing.T) {
+	defer gock.Off()
+
+	gock.New("https://akaa-baseurl-xxxxxxxxxxx-xxxxxxxxxxxxx.luna.akamaiapis.net").
+		Get("/papi/v1/properties/prp_1/versions/4/rules").
+		Reply(200).
+		SetHeader("Content-Type", "application/json").
+		BodyString(`{"propertyId": "prp_1", "propertyVersion": 4, "contractId": "ctr_1", "groupId": "grp_2", "rules": {"name": "default"}}`)
+
+	Init(config)
+
+	property := NewProperty(NewProperties())
+	property.PropertyID = "prp_1"
+	property.ContractID = "ctr_1"
+	property.GroupID = "grp_1"
+
+	promotion := &Promotion{
+		Property: property,
+		State: &PromotionState{
+			PropertyID:      "prp_1",
+			PropertyVersion: 4,
+			Completed:       PromotionSteps[:3],
+		},
+	}
+
+	err := promotion.Run(context.Background(), "")
+
+	assert.EqualError(t, err, "promotion step VALIDATE: version 4 is in contract ctr_1 and group grp_2, not ctr_1 and grp_1")
+	assert.Equal(t, PromotionSteps[:3], promotion.State.Completed)
+}
@@ -71,7 +71,7 @@ func (properties *Properties) GetProperties(contract *Contract, group *Group, co
 	res, err := client.Do(Config, req)
 
 	if err != nil {
-		return nil
+		return err
 	}
 
 	edge.PrintHttpResponseCorrelation(res, true, correlationid)
@@ -123,6 +123,9 @@ func (properties *Properties) FindProperty(id string) (*Property, error) {
 }
 
 // NewProperty creates a new property associated with the collection
+//
+// The contract and group are fetched in the background, and
+// property.Complete receives true once both are found.
 func (properties *Properties) NewProperty(contract *Contract, group *Group) *Property {
 	property := NewProperty(properties)
 
@@ -148,6 +151,8 @@ type Property struct {
 	AccountID         string             `json:"accountId,omitempty"`
 	Contract          *Contract          `json:"-"`
 	Group             *Group             `json:"-"`
+	Product           *Product           `json:"-"`
+	Latest            *Version           `json:"-"`
 	ContractID        string             `json:"contractId,omitempty"`
 	GroupID           string             `json:"groupId,omitempty"`
 	PropertyID        string             `json:"propertyId,omitempty"`
@@ -342,6 +347,9 @@ func (property *Property) GetHostnames(version *Version, correlationid string) (
 
 // PostUnmarshalJSON is called after JSON unmarshaling into EdgeHostnames
 //
+// Only the IDs of the Contract and Group are set; use Property.Load() to
+// fetch them.
+//
 // See: jsonhooks-v1/jsonhooks.Unmarshal()
 func (property *Property) PostUnmarshalJSON() error {
 	property.Init()
@@ -352,14 +360,7 @@ func (property *Property) PostUnmarshalJSON() error {
 	property.Group = NewGroup(NewGroups())
 	property.Group.GroupID = property.GroupID
 
-	go property.Group.GetGroup()
-	go property.Contract.GetContract()
-
-	go (func(property *Property) {
-		contractComplete := <-property.Contract.Complete
-		groupComplete := <-property.Group.Complete
-		property.Complete <- (contractComplete && groupComplete)
-	})(property)
+	signalComplete(property.Complete, true)
 
 	return nil
 }
@@ -0,0 +1,188 @@
+package papi
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"time"
+)
+
+// RollbackOptions configures Property.Rollback
+type RollbackOptions struct {
+	// Note defaults to "Rollback from version X to version Y"
+	Note string
+	// NotifyEmails defaults to those of the current activation
+	NotifyEmails []string
+	// FastFallback activates the previous version with fast fallback when the
+	// current activation is still within its fallback window. Outside the
+	// window a regular activation is made
+	FastFallback bool
+	// WarningPolicy decides which activation warnings to acknowledge
+	//
+	// See: Activation.SaveWithPolicy()
+	WarningPolicy ActivationWarningPolicy
+	// WaitOptions configures the activation polling; its Property is ignored
+	WaitOptions *ActivationWaitOptions
+}
+
+// RollbackRecord is the audit record of a rollback
+//
+// The record is returned by Property.Rollback whether or not the rollback
+// succeeded; Error holds the reason it failed.
+type RollbackRecord struct {
+	PropertyID   string       `json:"propertyId"`
+	PropertyName string       `json:"propertyName"`
+	Network      NetworkValue `json:"network"`
+	FromVersion  int          `json:"fromVersion"`
+	ToVersion    int          `json:"toVersion"`
+	FastFallback bool         `json:"fastFallback"`
+	ActivationID string       `json:"activationId,omitempty"`
+	Status       StatusValue  `json:"status,omitempty"`
+	Note         string       `json:"note,omitempty"`
+	NotifyEmails []string     `json:"notifyEmails"`
+	StartDate    time.Time    `json:"startDate"`
+	EndDate      time.Time    `json:"endDate"`
+	Error        string       `json:"error,omitempty"`
+}
+
+// Rollback reactivates the version that was active on network before the
+// current one, and waits until it is active
+//
+// The versions are found in the property's activation history: the current
+// version is the latest activation that is ACTIVE on the network, and the
+// previous version the latest other version activated before it. An error is
+// returned if the property was deactivated on the network.
+//
+// If ctx is done before the activation completes, the activation carries on
+// and ctx.Err() is returned.
+//
+// See: Activation.Wait()
+// API Docs: https://developer.akamai.com/api/luna/papi/resources.html#activateaproperty
+// Endpoint: POST /papi/v1/properties/{propertyId}/activations/{?contractId,groupId}
+func (property *Property) Rollback(ctx context.Context, network NetworkValue, opts *RollbackOptions) (*RollbackRecord, error) {
+	if opts == nil {
+		opts = &RollbackOptions{}
+	}
+
+	record := &RollbackRecord{
+		PropertyID:   property.PropertyID,
+		PropertyName: property.PropertyName,
+		Network:      network,
+		StartDate:    time.Now(),
+	}
+
+	err := property.rollback(ctx, network, opts, record)
+	if err != nil {
+		record.Error = err.Error()
+	}
+	record.EndDate = time.Now()
+
+	return record, err
+}
+
+func (property *Property) rollback(ctx context.Context, network NetworkValue, opts *RollbackOptions, record *RollbackRecord) error {
+	activations, err := property.GetActivations()
+	if err != nil {
+		return err
+	}
+
+	current, previous := rollbackVersions(activations, network)
+	if current == nil {
+		return fmt.Errorf("property %s is not active on %s", property.PropertyID, network)
+	}
+	record.FromVersion = current.PropertyVersion
+
+	if previous == nil {
+		return fmt.Errorf("property %s has no previous version to roll back to on %s", property.PropertyID, network)
+	}
+	record.ToVersion = previous.PropertyVersion
+
+	// Fetch the fallback info if the list did not include it
+	if current.FallbackInfo == nil {
+		if _, err := current.getActivation(ctx, property, 0); err != nil {
+			return err
+		}
+	}
+
+	activation := NewActivation(activations)
+	activation.PropertyVersion = previous.PropertyVersion
+	activation.Network = network
+	activation.Note = opts.Note
+	activation.NotifyEmails = opts.NotifyEmails
+	activation.UseFastFallback = opts.FastFallback && canFastFallback(current, previous.PropertyVersion, time.Now())
+
+	if activation.Note == "" {
+		activation.Note = fmt.Sprintf("Rollback from version %d to version %d", current.PropertyVersion, previous.PropertyVersion)
+	}
+	if len(activation.NotifyEmails) == 0 {
+		activation.NotifyEmails = current.NotifyEmails
+	}
+
+	record.FastFallback = activation.UseFastFallback
+	record.Note = activation.Note
+	record.NotifyEmails = activation.NotifyEmails
+
+	if err := activation.SaveWithPolicy(property, opts.WarningPolicy); err != nil {
+		return err
+	}
+	record.ActivationID = activation.ActivationID
+	record.Status = activation.Status
+
+	waitOptions := ActivationWaitOptions{}
+	if opts.WaitOptions != nil {
+		waitOptions = *opts.WaitOptions
+	}
+	waitOptions.Property = property
+
+	err = activation.Wait(ctx, &waitOptions)
+	record.Status = activation.Status
+
+	return err
+}
+
+// rollbackVersions returns the activation of the version active on network
+// and of the version active before it, or nil
+func rollbackVersions(activations *Activations, network NetworkValue) (*Activation, *Activation) {
+	var history []*Activation
+	for _, activation := range activations.Activations.Items {
+		if activation.Network != network {
+			continue
+		}
+		if activation.Status == StatusActive || activation.Status == StatusInactive {
+			history = append(history, activation)
+		}
+	}
+
+	sort.SliceStable(history, func(i, j int) bool {
+		return parseActivationDate(history[i].UpdateDate).After(parseActivationDate(history[j].UpdateDate))
+	})
+
+	var current *Activation
+	for _, activation := range history {
+		if current == nil {
+			if activation.Status == StatusActive && activation.ActivationType == ActivationTypeDeactivate {
+				return nil, nil
+			}
+			if activation.Status == StatusActive {
+				current = activation
+			}
+			continue
+		}
+
+		if activation.ActivationType != ActivationTypeDeactivate && activation.PropertyVersion != current.PropertyVersion {
+			return current, activation
+		}
+	}
+
+	return current, nil
+}
+
+// canFastFallback reports whether current can fall back to version at now
+func canFastFallback(current *Activation, version int, now time.Time) bool {
+	info := current.FallbackInfo
+	if info == nil || !info.CanFastFallback || info.FallbackVersion != version {
+		return false
+	}
+
+	return now.Before(time.Unix(info.FastFallbackExpirationTime, 0))
+}
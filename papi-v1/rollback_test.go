@@ -0,0 +1,120 @@
+package papi
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"gopkg.in/h2non/gock.v1"
+)
+
+func TestProperty_Rollback(t *testing.T) {
+	defer gock.Off()
+
+	gock.New("https://akaa-baseurl-xxxxxxxxxxx-xxxxxxxxxxxxx.luna.akamaiapis.net").
+		Get("/papi/v1/properties/prp_123/activations").
+		MatchParam("contractId", "ctr_1").
+		Reply(200).
+		SetHeader("Content-Type", "application/json").
+		BodyString(`{"activations": {"items": [
+			{"activationId": "atv_6", "propertyVersion": 6, "network": "STAGING", "activationType": "ACTIVATE", "status": "ACTIVE", "updateDate": "2021-06-03T10:00:00Z"},
+			{"activationId": "atv_3", "propertyVersion": 3, "network": "PRODUCTION", "activationType": "ACTIVATE", "status": "INACTIVE", "updateDate": "2021-05-01T10:00:00Z"},
+			{"activationId": "atv_5", "propertyVersion": 5, "network": "PRODUCTION", "activationType": "ACTIVATE", "status": "ACTIVE", "updateDate": "2021-06-02T10:00:00Z", "notifyEmails": ["ops@example.com"],
+				"fallbackInfo": {"canFastFallback": true, "fallbackVersion": 4, "fastFallbackExpirationTime": 4102444800}},
+			{"activationId": "atv_5f", "propertyVersion": 5, "network": "PRODUCTION", "activationType": "ACTIVATE", "status": "FAILED", "updateDate": "2021-06-01T12:00:00Z"},
+			{"activationId": "atv_4", "propertyVersion": 4, "network": "PRODUCTION", "activationType": "ACTIVATE", "status": "INACTIVE", "updateDate": "2021-06-01T10:00:00Z"}
+		]}}`)
+
+	gock.New("https://akaa-baseurl-xxxxxxxxxxx-xxxxxxxxxxxxx.luna.akamaiapis.net").
+		Post("/papi/v1/properties/prp_123/activations").
+		MatchParam("contractId", "ctr_1").
+		BodyString(`"propertyVersion":4,.*"useFastFallback":true`).
+		Reply(201).
+		SetHeader("Content-Type", "application/json").
+		BodyString(`{"activationLink": "/papi/v1/properties/prp_123/activations/atv_456?contractId=ctr_1&groupId=grp_1"}`)
+
+	mockActivationStatus(NetworkProduction, StatusPending, "")
+	mockActivationStatus(NetworkProduction, StatusActive, "")
+
+	Init(config)
+
+	property := NewProperty(NewProperties())
+	property.PropertyID = "prp_123"
+	property.PropertyName = "www.example.com"
+	property.ContractID = "ctr_1"
+	property.GroupID = "grp_1"
+	property.Contract.ContractID = "ctr_1"
+	property.Group.GroupID = "grp_1"
+
+	record, err := property.Rollback(context.Background(), NetworkProduction, &RollbackOptions{FastFallback: true, WaitOptions: &ActivationWaitOptions{PollInterval: time.Millisecond}})
+
+	assert.NoError(t, err)
+	assert.True(t, gock.IsDone())
+	assert.Equal(t, 5, record.FromVersion)
+	assert.Equal(t, 4, record.ToVersion)
+	assert.True(t, record.FastFallback)
+	assert.Equal(t, "atv_456", record.ActivationID)
+	assert.Equal(t, StatusActive, record.Status)
+	assert.Equal(t, "Rollback from version 5 to version 4", record.Note)
+	assert.Equal(t, []string{"ops@example.com"}, record.NotifyEmails)
+	assert.Empty(t, record.Error)
+	assert.False(t, record.EndDate.Before(record.StartDate))
+}
+
+func TestProperty_Rollback_NoPreviousVersion(t *testing.T) {
+	defer gock.Off()
+
+	gock.New("https://akaa-baseurl-xxxxxxxxxxx-xxxxxxxxxxxxx.luna.akamaiapis.net").
+		Get("/papi/v1/properties/prp_123/activations").
+		Reply(200).
+		SetHeader("Content-Type", "application/json").
+		BodyString(`{"activations": {"items": [
+			{"activationId": "atv_1", "propertyVersion": 1, "network": "STAGING", "activationType": "ACTIVATE", "status": "ACTIVE", "updateDate": "2021-06-03T10:00:00Z"}
+		]}}`)
+
+	Init(config)
+
+	property := NewProperty(NewProperties())
+	property.PropertyID = "prp_123"
+
+	record, err := property.Rollback(context.Background(), NetworkStaging, &RollbackOptions{WaitOptions: &ActivationWaitOptions{PollInterval: time.Millisecond}})
+
+	assert.Error(t, err)
+	assert.Equal(t, 1, record.FromVersion)
+	assert.Equal(t, err.Error(), record.Error)
+}
+
+func TestRollbackVersions(t *testing.T) {
+	activations := NewActivations()
+	activations.Activations.Items = []*Activation{
+		{PropertyVersion: 3, Network: NetworkStaging, ActivationType: ActivationTypeActivate, Status: StatusInactive, UpdateDate: "2021-06-01T10:00:00Z"},
+		{PropertyVersion: 3, Network: NetworkStaging, ActivationType: ActivationTypeDeactivate, Status: StatusActive, UpdateDate: "2021-06-02T10:00:00Z"},
+	}
+
+	current, previous := rollbackVersions(activations, NetworkStaging)
+	assert.Nil(t, current)
+	assert.Nil(t, previous)
+
+	activations.Activations.Items = append(activations.Activations.Items,
+		&Activation{PropertyVersion: 4, Network: NetworkStaging, ActivationType: ActivationTypeActivate, Status: StatusActive, UpdateDate: "2021-06-03T10:00:00Z"},
+	)
+
+	current, previous = rollbackVersions(activations, NetworkStaging)
+	assert.Equal(t, 4, current.PropertyVersion)
+	assert.Equal(t, 3, previous.PropertyVersion)
+}
+
+func TestCanFastFallback(t *testing.T) {
+	now := time.Date(2021, 6, 1, 10, 0, 0, 0, time.UTC)
+	current := &Activation{FallbackInfo: &ActivationFallbackInfo{
+		CanFastFallback:            true,
+		FallbackVersion:            4,
+		FastFallbackExpirationTime: now.Add(time.Minute).Unix(),
+	}}
+
+	assert.True(t, canFastFallback(current, 4, now))
+	assert.False(t, canFastFallback(current, 3, now))
+	assert.False(t, canFastFallback(current, 4, now.Add(time.Hour)))
+	assert.False(t, canFastFallback(&Activation{}, 4, now))
+}
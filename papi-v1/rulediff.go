@@ -0,0 +1,593 @@
+package papi
+
+import (
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"sort"
+	"strings"
+)
+
+// ChangeTypeValue is used to create an "enum" of possible RuleChange.Type values
+type ChangeTypeValue string
+
+// ChangeKindValue is used to create an "enum" of possible RuleChange.Kind values
+type ChangeKindValue string
+
+const (
+	// ChangeAdded RuleChange.Type value ADDED
+	ChangeAdded ChangeTypeValue = "ADDED"
+	// ChangeRemoved RuleChange.Type value REMOVED
+	ChangeRemoved ChangeTypeValue = "REMOVED"
+	// ChangeModified RuleChange.Type value MODIFIED
+	ChangeModified ChangeTypeValue = "MODIFIED"
+	// ChangeMoved RuleChange.Type value MOVED
+	ChangeMoved ChangeTypeValue = "MOVED"
+	// ChangeRenamed RuleChange.Type value RENAMED
+	ChangeRenamed ChangeTypeValue = "RENAMED"
+	// ChangeReordered RuleChange.Type value REORDERED
+	ChangeReordered ChangeTypeValue = "REORDERED"
+
+	// ChangeKindRule RuleChange.Kind value rule
+	ChangeKindRule ChangeKindValue = "rule"
+	// ChangeKindBehavior RuleChange.Kind value behavior
+	ChangeKindBehavior ChangeKindValue = "behavior"
+	// ChangeKindCriteria RuleChange.Kind value criteria
+	ChangeKindCriteria ChangeKindValue = "criteria"
+	// ChangeKindVariable RuleChange.Kind value variable
+	ChangeKindVariable ChangeKindValue = "variable"
+	// ChangeKindCriteriaMustSatisfy RuleChange.Kind value criteriaMustSatisfy
+	ChangeKindCriteriaMustSatisfy ChangeKindValue = "criteriaMustSatisfy"
+	// ChangeKindComments RuleChange.Kind value comments
+	ChangeKindComments ChangeKindValue = "comments"
+)
+
+// RuleChanges is the list of changes between two rule trees
+//
+// See: DiffRules()
+type RuleChanges []*RuleChange
+
+// RuleChange is a single change between two rule trees
+//
+// Path is the slash separated path of rule names, starting at the default
+// rule, of the rule the change applies to. It is the path in the new tree,
+// except for removed rules. OldPath is set for moved and renamed rules.
+//
+// A reordered change is reported on the parent rule, with the names of the
+// children it kept in their old (OldValue) and new (NewValue) order, as
+// PAPI evaluates children in order.
+type RuleChange struct {
+	Type     ChangeTypeValue `json:"type"`
+	Kind     ChangeKindValue `json:"kind"`
+	Path     string          `json:"path"`
+	OldPath  string          `json:"oldPath,omitempty"`
+	Name     string          `json:"name,omitempty"`
+	OldName  string          `json:"oldName,omitempty"`
+	UUID     string          `json:"uuid,omitempty"`
+	OldValue interface{}     `json:"oldValue,omitempty"`
+	NewValue interface{}     `json:"newValue,omitempty"`
+	Options  []*ValueChange  `json:"options,omitempty"`
+}
+
+// ValueChange is a change to a single behavior or criteria option, or
+// variable field
+type ValueChange struct {
+	Name     string          `json:"name"`
+	Type     ChangeTypeValue `json:"type"`
+	OldValue interface{}     `json:"oldValue,omitempty"`
+	NewValue interface{}     `json:"newValue,omitempty"`
+}
+
+// DiffRules compares two rule trees and returns the changes from a to b
+//
+// Rules, behaviors and criteria are matched by UUID when both sides have one,
+// and by name otherwise. A rule matched under a different parent is reported
+// as moved; a rule matched by UUID with a different name as renamed, which
+// may be in addition to moved. A rule whose children are in a different order
+// is reported as reordered.
+func DiffRules(a, b *Rules) RuleChanges {
+	if a == nil {
+		a = NewRules()
+	}
+	if b == nil {
+		b = NewRules()
+	}
+
+	differ := newRuleDiffer(a.Rule, b.Rule)
+	differ.match()
+
+	return differ.changes()
+}
+
+// Filter returns the changes of the given kinds
+func (changes RuleChanges) Filter(kinds ...ChangeKindValue) RuleChanges {
+	var filtered RuleChanges
+	for _, change := range changes {
+		for _, kind := range kinds {
+			if change.Kind == kind {
+				filtered = append(filtered, change)
+				break
+			}
+		}
+	}
+
+	return filtered
+}
+
+// String returns a human readable report of the changes
+func (changes RuleChanges) String() string {
+	if len(changes) == 0 {
+		return "No changes\n"
+	}
+
+	var report strings.Builder
+	for _, change := range changes {
+		report.WriteString(change.String())
+		report.WriteString("\n")
+		for _, option := range change.Options {
+			report.WriteString("    ")
+			report.WriteString(option.String())
+			report.WriteString("\n")
+		}
+	}
+
+	return report.String()
+}
+
+// String returns a single line description of the change
+func (change *RuleChange) String() string {
+	switch change.Kind {
+	case ChangeKindRule:
+		switch change.Type {
+		case ChangeMoved:
+			return fmt.Sprintf("> rule %s (moved from %s)", change.Path, change.OldPath)
+		case ChangeRenamed:
+			return fmt.Sprintf("> rule %s (renamed from %q)", change.Path, change.OldName)
+		case ChangeReordered:
+			return fmt.Sprintf("> rule %s (children reordered: %s -> %s)", change.Path, formatChangeValue(change.OldValue), formatChangeValue(change.NewValue))
+		}
+		return fmt.Sprintf("%s rule %s", changeSymbol(change.Type), change.Path)
+	case ChangeKindCriteriaMustSatisfy, ChangeKindComments:
+		return fmt.Sprintf("%s %s in %s: %s -> %s", changeSymbol(change.Type), change.Kind, change.Path, formatChangeValue(change.OldValue), formatChangeValue(change.NewValue))
+	}
+
+	return fmt.Sprintf("%s %s %s in %s", changeSymbol(change.Type), change.Kind, change.Name, change.Path)
+}
+
+// String returns a single line description of the change
+func (change *ValueChange) String() string {
+	switch change.Type {
+	case ChangeAdded:
+		return fmt.Sprintf("+ %s: %s", change.Name, formatChangeValue(change.NewValue))
+	case ChangeRemoved:
+		return fmt.Sprintf("- %s: %s", change.Name, formatChangeValue(change.OldValue))
+	}
+
+	return fmt.Sprintf("~ %s: %s -> %s", change.Name, formatChangeValue(change.OldValue), formatChangeValue(change.NewValue))
+}
+
+func changeSymbol(changeType ChangeTypeValue) string {
+	switch changeType {
+	case ChangeAdded:
+		return "+"
+	case ChangeRemoved:
+		return "-"
+	case ChangeMoved, ChangeRenamed, ChangeReordered:
+		return ">"
+	}
+
+	return "~"
+}
+
+func formatChangeValue(value interface{}) string {
+	formatted, err := json.Marshal(value)
+	if err != nil {
+		return fmt.Sprint(value)
+	}
+
+	return string(formatted)
+}
+
+// ruleNode is a rule and its position within a tree
+type ruleNode struct {
+	rule   *Rule
+	parent *ruleNode
+	path   string
+}
+
+// ruleDiffer matches the rules of two trees
+type ruleDiffer struct {
+	from    []*ruleNode
+	to      []*ruleNode
+	fromMap map[*Rule]*ruleNode
+	toMap   map[*Rule]*ruleNode
+	matched map[*ruleNode]*ruleNode
+	reverse map[*ruleNode]*ruleNode
+}
+
+func newRuleDiffer(from, to *Rule) *ruleDiffer {
+	differ := &ruleDiffer{
+		fromMap: map[*Rule]*ruleNode{},
+		toMap:   map[*Rule]*ruleNode{},
+		matched: map[*ruleNode]*ruleNode{},
+		reverse: map[*ruleNode]*ruleNode{},
+	}
+	differ.from = flattenRules(from, nil, differ.fromMap, nil)
+	differ.to = flattenRules(to, nil, differ.toMap, nil)
+
+	return differ
+}
+
+// flattenRules lists a tree's rules in pre-order
+func flattenRules(rule *Rule, parent *ruleNode, index map[*Rule]*ruleNode, nodes []*ruleNode) []*ruleNode {
+	if rule == nil {
+		return nodes
+	}
+
+	node := &ruleNode{rule: rule, parent: parent, path: rule.Name}
+	if parent != nil {
+		node.path = parent.path + "/" + rule.Name
+	}
+	index[rule] = node
+	nodes = append(nodes, node)
+
+	for _, child := range rule.Children {
+		nodes = flattenRules(child, node, index, nodes)
+	}
+
+	return nodes
+}
+
+func (differ *ruleDiffer) pair(from, to *ruleNode) {
+	differ.matched[from] = to
+	differ.reverse[to] = from
+}
+
+// match pairs up rules: by UUID anywhere in the tree, then by name among the
+// children of paired rules, then by name where it is unique among the
+// remaining rules, which detects moves
+func (differ *ruleDiffer) match() {
+	if len(differ.from) == 0 || len(differ.to) == 0 {
+		return
+	}
+
+	queue := []*ruleNode{differ.from[0]}
+	differ.pair(differ.from[0], differ.to[0])
+
+	byUUID := map[string]*ruleNode{}
+	for _, node := range differ.to[1:] {
+		if node.rule.UUID != "" {
+			byUUID[node.rule.UUID] = node
+		}
+	}
+	for _, node := range differ.from[1:] {
+		if to, ok := byUUID[node.rule.UUID]; ok && node.rule.UUID != "" && differ.reverse[to] == nil {
+			differ.pair(node, to)
+			queue = append(queue, node)
+		}
+	}
+
+	for len(queue) > 0 {
+		for len(queue) > 0 {
+			from := queue[0]
+			queue = queue[1:]
+
+			to := differ.matched[from]
+			for _, fromChild := range from.rule.Children {
+				fromNode := differ.fromMap[fromChild]
+				if differ.matched[fromNode] != nil {
+					continue
+				}
+
+				for _, toChild := range to.rule.Children {
+					toNode := differ.toMap[toChild]
+					if differ.reverse[toNode] == nil && toChild.Name == fromChild.Name && !hasDistinctUUIDs(fromChild.UUID, toChild.UUID) {
+						differ.pair(fromNode, toNode)
+						queue = append(queue, fromNode)
+						break
+					}
+				}
+			}
+		}
+
+		fromByName := map[string][]*ruleNode{}
+		for _, node := range differ.from {
+			if differ.matched[node] == nil {
+				fromByName[node.rule.Name] = append(fromByName[node.rule.Name], node)
+			}
+		}
+		toByName := map[string][]*ruleNode{}
+		for _, node := range differ.to {
+			if differ.reverse[node] == nil {
+				toByName[node.rule.Name] = append(toByName[node.rule.Name], node)
+			}
+		}
+		for _, node := range differ.from {
+			candidates := fromByName[node.rule.Name]
+			if differ.matched[node] != nil || len(candidates) != 1 || len(toByName[node.rule.Name]) != 1 {
+				continue
+			}
+
+			to := toByName[node.rule.Name][0]
+			if hasDistinctUUIDs(node.rule.UUID, to.rule.UUID) {
+				continue
+			}
+			differ.pair(node, to)
+			queue = append(queue, node)
+		}
+	}
+}
+
+// changes lists removed rules, then walks the new tree reporting everything else
+func (differ *ruleDiffer) changes() RuleChanges {
+	var changes RuleChanges
+
+	for _, node := range differ.from {
+		if differ.matched[node] == nil && (node.parent == nil || differ.matched[node.parent] != nil) {
+			changes = append(changes, &RuleChange{Type: ChangeRemoved, Kind: ChangeKindRule, Path: node.path, Name: node.rule.Name, UUID: node.rule.UUID})
+		}
+	}
+
+	for _, to := range differ.to {
+		from := differ.reverse[to]
+		if from == nil {
+			if to.parent == nil || differ.reverse[to.parent] != nil {
+				changes = append(changes, &RuleChange{Type: ChangeAdded, Kind: ChangeKindRule, Path: to.path, Name: to.rule.Name, UUID: to.rule.UUID})
+			}
+			continue
+		}
+
+		if from.parent != nil && to.parent != nil && differ.matched[from.parent] != to.parent {
+			changes = append(changes, &RuleChange{Type: ChangeMoved, Kind: ChangeKindRule, Path: to.path, OldPath: from.path, Name: to.rule.Name, OldName: from.rule.Name, UUID: to.rule.UUID})
+		}
+		if from.rule.Name != to.rule.Name {
+			changes = append(changes, &RuleChange{Type: ChangeRenamed, Kind: ChangeKindRule, Path: to.path, OldPath: from.path, Name: to.rule.Name, OldName: from.rule.Name, UUID: to.rule.UUID})
+		}
+
+		changes = append(changes, diffRule(from.rule, to.rule, to.path)...)
+
+		if oldOrder, newOrder, ok := differ.reordered(from, to); ok {
+			changes = append(changes, &RuleChange{Type: ChangeReordered, Kind: ChangeKindRule, Path: to.path, Name: to.rule.Name, UUID: to.rule.UUID, OldValue: oldOrder, NewValue: newOrder})
+		}
+	}
+
+	return changes
+}
+
+// reordered compares the order of the children that stayed under a pair of
+// matched rules, returning their new names in the old and new order
+func (differ *ruleDiffer) reordered(from, to *ruleNode) ([]string, []string, bool) {
+	var oldOrder []*ruleNode
+	for _, child := range from.rule.Children {
+		if match := differ.matched[differ.fromMap[child]]; match != nil && match.parent == to {
+			oldOrder = append(oldOrder, match)
+		}
+	}
+
+	var newOrder []*ruleNode
+	for _, child := range to.rule.Children {
+		node := differ.toMap[child]
+		if match := differ.reverse[node]; match != nil && match.parent == from {
+			newOrder = append(newOrder, node)
+		}
+	}
+
+	changed := false
+	for i := range oldOrder {
+		if oldOrder[i] != newOrder[i] {
+			changed = true
+			break
+		}
+	}
+	if !changed {
+		return nil, nil, false
+	}
+
+	return ruleNodeNames(oldOrder), ruleNodeNames(newOrder), true
+}
+
+func ruleNodeNames(nodes []*ruleNode) []string {
+	names := make([]string, 0, len(nodes))
+	for _, node := range nodes {
+		names = append(names, node.rule.Name)
+	}
+
+	return names
+}
+
+// diffRule compares the contents of two matched rules
+func diffRule(from, to *Rule, path string) RuleChanges {
+	var changes RuleChanges
+
+	if from.CriteriaMustSatisfy != to.CriteriaMustSatisfy {
+		changes = append(changes, &RuleChange{Type: ChangeModified, Kind: ChangeKindCriteriaMustSatisfy, Path: path, OldValue: from.CriteriaMustSatisfy, NewValue: to.CriteriaMustSatisfy})
+	}
+
+	if from.Comments != to.Comments {
+		changes = append(changes, &RuleChange{Type: ChangeModified, Kind: ChangeKindComments, Path: path, OldValue: from.Comments, NewValue: to.Comments})
+	}
+
+	changes = append(changes, diffOptionItems(ChangeKindCriteria, criteriaItems(from.Criteria), criteriaItems(to.Criteria), path)...)
+	changes = append(changes, diffOptionItems(ChangeKindBehavior, behaviorItems(from.Behaviors), behaviorItems(to.Behaviors), path)...)
+	changes = append(changes, diffVariables(from.Variables, to.Variables, path)...)
+
+	return changes
+}
+
+// optionItem is the common shape of behaviors and criteria
+type optionItem struct {
+	name    string
+	uuid    string
+	options OptionValue
+}
+
+func behaviorItems(behaviors []*Behavior) []optionItem {
+	items := make([]optionItem, 0, len(behaviors))
+	for _, behavior := range behaviors {
+		items = append(items, optionItem{name: behavior.Name, uuid: behavior.UUID, options: behavior.Options})
+	}
+
+	return items
+}
+
+func criteriaItems(criteria []*Criteria) []optionItem {
+	items := make([]optionItem, 0, len(criteria))
+	for _, c := range criteria {
+		items = append(items, optionItem{name: c.Name, uuid: c.UUID, options: c.Options})
+	}
+
+	return items
+}
+
+// diffOptionItems matches behaviors or criteria by UUID, then by name in order
+func diffOptionItems(kind ChangeKindValue, from, to []optionItem, path string) RuleChanges {
+	var changes RuleChanges
+
+	matched := make([]int, len(from))
+	used := make([]bool, len(to))
+	for i := range matched {
+		matched[i] = -1
+	}
+
+	for i, fromItem := range from {
+		if fromItem.uuid == "" {
+			continue
+		}
+		for j, toItem := range to {
+			if !used[j] && toItem.uuid == fromItem.uuid {
+				matched[i] = j
+				used[j] = true
+				break
+			}
+		}
+	}
+
+	for i, fromItem := range from {
+		if matched[i] != -1 {
+			continue
+		}
+		for j, toItem := range to {
+			if !used[j] && toItem.name == fromItem.name && !hasDistinctUUIDs(fromItem.uuid, toItem.uuid) {
+				matched[i] = j
+				used[j] = true
+				break
+			}
+		}
+	}
+
+	for i, fromItem := range from {
+		if matched[i] == -1 {
+			changes = append(changes, &RuleChange{Type: ChangeRemoved, Kind: kind, Path: path, Name: fromItem.name, UUID: fromItem.uuid, OldValue: fromItem.options})
+			continue
+		}
+
+		toItem := to[matched[i]]
+		options := diffValues(normalizeOptions(fromItem.options), normalizeOptions(toItem.options))
+		if len(options) > 0 || fromItem.name != toItem.name {
+			changes = append(changes, &RuleChange{Type: ChangeModified, Kind: kind, Path: path, Name: toItem.name, OldName: fromItem.name, UUID: toItem.uuid, Options: options})
+		}
+	}
+
+	for j, toItem := range to {
+		if !used[j] {
+			changes = append(changes, &RuleChange{Type: ChangeAdded, Kind: kind, Path: path, Name: toItem.name, UUID: toItem.uuid, NewValue: toItem.options})
+		}
+	}
+
+	return changes
+}
+
+// diffVariables matches variables by name
+func diffVariables(from, to []*Variable, path string) RuleChanges {
+	var changes RuleChanges
+
+	toByName := map[string]*Variable{}
+	for _, variable := range to {
+		toByName[variable.Name] = variable
+	}
+
+	fromByName := map[string]*Variable{}
+	for _, variable := range from {
+		fromByName[variable.Name] = variable
+
+		toVariable, ok := toByName[variable.Name]
+		if !ok {
+			changes = append(changes, &RuleChange{Type: ChangeRemoved, Kind: ChangeKindVariable, Path: path, Name: variable.Name, OldValue: variable.Value})
+			continue
+		}
+
+		fields := diffValues(variableFields(variable), variableFields(toVariable))
+		if len(fields) > 0 {
+			changes = append(changes, &RuleChange{Type: ChangeModified, Kind: ChangeKindVariable, Path: path, Name: variable.Name, Options: fields})
+		}
+	}
+
+	for _, variable := range to {
+		if _, ok := fromByName[variable.Name]; !ok {
+			changes = append(changes, &RuleChange{Type: ChangeAdded, Kind: ChangeKindVariable, Path: path, Name: variable.Name, NewValue: variable.Value})
+		}
+	}
+
+	return changes
+}
+
+func variableFields(variable *Variable) map[string]interface{} {
+	return map[string]interface{}{
+		"value":       variable.Value,
+		"description": variable.Description,
+		"hidden":      variable.Hidden,
+		"sensitive":   variable.Sensitive,
+	}
+}
+
+// diffValues compares two maps key by key, in key order
+func diffValues(from, to map[string]interface{}) []*ValueChange {
+	var keys []string
+	for key := range from {
+		keys = append(keys, key)
+	}
+	for key := range to {
+		if _, ok := from[key]; !ok {
+			keys = append(keys, key)
+		}
+	}
+	sort.Strings(keys)
+
+	var changes []*ValueChange
+	for _, key := range keys {
+		fromValue, inFrom := from[key]
+		toValue, inTo := to[key]
+
+		switch {
+		case !inTo:
+			changes = append(changes, &ValueChange{Name: key, Type: ChangeRemoved, OldValue: fromValue})
+		case !inFrom:
+			changes = append(changes, &ValueChange{Name: key, Type: ChangeAdded, NewValue: toValue})
+		case !reflect.DeepEqual(fromValue, toValue):
+			changes = append(changes, &ValueChange{Name: key, Type: ChangeModified, OldValue: fromValue, NewValue: toValue})
+		}
+	}
+
+	return changes
+}
+
+// normalizeOptions round trips options through JSON, so values set in code
+// (e.g. int) compare equal to values read from the API (float64)
+func normalizeOptions(options OptionValue) map[string]interface{} {
+	normalized := map[string]interface{}{}
+	data, err := json.Marshal(options)
+	if err != nil {
+		return options
+	}
+
+	if err := json.Unmarshal(data, &normalized); err != nil || normalized == nil {
+		return map[string]interface{}{}
+	}
+
+	return normalized
+}
+
+// hasDistinctUUIDs reports whether both sides have a UUID and they differ
+func hasDistinctUUIDs(a, b string) bool {
+	return a != "" && b != "" && a != b
+}
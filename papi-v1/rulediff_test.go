@@ -0,0 +1,179 @@
+package papi
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func rulesFromJSON(t *testing.T, body string) *Rules {
+	rules := NewRules()
+	if err := json.Unmarshal([]byte(body), rules); err != nil {
+		t.Fatal(err)
+	}
+
+	return rules
+}
+
+func TestDiffRules(t *testing.T) {
+	a := rulesFromJSON(t, `{
+		"rules": {
+			"name": "default",
+			"behaviors": [
+				{"name": "origin", "options": {"hostname": "origin.example.com", "httpPort": 80}},
+				{"name": "cpCode", "options": {"value": {"id": 12345}}}
+			],
+			"variables": [
+				{"name": "PMUSER_A", "value": "a"},
+				{"name": "PMUSER_B", "value": "b"}
+			],
+			"children": [
+				{
+					"name": "Static",
+					"uuid": "static-uuid",
+					"criteriaMustSatisfy": "all",
+					"criteria": [{"name": "fileExtension", "options": {"values": ["css", "js"]}}],
+					"behaviors": [{"name": "caching", "options": {"behavior": "MAX_AGE", "ttl": "1d"}}],
+					"children": [{"name": "Images", "behaviors": [{"name": "imageManager", "options": {}}]}]
+				},
+				{"name": "Dynamic", "comments": "old comment"},
+				{"name": "Legacy", "children": [{"name": "Old child"}]}
+			]
+		}
+	}`)
+
+	b := rulesFromJSON(t, `{
+		"rules": {
+			"name": "default",
+			"behaviors": [
+				{"name": "origin", "options": {"hostname": "origin.example.net", "httpPort": 80, "httpsPort": 443}},
+				{"name": "cpCode", "options": {"value": {"id": 12345}}}
+			],
+			"variables": [
+				{"name": "PMUSER_A", "value": "changed"},
+				{"name": "PMUSER_C", "value": "c"}
+			],
+			"children": [
+				{
+					"name": "Static content",
+					"uuid": "static-uuid",
+					"criteriaMustSatisfy": "any",
+					"criteria": [{"name": "fileExtension", "options": {"values": ["css", "js", "png"]}}],
+					"behaviors": [{"name": "caching", "options": {"behavior": "MAX_AGE", "ttl": "1d"}}],
+					"children": []
+				},
+				{
+					"name": "Dynamic",
+					"comments": "new comment",
+					"children": [{"name": "Images", "behaviors": [{"name": "imageManager", "options": {}}]}]
+				},
+				{"name": "New", "children": [{"name": "New child"}]}
+			]
+		}
+	}`)
+
+	changes := DiffRules(a, b)
+
+	assert.Equal(t, RuleChanges{
+		{Type: ChangeRemoved, Kind: ChangeKindRule, Path: "default/Legacy", Name: "Legacy"},
+		{Type: ChangeModified, Kind: ChangeKindBehavior, Path: "default", Name: "origin", OldName: "origin", Options: []*ValueChange{
+			{Name: "hostname", Type: ChangeModified, OldValue: "origin.example.com", NewValue: "origin.example.net"},
+			{Name: "httpsPort", Type: ChangeAdded, NewValue: float64(443)},
+		}},
+		{Type: ChangeModified, Kind: ChangeKindVariable, Path: "default", Name: "PMUSER_A", Options: []*ValueChange{
+			{Name: "value", Type: ChangeModified, OldValue: "a", NewValue: "changed"},
+		}},
+		{Type: ChangeRemoved, Kind: ChangeKindVariable, Path: "default", Name: "PMUSER_B", OldValue: "b"},
+		{Type: ChangeAdded, Kind: ChangeKindVariable, Path: "default", Name: "PMUSER_C", NewValue: "c"},
+		{Type: ChangeRenamed, Kind: ChangeKindRule, Path: "default/Static content", OldPath: "default/Static", Name: "Static content", OldName: "Static", UUID: "static-uuid"},
+		{Type: ChangeModified, Kind: ChangeKindCriteriaMustSatisfy, Path: "default/Static content", OldValue: RuleCriteriaMustSatisfyAll, NewValue: RuleCriteriaMustSatisfyAny},
+		{Type: ChangeModified, Kind: ChangeKindCriteria, Path: "default/Static content", Name: "fileExtension", OldName: "fileExtension", Options: []*ValueChange{
+			{Name: "values", Type: ChangeModified, OldValue: []interface{}{"css", "js"}, NewValue: []interface{}{"css", "js", "png"}},
+		}},
+		{Type: ChangeModified, Kind: ChangeKindComments, Path: "default/Dynamic", OldValue: "old comment", NewValue: "new comment"},
+		{Type: ChangeMoved, Kind: ChangeKindRule, Path: "default/Dynamic/Images", OldPath: "default/Static/Images", Name: "Images", OldName: "Images"},
+		{Type: ChangeAdded, Kind: ChangeKindRule, Path: "default/New", Name: "New"},
+	}, changes)
+
+	assert.Equal(t, `- rule default/Legacy
+~ behavior origin in default
+    ~ hostname: "origin.example.com" -> "origin.example.net"
+    + httpsPort: 443
+~ variable PMUSER_A in default
+    ~ value: "a" -> "changed"
+- variable PMUSER_B in default
++ variable PMUSER_C in default
+> rule default/Static content (renamed from "Static")
+~ criteriaMustSatisfy in default/Static content: "all" -> "any"
+~ criteria fileExtension in default/Static content
+    ~ values: ["css","js"] -> ["css","js","png"]
+~ comments in default/Dynamic: "old comment" -> "new comment"
+> rule default/Dynamic/Images (moved from default/Static/Images)
++ rule default/New
+`, changes.String())
+}
+
+func TestDiffRules_MatchesByUUID(t *testing.T) {
+	a := rulesFromJSON(t, `{"rules": {"name": "default", "behaviors": [
+		{"name": "caching", "uuid": "one", "options": {"ttl": "1d"}},
+		{"name": "caching", "uuid": "two", "options": {"ttl": "2d"}}
+	]}}`)
+	b := rulesFromJSON(t, `{"rules": {"name": "default", "behaviors": [
+		{"name": "caching", "uuid": "two", "options": {"ttl": "2d"}},
+		{"name": "caching", "uuid": "one", "options": {"ttl": "3d"}}
+	]}}`)
+
+	changes := DiffRules(a, b)
+
+	assert.Len(t, changes, 1)
+	assert.Equal(t, "one", changes[0].UUID)
+	assert.Equal(t, "3d", changes[0].Options[0].NewValue)
+}
+
+func TestDiffRules_NoChanges(t *testing.T) {
+	rules := rulesFromJSON(t, `{"rules": {"name": "default", "children": [{"name": "Child"}]}}`)
+
+	changes := DiffRules(rules, rules)
+
+	assert.Empty(t, changes)
+	assert.Equal(t, "No changes\n", changes.String())
+	assert.Len(t, DiffRules(nil, rules).Filter(ChangeKindRule), 1)
+}
+
+func TestDiffRules_MovedAndRenamed(t *testing.T) {
+	a := rulesFromJSON(t, `{"rules": {"name": "default", "children": [
+		{"name": "Static", "children": [{"name": "Images", "uuid": "images-uuid"}]},
+		{"name": "Dynamic"}
+	]}}`)
+	b := rulesFromJSON(t, `{"rules": {"name": "default", "children": [
+		{"name": "Static"},
+		{"name": "Dynamic", "children": [{"name": "Pictures", "uuid": "images-uuid"}]}
+	]}}`)
+
+	changes := DiffRules(a, b)
+
+	assert.Equal(t, RuleChanges{
+		{Type: ChangeMoved, Kind: ChangeKindRule, Path: "default/Dynamic/Pictures", OldPath: "default/Static/Images", Name: "Pictures", OldName: "Images", UUID: "images-uuid"},
+		{Type: ChangeRenamed, Kind: ChangeKindRule, Path: "default/Dynamic/Pictures", OldPath: "default/Static/Images", Name: "Pictures", OldName: "Images", UUID: "images-uuid"},
+	}, changes)
+}
+
+func TestDiffRules_Reordered(t *testing.T) {
+	a := rulesFromJSON(t, `{"rules": {"name": "default", "children": [
+		{"name": "Redirect"}, {"name": "Static"}, {"name": "Dynamic"}, {"name": "Legacy"}
+	]}}`)
+	b := rulesFromJSON(t, `{"rules": {"name": "default", "children": [
+		{"name": "Static"}, {"name": "Redirect"}, {"name": "New"}, {"name": "Dynamic"}
+	]}}`)
+
+	changes := DiffRules(a, b)
+
+	assert.Equal(t, RuleChanges{
+		{Type: ChangeRemoved, Kind: ChangeKindRule, Path: "default/Legacy", Name: "Legacy"},
+		{Type: ChangeReordered, Kind: ChangeKindRule, Path: "default", Name: "default",
+			OldValue: []string{"Redirect", "Static", "Dynamic"}, NewValue: []string{"Static", "Redirect", "Dynamic"}},
+		{Type: ChangeAdded, Kind: ChangeKindRule, Path: "default/New", Name: "New"},
+	}, changes)
+	assert.Contains(t, changes.String(), `> rule default (children reordered: ["Redirect","Static","Dynamic"] -> ["Static","Redirect","Dynamic"])`)
+}
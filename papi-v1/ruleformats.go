@@ -79,6 +79,23 @@ func (ruleFormats *RuleFormats) GetLatest(correlationid string) (string, error)
 // API Docs: https://developer.akamai.com/api/luna/papi/resources.html#getaruleformatsschema
 // Endpoint: /papi/v1/schemas/products/{productId}/{ruleFormat}
 func (ruleFormats *RuleFormats) GetSchema(product string, ruleFormat string, correlationid string) (*gojsonschema.Schema, error) {
+	schemaBytes, err := ruleFormats.GetSchemaJSON(product, ruleFormat, correlationid)
+	if err != nil {
+		return nil, err
+	}
+
+	loader := gojsonschema.NewBytesLoader(schemaBytes)
+	schema, err := gojsonschema.NewSchema(loader)
+
+	return schema, err
+}
+
+// GetSchemaJSON fetches the schema for a given product and rule format as a
+// JSON document
+//
+// API Docs: https://developer.akamai.com/api/luna/papi/resources.html#getaruleformatsschema
+// Endpoint: /papi/v1/schemas/products/{productId}/{ruleFormat}
+func (ruleFormats *RuleFormats) GetSchemaJSON(product string, ruleFormat string, correlationid string) ([]byte, error) {
 	req, err := client.NewRequest(
 		Config,
 		"GET",
@@ -106,10 +123,5 @@ func (ruleFormats *RuleFormats) GetSchema(product string, ruleFormat string, cor
 		return nil, client.NewAPIError(res)
 	}
 
-	schemaBytes, _ := ioutil.ReadAll(res.Body)
-	schemaBody := string(schemaBytes)
-	loader := gojsonschema.NewStringLoader(schemaBody)
-	schema, err := gojsonschema.NewSchema(loader)
-
-	return schema, err
+	return ioutil.ReadAll(res.Body)
 }
@@ -0,0 +1,205 @@
+package papi
+
+import (
+	"errors"
+	"fmt"
+	"net/url"
+
+	"github.com/akamai/AkamaiOPEN-edgegrid-golang/client-v1"
+	edge "github.com/akamai/AkamaiOPEN-edgegrid-golang/edgegrid"
+)
+
+// RuleFormatUpgrade is a property's rule tree converted to a newer rule
+// format, with what the conversion changed
+//
+// See: Property.PlanRuleFormatUpgrade()
+type RuleFormatUpgrade struct {
+	property *Property
+	// Version is the property version that was converted
+	Version    int
+	FromFormat string
+	ToFormat   string
+	// Rules is the converted rule tree
+	Rules *Rules
+	// Changes are all the changes made by the conversion
+	Changes RuleChanges
+	// Removed are the behaviors and criteria dropped by the conversion
+	Removed RuleChanges
+	// Renamed are the behaviors and criteria given a new name
+	Renamed RuleChanges
+	// Options are the behaviors and criteria whose options were added,
+	// removed or given a new value, usually because their default changed
+	Options RuleChanges
+	// ValidationErrors are the schema violations of the converted rule tree
+	ValidationErrors RuleValidationErrors
+}
+
+// PlanRuleFormatUpgrade converts the rules of the property's latest version
+// to toFormat, without saving anything
+//
+// toFormat defaults to the latest format from RuleFormats.GetLatest(). The
+// conversion is done by PAPI, and the result is validated against the
+// schema of the new format. Behaviors and criteria are matched by UUID, so
+// renames are only detected when the rule tree has UUIDs.
+//
+// Review the upgrade, then call RuleFormatUpgrade.Apply() to save it.
+func (property *Property) PlanRuleFormatUpgrade(toFormat string, correlationid string) (*RuleFormatUpgrade, error) {
+	if property.ProductID == "" || property.LatestVersion == 0 {
+		if err := property.GetProperty(correlationid); err != nil {
+			return nil, err
+		}
+	}
+
+	if toFormat == "" {
+		latest, err := NewRuleFormats().GetLatest(correlationid)
+		if err != nil {
+			return nil, err
+		}
+		toFormat = latest
+	}
+
+	current, err := property.getRulesInFormat(property.LatestVersion, "", correlationid)
+	if err != nil {
+		return nil, err
+	}
+
+	converted, err := property.getRulesInFormat(property.LatestVersion, toFormat, correlationid)
+	if err != nil {
+		return nil, fmt.Errorf("converting to %s: %w", toFormat, err)
+	}
+
+	upgrade := &RuleFormatUpgrade{
+		property:   property,
+		Version:    property.LatestVersion,
+		FromFormat: current.RuleFormat,
+		ToFormat:   toFormat,
+		Rules:      converted,
+		Changes:    DiffRules(current, converted),
+	}
+
+	for _, change := range upgrade.Changes {
+		if change.Kind != ChangeKindBehavior && change.Kind != ChangeKindCriteria {
+			continue
+		}
+
+		switch {
+		case change.Type == ChangeRemoved:
+			upgrade.Removed = append(upgrade.Removed, change)
+		case change.Type == ChangeModified && change.OldName != change.Name:
+			upgrade.Renamed = append(upgrade.Renamed, change)
+		}
+
+		if change.Type == ChangeModified && len(change.Options) != 0 {
+			upgrade.Options = append(upgrade.Options, change)
+		}
+	}
+
+	err = converted.Validate(property.ProductID, toFormat)
+	var validationErrors RuleValidationErrors
+	switch {
+	case errors.As(err, &validationErrors):
+		upgrade.ValidationErrors = validationErrors
+	case err != nil:
+		return nil, err
+	}
+
+	return upgrade, nil
+}
+
+// IsNoop reports whether the property already uses the format and the
+// conversion changed nothing
+func (upgrade *RuleFormatUpgrade) IsNoop() bool {
+	return upgrade.FromFormat == upgrade.ToFormat && len(upgrade.Changes) == 0
+}
+
+// Apply saves the converted rules, frozen to the new format, to a new
+// version created from the converted version. The new version is returned.
+//
+// Upgrades with validation errors are not saved; ValidationErrors is
+// returned instead.
+func (upgrade *RuleFormatUpgrade) Apply(correlationid string) (int, error) {
+	if len(upgrade.ValidationErrors) != 0 {
+		return 0, upgrade.ValidationErrors
+	}
+
+	property := upgrade.property
+
+	versions := NewVersions()
+	versions.PropertyID = property.PropertyID
+	versions.ContractID = property.ContractID
+	versions.GroupID = property.GroupID
+
+	version := versions.NewVersion(&Version{PropertyVersion: upgrade.Version}, false, correlationid)
+	if err := version.Save(correlationid); err != nil {
+		return 0, err
+	}
+
+	rules := upgrade.Rules
+	rules.PropertyID = property.PropertyID
+	rules.PropertyVersion = version.PropertyVersion
+	rules.ContractID = property.ContractID
+	rules.GroupID = property.GroupID
+	rules.RuleFormat = upgrade.ToFormat
+
+	if err := rules.Freeze(upgrade.ToFormat); err != nil {
+		return version.PropertyVersion, err
+	}
+
+	property.LatestVersion = version.PropertyVersion
+
+	return version.PropertyVersion, nil
+}
+
+// getRulesInFormat fetches the rules of a property version, converted by
+// PAPI to ruleFormat unless it is empty
+//
+// API Docs: https://developer.akamai.com/api/luna/papi/resources.html#getaruletree
+// Endpoint: GET /papi/v1/properties/{propertyId}/versions/{propertyVersion}/rules/{?contractId,groupId}
+func (property *Property) getRulesInFormat(version int, ruleFormat string, correlationid string) (*Rules, error) {
+	query := url.Values{}
+	if property.ContractID != "" {
+		query.Set("contractId", property.ContractID)
+	}
+	if property.GroupID != "" {
+		query.Set("groupId", property.GroupID)
+	}
+
+	req, err := client.NewRequest(
+		Config,
+		"GET",
+		fmt.Sprintf(
+			"/papi/v1/properties/%s/versions/%d/rules?%s",
+			property.PropertyID,
+			version,
+			query.Encode(),
+		),
+		nil,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	if ruleFormat != "" {
+		req.Header.Set("Accept", fmt.Sprintf("application/vnd.akamai.papirules.%s+json", ruleFormat))
+	}
+
+	edge.PrintHttpRequestCorrelation(req, true, correlationid)
+
+	res, err := client.Do(Config, req)
+	if err != nil {
+		return nil, err
+	}
+
+	edge.PrintHttpResponseCorrelation(res, true, correlationid)
+
+	if client.IsError(res) {
+		return nil, client.NewAPIError(res)
+	}
+
+	rules := NewRules()
+	if err = client.BodyJSON(res, rules); err != nil {
+		return nil, err
+	}
+
+	return rules, nil
+}
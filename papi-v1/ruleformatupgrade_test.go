@@ -0,0 +1,140 @@
+package papi
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"gopkg.in/h2non/gock.v1"
+)
+
+func mockRuleFormatUpgrade(product string, ttlPattern string) {
+	gock.New("https://akaa-baseurl-xxxxxxxxxxx-xxxxxxxxxxxxx.luna.akamaiapis.net").
+		Get("/papi/v1/rule-formats").
+		Reply(200).
+		SetHeader("Content-Type", "application/json").
+		BodyString(`{"ruleFormats": {"items": ["v2021-09-22", "latest", "v2015-08-17"]}}`)
+
+	gock.New("https://akaa-baseurl-xxxxxxxxxxx-xxxxxxxxxxxxx.luna.akamaiapis.net").
+		Get("/papi/v1/properties/prp_1/versions/4/rules").
+		MatchHeader("Accept", `vnd\.akamai\.papirules\.v2021-09-22\+json`).
+		Reply(200).
+		SetHeader("Content-Type", "application/vnd.akamai.papirules.v2021-09-22+json").
+		BodyString(`{"propertyId": "prp_1", "propertyVersion": 4, "ruleFormat": "v2021-09-22", "rules": {
+			"name": "default",
+			"behaviors": [
+				{"name": "origin", "uuid": "b1", "options": {"hostname": "origin.example.com", "httpPort": 80}},
+				{"name": "cachingTtl", "uuid": "b2", "options": {"ttl": "1d"}},
+				{"name": "http2", "uuid": "b4", "options": {"enabled": true}}
+			]
+		}}`)
+
+	gock.New("https://akaa-baseurl-xxxxxxxxxxx-xxxxxxxxxxxxx.luna.akamaiapis.net").
+		Get("/papi/v1/properties/prp_1/versions/4/rules").
+		Reply(200).
+		SetHeader("Content-Type", "application/json").
+		BodyString(`{"propertyId": "prp_1", "propertyVersion": 4, "ruleFormat": "v2015-08-17", "rules": {
+			"name": "default",
+			"behaviors": [
+				{"name": "origin", "uuid": "b1", "options": {"hostname": "origin.example.com"}},
+				{"name": "caching", "uuid": "b2", "options": {"ttl": "1d"}},
+				{"name": "edgeScape", "uuid": "b3", "options": {"enabled": true}},
+				{"name": "http2", "uuid": "b4", "options": {"enabled": true}}
+			]
+		}}`)
+
+	gock.New("https://akaa-baseurl-xxxxxxxxxxx-xxxxxxxxxxxxx.luna.akamaiapis.net").
+		Get("/papi/v1/schemas/products/" + product + "/v2021-09-22").
+		Reply(200).
+		SetHeader("Content-Type", "application/json").
+		BodyString(`{
+			"type": "object",
+			"properties": {"rules": {"type": "object", "properties": {"behaviors": {"type": "array", "items": {
+				"type": "object",
+				"properties": {"options": {"type": "object", "properties": {"ttl": {"type": "string", "pattern": "` + ttlPattern + `"}}}}
+			}}}}}
+		}`)
+}
+
+func newUpgradeTestProperty(product string) *Property {
+	property := NewProperty(NewProperties())
+	property.PropertyID = "prp_1"
+	property.ContractID = "ctr_1"
+	property.GroupID = "grp_1"
+	property.ProductID = product
+	property.LatestVersion = 4
+
+	return property
+}
+
+func TestProperty_PlanRuleFormatUpgrade(t *testing.T) {
+	defer gock.Off()
+
+	mockRuleFormatUpgrade("prd_Upgrade", `^[0-9]+[smhd]$`)
+
+	gock.New("https://akaa-baseurl-xxxxxxxxxxx-xxxxxxxxxxxxx.luna.akamaiapis.net").
+		Post("/papi/v1/properties/prp_1/versions").
+		BodyString(`"createFromVersion":\s*4`).
+		Reply(201).
+		SetHeader("Content-Type", "application/json").
+		BodyString(`{"versionLink": "/papi/v1/properties/prp_1/versions/5?contractId=ctr_1&groupId=grp_1"}`)
+
+	gock.New("https://akaa-baseurl-xxxxxxxxxxx-xxxxxxxxxxxxx.luna.akamaiapis.net").
+		Get("/papi/v1/properties/prp_1/versions/5").
+		Reply(200).
+		SetHeader("Content-Type", "application/json").
+		BodyString(`{"versions": {"items": [{"propertyVersion": 5}]}}`)
+
+	gock.New("https://akaa-baseurl-xxxxxxxxxxx-xxxxxxxxxxxxx.luna.akamaiapis.net").
+		Put("/papi/v1/properties/prp_1/versions/5/rules").
+		MatchHeader("Content-Type", `application/vnd\.akamai\.papirules\.v2021-09-22\+json`).
+		Reply(200).
+		SetHeader("Content-Type", "application/json").
+		BodyString(`{"propertyId": "prp_1", "propertyVersion": 5, "ruleFormat": "v2021-09-22", "rules": {"name": "default"}}`)
+
+	Init(config)
+
+	property := newUpgradeTestProperty("prd_Upgrade")
+	upgrade, err := property.PlanRuleFormatUpgrade("", "")
+	assert.NoError(t, err)
+
+	assert.Equal(t, "v2015-08-17", upgrade.FromFormat)
+	assert.Equal(t, "v2021-09-22", upgrade.ToFormat)
+	assert.Empty(t, upgrade.ValidationErrors)
+	assert.False(t, upgrade.IsNoop())
+
+	assert.Len(t, upgrade.Removed, 1)
+	assert.Equal(t, "edgeScape", upgrade.Removed[0].Name)
+
+	assert.Len(t, upgrade.Renamed, 1)
+	assert.Equal(t, "caching", upgrade.Renamed[0].OldName)
+	assert.Equal(t, "cachingTtl", upgrade.Renamed[0].Name)
+
+	assert.Len(t, upgrade.Options, 1)
+	assert.Equal(t, "origin", upgrade.Options[0].Name)
+	assert.Equal(t, "httpPort", upgrade.Options[0].Options[0].Name)
+	assert.Equal(t, ChangeAdded, upgrade.Options[0].Options[0].Type)
+
+	version, err := upgrade.Apply("")
+	assert.NoError(t, err)
+	assert.Equal(t, 5, version)
+	assert.Equal(t, 5, property.LatestVersion)
+	assert.True(t, gock.IsDone())
+}
+
+func TestProperty_PlanRuleFormatUpgrade_Invalid(t *testing.T) {
+	defer gock.Off()
+
+	mockRuleFormatUpgrade("prd_Upgrade_Invalid", `^[0-9]+$`)
+
+	Init(config)
+
+	upgrade, err := newUpgradeTestProperty("prd_Upgrade_Invalid").PlanRuleFormatUpgrade("", "")
+	assert.NoError(t, err)
+	assert.Len(t, upgrade.ValidationErrors, 1)
+
+	_, err = upgrade.Apply("")
+	var validationErrors RuleValidationErrors
+	assert.True(t, errors.As(err, &validationErrors))
+	assert.True(t, gock.IsDone())
+}
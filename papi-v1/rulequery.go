@@ -0,0 +1,597 @@
+package papi
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+// SkipRule is returned by a RuleWalkFunc to skip the children of the rule
+var SkipRule = errors.New("skip this rule")
+
+// RuleWalkFunc is called by Rules.Walk for each rule
+//
+// path is the slash separated path of rule names, starting at the default
+// rule, e.g. default/Performance/Compressible Objects.
+type RuleWalkFunc func(path string, rule *Rule) error
+
+// Walk calls fn for every rule in the tree, parents before their children
+//
+// If fn returns SkipRule the rule's children are skipped. Any other error
+// stops the walk and is returned.
+func (rules *Rules) Walk(fn RuleWalkFunc) error {
+	if rules.Rule == nil {
+		return nil
+	}
+
+	err := walkRule(rules.Rule, rules.Rule.Name, fn)
+	if err == SkipRule {
+		return nil
+	}
+
+	return err
+}
+
+func walkRule(rule *Rule, path string, fn RuleWalkFunc) error {
+	if err := fn(path, rule); err != nil {
+		return err
+	}
+
+	for _, child := range rule.Children {
+		err := walkRule(child, path+"/"+child.Name, fn)
+		if err != nil && err != SkipRule {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// SelectorKindValue is used to create an "enum" of possible Selector.Kind values
+type SelectorKindValue string
+
+const (
+	// SelectorRule Selector.Kind value rule
+	SelectorRule SelectorKindValue = "rule"
+	// SelectorBehavior Selector.Kind value behavior
+	SelectorBehavior SelectorKindValue = "behavior"
+	// SelectorCriteria Selector.Kind value criteria
+	SelectorCriteria SelectorKindValue = "criteria"
+)
+
+// Selector finds rules, behaviors or criteria in a rule tree
+//
+// The syntax is kind[:name][predicate]..., for example:
+//
+//	behavior:origin[hostname$=.internal]
+//	criteria:path[values*=/images/]
+//	rule:Static*
+//	behavior:caching[behavior=MAX_AGE][ttl!=1d]
+//	criteria:path[values%=/images/a.png]
+//
+// kind is rule, behavior or criteria. name is matched case-insensitively and
+// may contain * wildcards. Predicates test behavior or criteria options, or
+// the name, comments, uuid and criteriaMustSatisfy of rules. Nested options
+// are addressed with dots (value.id). A predicate is [key] for a set option,
+// or [key op value] with op one of = != ^= (prefix) $= (suffix) *= (contains)
+// or ~= (regular expression). Values may be double quoted. Options holding
+// arrays match when any element matches (none, for !=).
+//
+// %= evaluates the option as PAPI does, testing the value against it: option
+// values are wildcard patterns where * matches any characters and ? a single
+// one, the match is case-insensitive unless matchCaseSensitive is true, and
+// a negative matchOperator such as DOES_NOT_MATCH_ONE_OF or IS_NOT_ONE_OF
+// inverts it.
+type Selector struct {
+	Kind       SelectorKindValue
+	Name       string
+	Predicates []*SelectorPredicate
+
+	name *regexp.Regexp
+}
+
+// SelectorPredicate is a single [key op value] test of a Selector
+type SelectorPredicate struct {
+	Key      string
+	Operator string
+	Value    string
+
+	pattern *regexp.Regexp
+}
+
+var selectorOperators = []string{"!=", "^=", "$=", "*=", "~=", "%=", "="}
+
+// ParseSelector parses a selector
+//
+// See: Selector
+func ParseSelector(selector string) (*Selector, error) {
+	parsed := &Selector{}
+	rest := strings.TrimSpace(selector)
+
+	end := strings.IndexAny(rest, ":[")
+	if end == -1 {
+		end = len(rest)
+	}
+	parsed.Kind = SelectorKindValue(strings.ToLower(rest[:end]))
+	rest = rest[end:]
+
+	switch parsed.Kind {
+	case SelectorRule, SelectorBehavior, SelectorCriteria:
+	default:
+		return nil, fmt.Errorf("%w: unknown kind %q in %q", ErrorMap[ErrInvalidSelector], parsed.Kind, selector)
+	}
+
+	if strings.HasPrefix(rest, ":") {
+		end = strings.Index(rest, "[")
+		if end == -1 {
+			end = len(rest)
+		}
+		parsed.Name = rest[1:end]
+		rest = rest[end:]
+
+		pattern := "(?i)^" + strings.Replace(regexp.QuoteMeta(parsed.Name), `\*`, ".*", -1) + "$"
+		parsed.name = regexp.MustCompile(pattern)
+	}
+
+	for rest != "" {
+		predicate, remaining, err := parsePredicate(rest)
+		if err != nil {
+			return nil, fmt.Errorf("%w: %s in %q", ErrorMap[ErrInvalidSelector], err, selector)
+		}
+		parsed.Predicates = append(parsed.Predicates, predicate)
+		rest = remaining
+	}
+
+	return parsed, nil
+}
+
+// parsePredicate parses the [key op value] at the start of selector
+func parsePredicate(selector string) (*SelectorPredicate, string, error) {
+	if !strings.HasPrefix(selector, "[") {
+		return nil, "", fmt.Errorf("expected [ at %q", selector)
+	}
+
+	predicate := &SelectorPredicate{}
+	rest := selector[1:]
+
+	end := strings.IndexAny(rest, "!^$*~%=]")
+	if end == -1 {
+		return nil, "", fmt.Errorf("unterminated predicate %q", selector)
+	}
+	predicate.Key = strings.TrimSpace(rest[:end])
+	rest = rest[end:]
+
+	if predicate.Key == "" {
+		return nil, "", fmt.Errorf("missing key in %q", selector)
+	}
+
+	if strings.HasPrefix(rest, "]") {
+		return predicate, rest[1:], nil
+	}
+
+	for _, operator := range selectorOperators {
+		if strings.HasPrefix(rest, operator) {
+			predicate.Operator = operator
+			rest = strings.TrimLeft(rest[len(operator):], " ")
+			break
+		}
+	}
+	if predicate.Operator == "" {
+		return nil, "", fmt.Errorf("unknown operator at %q", rest)
+	}
+
+	if strings.HasPrefix(rest, `"`) {
+		value, remaining, err := parseQuoted(rest)
+		if err != nil {
+			return nil, "", err
+		}
+		predicate.Value = value
+		rest = strings.TrimLeft(remaining, " ")
+		if !strings.HasPrefix(rest, "]") {
+			return nil, "", fmt.Errorf("expected ] at %q", rest)
+		}
+	} else {
+		end = strings.Index(rest, "]")
+		if end == -1 {
+			return nil, "", fmt.Errorf("unterminated predicate %q", selector)
+		}
+		predicate.Value = strings.TrimSpace(rest[:end])
+		rest = rest[end:]
+	}
+
+	if predicate.Operator == "~=" {
+		pattern, err := regexp.Compile(predicate.Value)
+		if err != nil {
+			return nil, "", err
+		}
+		predicate.pattern = pattern
+	}
+
+	return predicate, rest[1:], nil
+}
+
+// parseQuoted parses the double quoted string at the start of value
+func parseQuoted(value string) (string, string, error) {
+	escaped := false
+	for key := 1; key < len(value); key++ {
+		switch {
+		case escaped:
+			escaped = false
+		case value[key] == '\\':
+			escaped = true
+		case value[key] == '"':
+			unquoted, err := strconv.Unquote(value[:key+1])
+			return unquoted, value[key+1:], err
+		}
+	}
+
+	return "", "", fmt.Errorf("unterminated string %s", value)
+}
+
+// matchName reports whether name matches the selector name, if any
+func (selector *Selector) matchName(name string) bool {
+	return selector.name == nil || selector.name.MatchString(name)
+}
+
+// matchFields reports whether all predicates hold for the given fields
+func (selector *Selector) matchFields(fields map[string]interface{}) bool {
+	for _, predicate := range selector.Predicates {
+		if !predicate.Match(fields) {
+			return false
+		}
+	}
+
+	return true
+}
+
+// Match reports whether the predicate holds for the given options
+func (predicate *SelectorPredicate) Match(options map[string]interface{}) bool {
+	value, ok := lookupOption(options, predicate.Key)
+	if predicate.Operator == "" {
+		return ok && value != nil
+	}
+
+	values := optionStrings(value)
+	if predicate.Operator == "%=" {
+		return matchCriteriaValues(options, values, predicate.Value)
+	}
+
+	if predicate.Operator == "!=" {
+		for _, candidate := range values {
+			if candidate == predicate.Value {
+				return false
+			}
+		}
+		return true
+	}
+
+	for _, candidate := range values {
+		switch predicate.Operator {
+		case "=":
+			if candidate == predicate.Value {
+				return true
+			}
+		case "^=":
+			if strings.HasPrefix(candidate, predicate.Value) {
+				return true
+			}
+		case "$=":
+			if strings.HasSuffix(candidate, predicate.Value) {
+				return true
+			}
+		case "*=":
+			if strings.Contains(candidate, predicate.Value) {
+				return true
+			}
+		case "~=":
+			if predicate.pattern.MatchString(candidate) {
+				return true
+			}
+		}
+	}
+
+	return false
+}
+
+// matchCriteriaValues reports whether PAPI would consider value a match for
+// criteria options whose values are patterns
+func matchCriteriaValues(options map[string]interface{}, patterns []string, value string) bool {
+	caseSensitive, _ := options["matchCaseSensitive"].(bool)
+	operator, _ := options["matchOperator"].(string)
+
+	matched := false
+	for _, pattern := range patterns {
+		if matchWildcard(pattern, value, caseSensitive) {
+			matched = true
+			break
+		}
+	}
+
+	if strings.Contains(operator, "NOT") {
+		return !matched
+	}
+
+	return matched
+}
+
+// matchWildcard matches value against a PAPI wildcard pattern, in which *
+// matches any number of characters and ? matches exactly one
+func matchWildcard(pattern string, value string, caseSensitive bool) bool {
+	if !caseSensitive {
+		pattern = strings.ToLower(pattern)
+		value = strings.ToLower(value)
+	}
+
+	patternRunes := []rune(pattern)
+	valueRunes := []rune(value)
+
+	// The last * seen, and the value position it is currently matched up to
+	star, starValue := -1, 0
+	p, v := 0, 0
+	for v < len(valueRunes) {
+		switch {
+		case p < len(patternRunes) && (patternRunes[p] == '?' || patternRunes[p] == valueRunes[v]):
+			p++
+			v++
+		case p < len(patternRunes) && patternRunes[p] == '*':
+			star, starValue = p, v
+			p++
+		case star != -1:
+			starValue++
+			p, v = star+1, starValue
+		default:
+			return false
+		}
+	}
+
+	for p < len(patternRunes) && patternRunes[p] == '*' {
+		p++
+	}
+
+	return p == len(patternRunes)
+}
+
+// lookupOption resolves a dotted key within nested options
+func lookupOption(options map[string]interface{}, key string) (interface{}, bool) {
+	var value interface{} = options
+	for _, segment := range strings.Split(key, ".") {
+		var node map[string]interface{}
+		switch typed := value.(type) {
+		case map[string]interface{}:
+			node = typed
+		case OptionValue:
+			node = typed
+		default:
+			return nil, false
+		}
+
+		var ok bool
+		if value, ok = node[segment]; !ok {
+			return nil, false
+		}
+	}
+
+	return value, true
+}
+
+// optionStrings returns the string forms of an option value, one per element
+// for arrays
+func optionStrings(value interface{}) []string {
+	switch typed := value.(type) {
+	case nil:
+		return nil
+	case string:
+		return []string{typed}
+	case float64:
+		return []string{strconv.FormatFloat(typed, 'f', -1, 64)}
+	case []interface{}:
+		var values []string
+		for _, element := range typed {
+			values = append(values, optionStrings(element)...)
+		}
+		return values
+	case []string:
+		return typed
+	case map[string]interface{}, OptionValue:
+		data, _ := json.Marshal(typed)
+		return []string{string(data)}
+	}
+
+	return []string{fmt.Sprint(value)}
+}
+
+// RuleMatch is a rule, behavior or criteria found by Rules.Select
+//
+// Path and Rule are the matched rule, or the rule holding the matched
+// behavior or criteria. Parent is the rule's parent, nil for the default rule.
+type RuleMatch struct {
+	Path     string
+	Rule     *Rule
+	Parent   *Rule
+	Behavior *Behavior
+	Criteria *Criteria
+}
+
+// Options returns the options of the matched behavior or criteria
+func (match *RuleMatch) Options() OptionValue {
+	switch {
+	case match.Behavior != nil:
+		return match.Behavior.Options
+	case match.Criteria != nil:
+		return match.Criteria.Options
+	}
+
+	return nil
+}
+
+// RuleMatches is the list of matches returned by Rules.Select, in tree order
+type RuleMatches []*RuleMatch
+
+// Select returns the rules, behaviors or criteria matching a selector
+//
+// See: Selector
+func (rules *Rules) Select(selector string) (RuleMatches, error) {
+	parsed, err := ParseSelector(selector)
+	if err != nil {
+		return nil, err
+	}
+
+	return rules.SelectWith(parsed), nil
+}
+
+// SelectPath returns the path criteria matching a request path, such as
+// /images/a.png, as PAPI evaluates them
+//
+// Only the path criteria themselves are evaluated; a matched rule may have
+// other criteria, or ancestors whose criteria do not match.
+func (rules *Rules) SelectPath(path string) RuleMatches {
+	return rules.SelectWith(&Selector{
+		Kind:       SelectorCriteria,
+		Name:       "path",
+		Predicates: []*SelectorPredicate{{Key: "values", Operator: "%=", Value: path}},
+		name:       regexp.MustCompile("(?i)^path$"),
+	})
+}
+
+// SelectWith returns the rules, behaviors or criteria matching a parsed selector
+func (rules *Rules) SelectWith(selector *Selector) RuleMatches {
+	matches := RuleMatches{}
+	parents := map[*Rule]*Rule{}
+
+	rules.Walk(func(path string, rule *Rule) error {
+		for _, child := range rule.Children {
+			parents[child] = rule
+		}
+
+		switch selector.Kind {
+		case SelectorRule:
+			fields := map[string]interface{}{
+				"name":                rule.Name,
+				"comments":            rule.Comments,
+				"uuid":                rule.UUID,
+				"criteriaMustSatisfy": string(rule.CriteriaMustSatisfy),
+			}
+			if selector.matchName(rule.Name) && selector.matchFields(fields) {
+				matches = append(matches, &RuleMatch{Path: path, Rule: rule, Parent: parents[rule]})
+			}
+		case SelectorBehavior:
+			for _, behavior := range rule.Behaviors {
+				if selector.matchName(behavior.Name) && selector.matchFields(behavior.Options) {
+					matches = append(matches, &RuleMatch{Path: path, Rule: rule, Parent: parents[rule], Behavior: behavior})
+				}
+			}
+		case SelectorCriteria:
+			for _, criteria := range rule.Criteria {
+				if selector.matchName(criteria.Name) && selector.matchFields(criteria.Options) {
+					matches = append(matches, &RuleMatch{Path: path, Rule: rule, Parent: parents[rule], Criteria: criteria})
+				}
+			}
+		}
+
+		return nil
+	})
+
+	return matches
+}
+
+// Update calls fn for each match, stopping at the first error
+func (matches RuleMatches) Update(fn func(match *RuleMatch) error) error {
+	for _, match := range matches {
+		if err := fn(match); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// SetOption sets an option on every matched behavior or criteria
+//
+// Nested options are addressed with dots (value.id) and created as needed.
+func (matches RuleMatches) SetOption(key string, value interface{}) {
+	for _, match := range matches {
+		options := match.Options()
+		if options == nil {
+			if match.Behavior == nil && match.Criteria == nil {
+				continue
+			}
+			options = OptionValue{}
+			if match.Behavior != nil {
+				match.Behavior.Options = options
+			} else {
+				match.Criteria.Options = options
+			}
+		}
+
+		segments := strings.Split(key, ".")
+		node := map[string]interface{}(options)
+		for _, segment := range segments[:len(segments)-1] {
+			child, ok := node[segment].(map[string]interface{})
+			if !ok {
+				if optionValue, isOptionValue := node[segment].(OptionValue); isOptionValue {
+					child = optionValue
+				} else {
+					child = map[string]interface{}{}
+					node[segment] = child
+				}
+			}
+			node = child
+		}
+		node[segments[len(segments)-1]] = value
+	}
+}
+
+// DeleteOption removes an option from every matched behavior or criteria
+func (matches RuleMatches) DeleteOption(key string) {
+	for _, match := range matches {
+		segments := strings.Split(key, ".")
+		var parent interface{} = map[string]interface{}(match.Options())
+		if len(segments) > 1 {
+			var ok bool
+			if parent, ok = lookupOption(match.Options(), strings.Join(segments[:len(segments)-1], ".")); !ok {
+				continue
+			}
+		}
+
+		switch node := parent.(type) {
+		case map[string]interface{}:
+			delete(node, segments[len(segments)-1])
+		case OptionValue:
+			delete(node, segments[len(segments)-1])
+		}
+	}
+}
+
+// Remove removes the matched behaviors, criteria and rules from the tree
+//
+// The default rule cannot be removed and is left in place.
+func (matches RuleMatches) Remove() {
+	for _, match := range matches {
+		switch {
+		case match.Behavior != nil:
+			for key, behavior := range match.Rule.Behaviors {
+				if behavior == match.Behavior {
+					match.Rule.Behaviors = append(match.Rule.Behaviors[:key], match.Rule.Behaviors[key+1:]...)
+					break
+				}
+			}
+		case match.Criteria != nil:
+			for key, criteria := range match.Rule.Criteria {
+				if criteria == match.Criteria {
+					match.Rule.Criteria = append(match.Rule.Criteria[:key], match.Rule.Criteria[key+1:]...)
+					break
+				}
+			}
+		case match.Parent != nil:
+			for key, child := range match.Parent.Children {
+				if child == match.Rule {
+					match.Parent.Children = append(match.Parent.Children[:key], match.Parent.Children[key+1:]...)
+					break
+				}
+			}
+		}
+	}
+}
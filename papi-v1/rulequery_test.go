@@ -0,0 +1,172 @@
+package papi
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+const ruleQueryTree = `{
+	"rules": {
+		"name": "default",
+		"behaviors": [
+			{"name": "origin", "options": {"hostname": "origin.example.com", "httpPort": 80}},
+			{"name": "cpCode", "options": {"value": {"id": 12345}}}
+		],
+		"children": [
+			{
+				"name": "Images",
+				"criteria": [{"name": "path", "options": {"matchOperator": "MATCHES_ONE_OF", "values": ["/images/*", "/img/*"]}}],
+				"behaviors": [{"name": "caching", "options": {"behavior": "MAX_AGE", "ttl": "1d"}}],
+				"children": [
+					{
+						"name": "Internal Images",
+						"behaviors": [{"name": "origin", "options": {"hostname": "images.internal", "httpPort": 8080}}]
+					}
+				]
+			},
+			{
+				"name": "Scripts",
+				"criteria": [{"name": "fileExtension", "options": {"values": ["js"]}}],
+				"behaviors": [
+					{"name": "caching", "options": {"behavior": "MAX_AGE", "ttl": "7d"}},
+					{"name": "origin", "options": {"hostname": "scripts.internal", "httpPort": 80}}
+				]
+			}
+		]
+	}
+}`
+
+func TestRules_Walk(t *testing.T) {
+	rules := rulesFromJSON(t, ruleQueryTree)
+
+	var paths []string
+	err := rules.Walk(func(path string, rule *Rule) error {
+		paths = append(paths, path)
+		if rule.Name == "Images" {
+			return SkipRule
+		}
+		return nil
+	})
+
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"default", "default/Images", "default/Scripts"}, paths)
+
+	stop := errors.New("stop")
+	err = rules.Walk(func(path string, rule *Rule) error {
+		return stop
+	})
+	assert.Equal(t, stop, err)
+}
+
+func TestRules_Select(t *testing.T) {
+	rules := rulesFromJSON(t, ruleQueryTree)
+
+	tests := map[string][]string{
+		`behavior:origin[hostname$=.internal]`:        {"default/Images/Internal Images", "default/Scripts"},
+		`behavior:origin[httpPort=80]`:                {"default", "default/Scripts"},
+		`behavior:cpCode[value.id=12345]`:             {"default"},
+		`behavior:caching[ttl!=1d]`:                   {"default/Scripts"},
+		`criteria:path[values*=/images/]`:             {"default/Images"},
+		`criteria:*[values]`:                          {"default/Images", "default/Scripts"},
+		`rule:*images`:                                {"default/Images", "default/Images/Internal Images"},
+		`rule[name~="^(Scripts|Fonts)$"]`:             {"default/Scripts"},
+		`behavior:ORIGIN[hostname ^= "origin."]`:      {"default"},
+		`behavior[behavior=MAX_AGE][ttl="7d"]`:        {"default/Scripts"},
+		`behavior:origin[hostname$=.example.org]`:     {},
+		`criteria:fileExtension[values=js][missing]`:  {},
+		`behavior:origin[hostname="a]b"][httpPort=1]`: {},
+		`criteria:path[values%=/IMAGES/a.png]`:        {"default/Images"},
+		`criteria:path[values%=/images]`:              {},
+	}
+
+	for selector, expected := range tests {
+		matches, err := rules.Select(selector)
+		if !assert.NoError(t, err, selector) {
+			continue
+		}
+
+		paths := []string{}
+		for _, match := range matches {
+			paths = append(paths, match.Path)
+		}
+		assert.Equal(t, expected, paths, selector)
+	}
+
+	for _, selector := range []string{"origin", "behavior:origin[hostname", "behavior[=x]", "rule[name~=(]", `rule[name="x]`} {
+		_, err := rules.Select(selector)
+		assert.True(t, errors.Is(err, ErrorMap[ErrInvalidSelector]), selector)
+	}
+}
+
+func TestRules_SelectPath(t *testing.T) {
+	rules := rulesFromJSON(t, `{"rules": {"name": "default", "children": [
+		{"name": "Images", "criteria": [{"name": "path", "options": {"matchOperator": "MATCHES_ONE_OF", "values": ["/images/*", "/img/*.??g"]}}]},
+		{"name": "Not API", "criteria": [{"name": "path", "options": {"matchOperator": "DOES_NOT_MATCH_ONE_OF", "values": ["/api/*"]}}]},
+		{"name": "Exact", "criteria": [{"name": "path", "options": {"matchOperator": "MATCHES_ONE_OF", "values": ["/Images/Logo.png"], "matchCaseSensitive": true}}]},
+		{"name": "Extension", "criteria": [{"name": "fileExtension", "options": {"matchOperator": "IS_NOT_ONE_OF", "values": ["png"]}}]}
+	]}}`)
+
+	tests := map[string][]string{
+		"/images/a.png":    {"default/Images", "default/Not API"},
+		"/Images/Logo.png": {"default/Images", "default/Not API", "default/Exact"},
+		"/img/a.png":       {"default/Images", "default/Not API"},
+		"/img/a.gif":       {"default/Not API"},
+		"/api/users":       {},
+		"/images":          {"default/Not API"},
+	}
+
+	for path, expected := range tests {
+		paths := []string{}
+		for _, match := range rules.SelectPath(path) {
+			paths = append(paths, match.Path)
+		}
+		assert.Equal(t, expected, paths, path)
+	}
+}
+
+func TestMatchWildcard(t *testing.T) {
+	assert.True(t, matchWildcard("*", "", false))
+	assert.True(t, matchWildcard("/a/*/c*", "/a/b/x/c", false))
+	assert.True(t, matchWildcard("/a?c", "/abc", false))
+	assert.False(t, matchWildcard("/a?c", "/ac", false))
+	assert.False(t, matchWildcard("/A", "/a", true))
+	assert.True(t, matchWildcard("/A", "/a", false))
+	assert.True(t, matchWildcard("*.png", "/a.b.png", false))
+	assert.False(t, matchWildcard("*.png", "/a.png.gif", false))
+}
+
+func TestRuleMatches_Update(t *testing.T) {
+	rules := rulesFromJSON(t, ruleQueryTree)
+
+	matches, err := rules.Select(`behavior:origin[hostname$=.internal]`)
+	assert.NoError(t, err)
+
+	matches.SetOption("httpPort", 443)
+	matches.SetOption("customCertificates.enabled", true)
+	assert.Equal(t, 443, rules.Rule.Children[1].Behaviors[1].Options["httpPort"])
+	assert.Equal(t, map[string]interface{}{"enabled": true}, rules.Rule.Children[0].Children[0].Behaviors[0].Options["customCertificates"])
+	assert.Equal(t, float64(80), rules.Rule.Behaviors[0].Options["httpPort"])
+
+	matches.DeleteOption("customCertificates.enabled")
+	assert.Equal(t, map[string]interface{}{}, rules.Rule.Children[1].Behaviors[1].Options["customCertificates"])
+
+	err = matches.Update(func(match *RuleMatch) error {
+		match.Behavior.Options["hostname"] = "origin.example.com"
+		return nil
+	})
+	assert.NoError(t, err)
+	assert.Equal(t, "origin.example.com", rules.Rule.Children[1].Behaviors[1].Options["hostname"])
+
+	matches, err = rules.Select(`behavior:caching`)
+	assert.NoError(t, err)
+	matches.Remove()
+	assert.Len(t, rules.Rule.Children[0].Behaviors, 0)
+	assert.Len(t, rules.Rule.Children[1].Behaviors, 1)
+
+	matches, err = rules.Select(`rule:Internal*`)
+	assert.NoError(t, err)
+	matches.Remove()
+	assert.Len(t, rules.Rule.Children[0].Children, 0)
+}
@@ -16,6 +16,8 @@ type Rules struct {
 	GroupID         string        `json:"groupId"`
 	PropertyID      string        `json:"propertyId"`
 	PropertyVersion int           `json:"propertyVersion"`
+	IncludeID       string        `json:"includeId,omitempty"`
+	IncludeVersion  int           `json:"includeVersion,omitempty"`
 	Etag            string        `json:"etag"`
 	RuleFormat      string        `json:"ruleFormat"`
 	Rule            *Rule         `json:"rules"`
@@ -455,6 +457,92 @@ func (rules *Rules) FindParentRule(path string) (*Rule, error) {
 	return rules.FindRule(parentPath)
 }
 
+// RulePointer returns the JSON pointer to the rule at a given path
+//
+// The path uses the same conventions as FindRule, and the pointer can be used
+// in JSON Patch operations against the rule tree, e.g. "/rules/children/1"
+func (rules *Rules) RulePointer(path string) (string, error) {
+	_, pointer, err := rules.findRulePointer(path)
+	return pointer, err
+}
+
+// findRulePointer locates a rule and its JSON pointer by path
+func (rules *Rules) findRulePointer(path string) (*Rule, string, error) {
+	sep := "/"
+	pointer := "/rules"
+
+	path = strings.Trim(path, sep)
+	if path == "" {
+		return rules.Rule, pointer, nil
+	}
+
+	currentRule := rules.Rule
+	for _, segment := range strings.Split(strings.ToLower(path), sep) {
+		found := false
+		for key, rule := range currentRule.Children {
+			if strings.ToLower(rule.Name) == segment {
+				currentRule = rule
+				pointer = fmt.Sprintf("%s/children/%d", pointer, key)
+				found = true
+				break
+			}
+		}
+		if !found {
+			return nil, "", ErrorMap[ErrRuleNotFound]
+		}
+	}
+
+	return currentRule, pointer, nil
+}
+
+// BehaviorPointer returns the JSON pointer to the behavior at a given path
+//
+// The path uses the same conventions as FindBehavior
+func (rules *Rules) BehaviorPointer(path string) (string, error) {
+	if len(path) <= 1 {
+		return "", ErrorMap[ErrInvalidPath]
+	}
+
+	sep := "/"
+	segments := strings.Split(strings.ToLower(strings.Trim(path, sep)), sep)
+	rule, pointer, err := rules.findRulePointer(strings.Join(segments[0:len(segments)-1], sep))
+	if err != nil {
+		return "", err
+	}
+
+	for key, behavior := range rule.Behaviors {
+		if strings.ToLower(behavior.Name) == segments[len(segments)-1] {
+			return fmt.Sprintf("%s/behaviors/%d", pointer, key), nil
+		}
+	}
+
+	return "", ErrorMap[ErrBehaviorNotFound]
+}
+
+// CriteriaPointer returns the JSON pointer to the criteria at a given path
+//
+// The path uses the same conventions as FindCriteria
+func (rules *Rules) CriteriaPointer(path string) (string, error) {
+	if len(path) <= 1 {
+		return "", ErrorMap[ErrInvalidPath]
+	}
+
+	sep := "/"
+	segments := strings.Split(strings.ToLower(strings.Trim(path, sep)), sep)
+	rule, pointer, err := rules.findRulePointer(strings.Join(segments[0:len(segments)-1], sep))
+	if err != nil {
+		return "", err
+	}
+
+	for key, criteria := range rule.Criteria {
+		if strings.ToLower(criteria.Name) == segments[len(segments)-1] {
+			return fmt.Sprintf("%s/criteria/%d", pointer, key), nil
+		}
+	}
+
+	return "", ErrorMap[ErrCriteriaNotFound]
+}
+
 // Criteria represents a rule criteria resource
 type Criteria struct {
 	client.Resource
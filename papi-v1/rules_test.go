@@ -1842,6 +1842,46 @@ func TestRules_GetRules_Variables(t *testing.T) {
 	assert.False(t, rules.Rule.Variables[0].Sensitive)
 }
 
+func TestRules_Pointers(t *testing.T) {
+	rules := NewRules()
+	parent := NewRule()
+	parent.Name = "Parent"
+	child := NewRule()
+	child.Name = "Child"
+	behavior := NewBehavior()
+	behavior.Name = "caching"
+	criteria := NewCriteria()
+	criteria.Name = "path"
+	child.AddBehavior(NewBehavior())
+	child.AddBehavior(behavior)
+	child.AddCriteria(criteria)
+	parent.AddChildRule(NewRule())
+	parent.AddChildRule(child)
+	rules.Rule.AddChildRule(parent)
+
+	pointer, err := rules.RulePointer("")
+	assert.NoError(t, err)
+	assert.Equal(t, "/rules", pointer)
+
+	pointer, err = rules.RulePointer("parent/child")
+	assert.NoError(t, err)
+	assert.Equal(t, "/rules/children/0/children/1", pointer)
+
+	pointer, err = rules.BehaviorPointer("/parent/child/caching")
+	assert.NoError(t, err)
+	assert.Equal(t, "/rules/children/0/children/1/behaviors/1", pointer)
+
+	pointer, err = rules.CriteriaPointer("parent/child/path")
+	assert.NoError(t, err)
+	assert.Equal(t, "/rules/children/0/children/1/criteria/0", pointer)
+
+	_, err = rules.RulePointer("parent/missing")
+	assert.Equal(t, ErrorMap[ErrRuleNotFound], err)
+
+	_, err = rules.BehaviorPointer("parent/child/origin")
+	assert.Equal(t, ErrorMap[ErrBehaviorNotFound], err)
+}
+
 func assertRulesMatch(t *testing.T, expected *Rule, actual *Rule) bool {
 	valid := true
 
@@ -0,0 +1,176 @@
+package papi
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+
+	"github.com/akamai/AkamaiOPEN-edgegrid-golang/client-v1"
+	edge "github.com/akamai/AkamaiOPEN-edgegrid-golang/edgegrid"
+	"github.com/akamai/AkamaiOPEN-edgegrid-golang/jsonpatch-v1"
+)
+
+// RulesConflictError is returned by Rules.SavePatch when the rule tree was
+// changed since it was fetched and the local changes cannot be rebased onto
+// the new rule tree
+type RulesConflictError struct {
+	// Local are the changes made to the rule tree being saved
+	Local RuleChanges
+	// Remote are the changes made to the rule tree on the server
+	Remote RuleChanges
+	// LocalPatch and RemotePatch are the same changes as JSON Patch documents
+	LocalPatch  jsonpatch.Patch
+	RemotePatch jsonpatch.Patch
+	// Current is the rule tree as it is now on the server
+	Current *Rules
+	// Err describes the overlapping changes
+	Err error
+}
+
+func (err *RulesConflictError) Error() string {
+	return fmt.Sprintf(
+		"rule tree for %s version %d was modified concurrently: %s\nlocal changes:\n%s\nremote changes:\n%s",
+		err.Current.PropertyID,
+		err.Current.PropertyVersion,
+		err.Err,
+		strings.TrimSpace(err.Local.String()),
+		strings.TrimSpace(err.Remote.String()),
+	)
+}
+
+// Unwrap returns the underlying jsonpatch.ErrConflict
+func (err *RulesConflictError) Unwrap() error {
+	return err.Err
+}
+
+// SavePatch updates a rule tree for a property by sending only the changes
+// made since base was fetched
+//
+// base is the rule tree that rules was edited from, usually a copy made right
+// after Property.GetRules. The changes are sent as a JSON Patch with If-Match
+// set to base.Etag. If the rule tree was modified on the server in the
+// meantime, the current rule tree is fetched and the changes rebased onto it.
+// When the changes overlap a *RulesConflictError is returned and nothing is
+// saved.
+//
+// API Docs: https://developer.akamai.com/api/core_features/property_manager/v1.html#patchpropertyversionrules
+// Endpoint: PATCH /papi/v1/properties/{propertyId}/versions/{propertyVersion}/rules{?contractId,groupId}
+func (rules *Rules) SavePatch(base *Rules, correlationid string) error {
+	local, err := rulesPatch(base, rules)
+	if err != nil {
+		return err
+	}
+
+	if len(local) == 0 {
+		rules.Etag = base.Etag
+		return nil
+	}
+
+	conflict, err := rules.sendPatch(local, base.Etag, correlationid)
+	if err != nil || !conflict {
+		return err
+	}
+
+	property := NewProperty(NewProperties())
+	property.PropertyID = rules.PropertyID
+	property.ContractID = rules.ContractID
+	property.GroupID = rules.GroupID
+	property.LatestVersion = rules.PropertyVersion
+
+	current, err := property.GetRules(correlationid)
+	if err != nil {
+		return err
+	}
+
+	remote, err := rulesPatch(base, current)
+	if err != nil {
+		return err
+	}
+
+	rebased, err := jsonpatch.Rebase(local, remote)
+	if err == nil {
+		conflict, err = rules.sendPatch(rebased, current.Etag, correlationid)
+		if err != nil || !conflict {
+			return err
+		}
+		err = fmt.Errorf("%w: rule tree modified again while rebasing", jsonpatch.ErrConflict)
+	}
+
+	if !errors.Is(err, jsonpatch.ErrConflict) {
+		return err
+	}
+
+	return &RulesConflictError{
+		Local:       DiffRules(base, rules),
+		Remote:      DiffRules(base, current),
+		LocalPatch:  local,
+		RemotePatch: remote,
+		Current:     current,
+		Err:         err,
+	}
+}
+
+// sendPatch sends a rule tree patch, reporting a failed If-Match as a conflict
+func (rules *Rules) sendPatch(patch jsonpatch.Patch, etag string, correlationid string) (bool, error) {
+	req, err := client.NewJSONRequest(
+		Config,
+		"PATCH",
+		fmt.Sprintf(
+			"/papi/v1/properties/%s/versions/%d/rules",
+			rules.PropertyID,
+			rules.PropertyVersion,
+		),
+		patch,
+	)
+	if err != nil {
+		return false, err
+	}
+
+	req.Header.Set("Content-Type", jsonpatch.ContentType)
+	if etag != "" {
+		if !strings.HasPrefix(etag, `"`) {
+			etag = `"` + etag + `"`
+		}
+		req.Header.Set("If-Match", etag)
+	}
+
+	edge.PrintHttpRequestCorrelation(req, true, correlationid)
+
+	res, err := client.Do(Config, req)
+	if err != nil {
+		return false, err
+	}
+
+	edge.PrintHttpResponseCorrelation(res, true, correlationid)
+
+	if res.StatusCode == http.StatusPreconditionFailed {
+		return true, nil
+	}
+
+	if client.IsError(res) {
+		return false, client.NewAPIError(res)
+	}
+
+	rules.Errors = []*RuleErrors{}
+	if err = client.BodyJSON(res, rules); err != nil {
+		return false, err
+	}
+
+	if len(rules.Errors) != 0 {
+		return false, ErrorMap[ErrInvalidRules]
+	}
+
+	return false, nil
+}
+
+// rulesPatch creates the JSON Patch turning one rule tree into another, with
+// paths relative to the rule tree document
+func rulesPatch(from, to *Rules) (jsonpatch.Patch, error) {
+	patch, err := jsonpatch.CreatePatch(from.Rule, to.Rule)
+	if err != nil {
+		return nil, err
+	}
+
+	return patch.Prefix("/rules"), nil
+}
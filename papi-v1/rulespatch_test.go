@@ -0,0 +1,137 @@
+package papi
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/akamai/AkamaiOPEN-edgegrid-golang/jsonpatch-v1"
+	"github.com/stretchr/testify/assert"
+	"gopkg.in/h2non/gock.v1"
+)
+
+const rulesPatchBase = `{
+	"propertyId": "prp_123",
+	"propertyVersion": 1,
+	"etag": "etag1",
+	"rules": {
+		"name": "default",
+		"children": [
+			{"name": "Images", "behaviors": [{"name": "caching", "options": {"ttl": "1d"}}]},
+			{"name": "Scripts", "behaviors": [{"name": "caching", "options": {"ttl": "1d"}}]}
+		]
+	}
+}`
+
+func TestRules_SavePatch(t *testing.T) {
+	defer gock.Off()
+
+	base := rulesFromJSON(t, rulesPatchBase)
+	rules := rulesFromJSON(t, rulesPatchBase)
+	rules.Rule.Children[1].Behaviors[0].Options["ttl"] = "7d"
+
+	gock.New("https://akaa-baseurl-xxxxxxxxxxx-xxxxxxxxxxxxx.luna.akamaiapis.net").
+		Patch("/papi/v1/properties/prp_123/versions/1/rules").
+		MatchHeader("Content-Type", "application/json-patch\\+json").
+		MatchHeader("If-Match", `"etag1"`).
+		BodyString(`[{"op":"replace","path":"/rules/children/1/behaviors/0/options/ttl","value":"7d"}]`).
+		Reply(200).
+		SetHeader("Content-Type", "application/json").
+		BodyString(`{"propertyId": "prp_123", "propertyVersion": 1, "etag": "etag2", "rules": {"name": "default"}}`)
+
+	Init(config)
+
+	err := rules.SavePatch(base, "")
+
+	assert.NoError(t, err)
+	assert.Equal(t, "etag2", rules.Etag)
+	assert.True(t, gock.IsDone())
+}
+
+func TestRules_SavePatch_Rebase(t *testing.T) {
+	defer gock.Off()
+
+	base := rulesFromJSON(t, rulesPatchBase)
+	rules := rulesFromJSON(t, rulesPatchBase)
+	rules.Rule.Children[1].Behaviors[0].Options["ttl"] = "7d"
+
+	gock.New("https://akaa-baseurl-xxxxxxxxxxx-xxxxxxxxxxxxx.luna.akamaiapis.net").
+		Patch("/papi/v1/properties/prp_123/versions/1/rules").
+		MatchHeader("If-Match", `"etag1"`).
+		Reply(412)
+
+	gock.New("https://akaa-baseurl-xxxxxxxxxxx-xxxxxxxxxxxxx.luna.akamaiapis.net").
+		Get("/papi/v1/properties/prp_123/versions/1/rules").
+		Reply(200).
+		SetHeader("Content-Type", "application/json").
+		BodyString(`{
+			"propertyId": "prp_123",
+			"propertyVersion": 1,
+			"etag": "etag2",
+			"rules": {
+				"name": "default",
+				"children": [
+					{"name": "Fonts"},
+					{"name": "Images", "behaviors": [{"name": "caching", "options": {"ttl": "2d"}}]},
+					{"name": "Scripts", "behaviors": [{"name": "caching", "options": {"ttl": "1d"}}]}
+				]
+			}
+		}`)
+
+	gock.New("https://akaa-baseurl-xxxxxxxxxxx-xxxxxxxxxxxxx.luna.akamaiapis.net").
+		Patch("/papi/v1/properties/prp_123/versions/1/rules").
+		MatchHeader("If-Match", `"etag2"`).
+		BodyString(`[{"op":"replace","path":"/rules/children/2/behaviors/0/options/ttl","value":"7d"}]`).
+		Reply(200).
+		SetHeader("Content-Type", "application/json").
+		BodyString(`{"propertyId": "prp_123", "propertyVersion": 1, "etag": "etag3", "rules": {"name": "default"}}`)
+
+	Init(config)
+
+	err := rules.SavePatch(base, "")
+
+	assert.NoError(t, err)
+	assert.Equal(t, "etag3", rules.Etag)
+	assert.True(t, gock.IsDone())
+}
+
+func TestRules_SavePatch_Conflict(t *testing.T) {
+	defer gock.Off()
+
+	base := rulesFromJSON(t, rulesPatchBase)
+	rules := rulesFromJSON(t, rulesPatchBase)
+	rules.Rule.Children[1].Behaviors[0].Options["ttl"] = "7d"
+
+	gock.New("https://akaa-baseurl-xxxxxxxxxxx-xxxxxxxxxxxxx.luna.akamaiapis.net").
+		Patch("/papi/v1/properties/prp_123/versions/1/rules").
+		Reply(412)
+
+	gock.New("https://akaa-baseurl-xxxxxxxxxxx-xxxxxxxxxxxxx.luna.akamaiapis.net").
+		Get("/papi/v1/properties/prp_123/versions/1/rules").
+		Reply(200).
+		SetHeader("Content-Type", "application/json").
+		BodyString(`{
+			"propertyId": "prp_123",
+			"propertyVersion": 1,
+			"etag": "etag2",
+			"rules": {
+				"name": "default",
+				"children": [
+					{"name": "Images", "behaviors": [{"name": "caching", "options": {"ttl": "1d"}}]},
+					{"name": "Scripts", "behaviors": [{"name": "caching", "options": {"ttl": "30m"}}]}
+				]
+			}
+		}`)
+
+	Init(config)
+
+	err := rules.SavePatch(base, "")
+
+	conflict, ok := err.(*RulesConflictError)
+	if assert.True(t, ok, "expected *RulesConflictError, got %v", err) {
+		assert.True(t, errors.Is(err, jsonpatch.ErrConflict))
+		assert.Equal(t, "etag2", conflict.Current.Etag)
+		assert.Equal(t, "~ behavior caching in default/Scripts\n    ~ ttl: \"1d\" -> \"7d\"\n", conflict.Local.String())
+		assert.Equal(t, "~ behavior caching in default/Scripts\n    ~ ttl: \"1d\" -> \"30m\"\n", conflict.Remote.String())
+	}
+	assert.True(t, gock.IsDone())
+}
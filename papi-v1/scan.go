@@ -0,0 +1,260 @@
+package papi
+
+import (
+	"errors"
+	"fmt"
+)
+
+// PropertyScope is a contract and group whose properties are scanned by the
+// reports over many properties, such as GetActivationHistory,
+// GetCustomUsage and GetCpCodeInventory
+type PropertyScope struct {
+	Contract *Contract
+	Group    *Group
+}
+
+// contract returns the contract of the scope, or the first contract of its
+// group when Contract is nil
+func (scope PropertyScope) contract() (*Contract, error) {
+	if scope.Contract != nil {
+		return scope.Contract, nil
+	}
+
+	if len(scope.Group.ContractIDs) == 0 {
+		return nil, fmt.Errorf("You must provide a contract for group %s", scope.Group.GroupID)
+	}
+
+	contract := NewContract(NewContracts())
+	contract.ContractID = scope.Group.ContractIDs[0]
+
+	return contract, nil
+}
+
+// scopeProperties fetches the properties of each scope, without duplicates.
+// Fetch errors are collected by the loader.
+func (loader *loader) scopeProperties(scopes []PropertyScope, correlationid string) ([]*Property, error) {
+	for _, scope := range scopes {
+		if scope.Group == nil {
+			return nil, errors.New("You must provide a group for each scope")
+		}
+	}
+
+	found := make([]*Properties, len(scopes))
+	for key, scope := range scopes {
+		key, scope := key, scope
+		loader.run(fmt.Sprintf("properties:%d", key), func() error {
+			contract, err := scope.contract()
+			if err != nil {
+				return err
+			}
+
+			properties := NewProperties()
+			if err := properties.GetProperties(contract, scope.Group, correlationid); err != nil {
+				return fmt.Errorf("properties of %s: %w", scope.Group.GroupID, err)
+			}
+			found[key] = properties
+			return nil
+		})
+	}
+	loader.wait()
+
+	var properties []*Property
+	seen := map[string]bool{}
+	for _, scopeProperties := range found {
+		if scopeProperties == nil {
+			continue
+		}
+		for _, property := range scopeProperties.Properties.Items {
+			if !seen[property.PropertyID] {
+				seen[property.PropertyID] = true
+				properties = append(properties, property)
+			}
+		}
+	}
+
+	return properties, nil
+}
+
+// ruleScan chooses the versions of each property scanned by scanRules: the
+// latest version, and the versions active on networks
+type ruleScan struct {
+	latest   bool
+	networks []NetworkValue
+}
+
+// ruleScanFunc is called by scanRules for each rule of each rule tree
+type ruleScanFunc func(property *Property, version int, path string, rule *Rule)
+
+// scanRules fetches the rule trees of the properties chosen by scan and calls
+// fn for each of their rules, returning the number of trees scanned. Fetch
+// errors are collected by the loader.
+func (loader *loader) scanRules(properties []*Property, scan ruleScan, correlationid string, fn ruleScanFunc) int {
+	versions := make([][]int, len(properties))
+	for key, property := range properties {
+		key, property := key, property
+		if scan.latest && property.LatestVersion != 0 {
+			versions[key] = append(versions[key], property.LatestVersion)
+		}
+		if len(scan.networks) == 0 {
+			continue
+		}
+
+		loader.run("activations:"+property.PropertyID, func() error {
+			activations, err := property.GetActivations()
+			if err != nil {
+				return fmt.Errorf("property %s: activations: %w", property.PropertyID, err)
+			}
+
+			for _, network := range scan.networks {
+				if version := activeVersion(activations, network); version != 0 {
+					versions[key] = appendVersion(versions[key], version)
+				}
+			}
+			return nil
+		})
+	}
+	loader.wait()
+
+	trees := make([][]*Rules, len(properties))
+	for key, property := range properties {
+		trees[key] = make([]*Rules, len(versions[key]))
+		for index, version := range versions[key] {
+			key, property, index, version := key, property, index, version
+			loader.run(fmt.Sprintf("rules:%s:%d", property.PropertyID, version), func() error {
+				rules, err := property.getRulesInFormat(version, "", correlationid)
+				if err != nil {
+					return fmt.Errorf("property %s: version %d: rules: %w", property.PropertyID, version, err)
+				}
+				trees[key][index] = rules
+				return nil
+			})
+		}
+	}
+	loader.wait()
+
+	scanned := 0
+	for key, property := range properties {
+		for index, rules := range trees[key] {
+			if rules == nil {
+				continue
+			}
+			scanned++
+
+			version := versions[key][index]
+			rules.Walk(func(path string, rule *Rule) error {
+				fn(property, version, path, rule)
+				return nil
+			})
+		}
+	}
+
+	return scanned
+}
+
+// scopeIncludes fetches the includes of each scope, without duplicates.
+// Fetch errors are collected by the loader.
+func (loader *loader) scopeIncludes(scopes []PropertyScope, correlationid string) []*Include {
+	found := make([]*Includes, len(scopes))
+	for key, scope := range scopes {
+		key, scope := key, scope
+		loader.run(fmt.Sprintf("includes:%d", key), func() error {
+			contract, err := scope.contract()
+			if err != nil {
+				return err
+			}
+
+			includes := NewIncludes()
+			if err := includes.GetIncludes(contract, scope.Group, correlationid); err != nil {
+				return fmt.Errorf("includes of %s: %w", scope.Group.GroupID, err)
+			}
+			found[key] = includes
+			return nil
+		})
+	}
+	loader.wait()
+
+	var includes []*Include
+	seen := map[string]bool{}
+	for _, scopeIncludes := range found {
+		if scopeIncludes == nil {
+			continue
+		}
+		for _, include := range scopeIncludes.Includes.Items {
+			if !seen[include.IncludeID] {
+				seen[include.IncludeID] = true
+				includes = append(includes, include)
+			}
+		}
+	}
+
+	return includes
+}
+
+// includeScanFunc is called by scanIncludeRules for each rule of each
+// include rule tree
+type includeScanFunc func(include *Include, version int, path string, rule *Rule)
+
+// scanIncludeRules fetches the rule trees of the include versions chosen by
+// scan and calls fn for each of their rules, returning the number of trees
+// scanned. Fetch errors are collected by the loader.
+func (loader *loader) scanIncludeRules(includes []*Include, scan ruleScan, correlationid string, fn includeScanFunc) int {
+	trees := make([][]*Rules, len(includes))
+	versions := make([][]int, len(includes))
+	for key, include := range includes {
+		if scan.latest && include.LatestVersion != 0 {
+			versions[key] = append(versions[key], include.LatestVersion)
+		}
+		for _, network := range scan.networks {
+			version := include.StagingVersion
+			if network == NetworkProduction {
+				version = include.ProductionVersion
+			}
+			if version != 0 {
+				versions[key] = appendVersion(versions[key], version)
+			}
+		}
+
+		trees[key] = make([]*Rules, len(versions[key]))
+		for index, version := range versions[key] {
+			key, include, index, version := key, include, index, version
+			loader.run(fmt.Sprintf("include:%s:%d", include.IncludeID, version), func() error {
+				rules, err := include.GetRules(version, correlationid)
+				if err != nil {
+					return fmt.Errorf("include %s: version %d: rules: %w", include.IncludeID, version, err)
+				}
+				trees[key][index] = rules
+				return nil
+			})
+		}
+	}
+	loader.wait()
+
+	scanned := 0
+	for key, include := range includes {
+		for index, rules := range trees[key] {
+			if rules == nil {
+				continue
+			}
+			scanned++
+
+			version := versions[key][index]
+			rules.Walk(func(path string, rule *Rule) error {
+				fn(include, version, path, rule)
+				return nil
+			})
+		}
+	}
+
+	return scanned
+}
+
+// appendVersion appends version unless it is already in versions
+func appendVersion(versions []int, version int) []int {
+	for _, existing := range versions {
+		if existing == version {
+			return versions
+		}
+	}
+
+	return append(versions, version)
+}
@@ -8,7 +8,11 @@ import (
 )
 
 var (
-	Config       edgegrid.Config
+	Config edgegrid.Config
+	// Profilecache is no longer used.
+	//
+	// Deprecated: use ResponseCache, which is scoped by account, contract,
+	// group and options
 	Profilecache = cache.New(5*time.Minute, 10*time.Minute)
 )
 
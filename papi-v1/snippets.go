@@ -0,0 +1,287 @@
+package papi
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+
+	"github.com/akamai/AkamaiOPEN-edgegrid-golang/jsonhooks-v1"
+)
+
+const (
+	// SnippetsMainFile is the file Rules.Split writes the rule tree to, and
+	// MergeRules starts from
+	SnippetsMainFile = "main.json"
+	// SnippetsIncludePrefix marks a string value to be replaced with the
+	// contents of the named file
+	SnippetsIncludePrefix = "#include:"
+)
+
+var (
+	snippetNameRegexp     = regexp.MustCompile(`[^A-Za-z0-9_\-]+`)
+	snippetVariableRegexp = regexp.MustCompile(`\$\{env\.([A-Za-z0-9_\-]+)\}`)
+)
+
+// Split writes the rule tree to dir as a set of snippet files
+//
+// SnippetsMainFile holds the rule tree with each child rule replaced by an
+// "#include:<file>" directive. Every child rule is written to its own file,
+// named after the rule, with its own children replaced the same way, so the
+// directory is laid out like a property-manager CLI project. Existing files
+// are overwritten, stale snippets are not removed.
+//
+// See: MergeRules()
+func (rules *Rules) Split(dir string) error {
+	data, err := jsonhooks.Marshal(rules)
+	if err != nil {
+		return err
+	}
+
+	document, err := decodeSnippet(data)
+	if err != nil {
+		return err
+	}
+
+	root, ok := document.(map[string]interface{})
+	if !ok {
+		return fmt.Errorf("unexpected rule tree document")
+	}
+
+	if err = os.MkdirAll(dir, 0755); err != nil {
+		return err
+	}
+
+	files := map[string]bool{SnippetsMainFile: true}
+	if rule, ok := root["rules"].(map[string]interface{}); ok {
+		if err = splitChildren(rule, dir, files); err != nil {
+			return err
+		}
+	}
+
+	return writeSnippet(filepath.Join(dir, SnippetsMainFile), root)
+}
+
+// splitChildren writes each child of rule to its own file, replacing it with
+// an include directive
+func splitChildren(rule map[string]interface{}, dir string, files map[string]bool) error {
+	children, ok := rule["children"].([]interface{})
+	if !ok {
+		return nil
+	}
+
+	for key, child := range children {
+		childRule, ok := child.(map[string]interface{})
+		if !ok {
+			continue
+		}
+
+		name, _ := childRule["name"].(string)
+		file := snippetFileName(name, files)
+
+		if err := splitChildren(childRule, dir, files); err != nil {
+			return err
+		}
+
+		if err := writeSnippet(filepath.Join(dir, file), childRule); err != nil {
+			return err
+		}
+
+		children[key] = SnippetsIncludePrefix + file
+	}
+
+	return nil
+}
+
+// snippetFileName returns a file name for a rule that is not yet in use
+func snippetFileName(name string, files map[string]bool) string {
+	base := strings.Trim(snippetNameRegexp.ReplaceAllString(name, "_"), "_")
+	if base == "" {
+		base = "rule"
+	}
+
+	file := base + ".json"
+	for count := 2; files[file]; count++ {
+		file = fmt.Sprintf("%s_%d.json", base, count)
+	}
+	files[file] = true
+
+	return file
+}
+
+func writeSnippet(path string, value interface{}) error {
+	var buf bytes.Buffer
+	encoder := json.NewEncoder(&buf)
+	encoder.SetEscapeHTML(false)
+	encoder.SetIndent("", "  ")
+	if err := encoder.Encode(value); err != nil {
+		return err
+	}
+
+	return ioutil.WriteFile(path, buf.Bytes(), 0644)
+}
+
+// MergeRules reassembles a rule tree written by Rules.Split, or laid out by
+// hand the same way
+//
+// Starting from SnippetsMainFile in dir, every string value of the form
+// "#include:<file>" is replaced with the contents of that file, relative to
+// the including file. Includes may nest; an include cycle is an error.
+//
+// Placeholders of the form "${env.name}" are substituted with values from
+// variableFiles, each a JSON object of variable names to values, with later
+// files overriding earlier ones. A string that is only a placeholder takes
+// the variable's value as is, so numbers and objects keep their type; a
+// placeholder inside a longer string is interpolated. Using a variable that
+// is not defined is an error.
+func MergeRules(dir string, variableFiles ...string) (*Rules, error) {
+	variables := map[string]interface{}{}
+	for _, file := range variableFiles {
+		data, err := ioutil.ReadFile(file)
+		if err != nil {
+			return nil, err
+		}
+
+		fileVariables := map[string]interface{}{}
+		decoder := json.NewDecoder(bytes.NewReader(data))
+		decoder.UseNumber()
+		if err = decoder.Decode(&fileVariables); err != nil {
+			return nil, fmt.Errorf("%s: %s", file, err)
+		}
+
+		for name, value := range fileVariables {
+			variables[name] = value
+		}
+	}
+
+	merger := &snippetMerger{variables: variables}
+	document, err := merger.include(filepath.Join(dir, SnippetsMainFile))
+	if err != nil {
+		return nil, err
+	}
+
+	data, err := json.Marshal(document)
+	if err != nil {
+		return nil, err
+	}
+
+	rules := NewRules()
+	if err = jsonhooks.Unmarshal(data, rules); err != nil {
+		return nil, err
+	}
+
+	return rules, nil
+}
+
+// snippetMerger resolves includes and variables while merging snippets
+type snippetMerger struct {
+	variables map[string]interface{}
+	stack     []string
+}
+
+// include reads and resolves a snippet file
+func (merger *snippetMerger) include(path string) (interface{}, error) {
+	path = filepath.Clean(path)
+	for key, included := range merger.stack {
+		if included == path {
+			cycle := append(append([]string{}, merger.stack[key:]...), path)
+			return nil, fmt.Errorf("include cycle: %s", strings.Join(cycle, " -> "))
+		}
+	}
+
+	data, err := ioutil.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+
+	document, err := decodeSnippet(data)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %s", path, err)
+	}
+
+	merger.stack = append(merger.stack, path)
+	defer func() {
+		merger.stack = merger.stack[:len(merger.stack)-1]
+	}()
+
+	return merger.resolve(document, filepath.Dir(path))
+}
+
+// resolve replaces includes and variables within a decoded snippet
+func (merger *snippetMerger) resolve(value interface{}, dir string) (interface{}, error) {
+	var err error
+	switch node := value.(type) {
+	case map[string]interface{}:
+		// Sorted so errors are reported deterministically
+		keys := make([]string, 0, len(node))
+		for key := range node {
+			keys = append(keys, key)
+		}
+		sort.Strings(keys)
+
+		for _, key := range keys {
+			if node[key], err = merger.resolve(node[key], dir); err != nil {
+				return nil, err
+			}
+		}
+	case []interface{}:
+		for key, child := range node {
+			if node[key], err = merger.resolve(child, dir); err != nil {
+				return nil, err
+			}
+		}
+	case string:
+		if strings.HasPrefix(node, SnippetsIncludePrefix) {
+			return merger.include(filepath.Join(dir, strings.TrimPrefix(node, SnippetsIncludePrefix)))
+		}
+
+		return merger.substitute(node)
+	}
+
+	return value, nil
+}
+
+// substitute replaces the variable placeholders in a string value
+func (merger *snippetMerger) substitute(value string) (interface{}, error) {
+	if match := snippetVariableRegexp.FindStringSubmatch(value); match != nil && match[0] == value {
+		variable, ok := merger.variables[match[1]]
+		if !ok {
+			return nil, fmt.Errorf("variable %q is not defined", match[1])
+		}
+		return variable, nil
+	}
+
+	var err error
+	substituted := snippetVariableRegexp.ReplaceAllStringFunc(value, func(placeholder string) string {
+		name := snippetVariableRegexp.FindStringSubmatch(placeholder)[1]
+		variable, ok := merger.variables[name]
+		if !ok {
+			err = fmt.Errorf("variable %q is not defined", name)
+			return placeholder
+		}
+		if text, ok := variable.(string); ok {
+			return text
+		}
+		data, _ := json.Marshal(variable)
+		return string(data)
+	})
+
+	return substituted, err
+}
+
+// decodeSnippet decodes a snippet, keeping numbers as written
+func decodeSnippet(data []byte) (interface{}, error) {
+	var document interface{}
+	decoder := json.NewDecoder(bytes.NewReader(data))
+	decoder.UseNumber()
+	if err := decoder.Decode(&document); err != nil {
+		return nil, err
+	}
+
+	return document, nil
+}
@@ -0,0 +1,108 @@
+package papi
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRules_Split(t *testing.T) {
+	dir, err := ioutil.TempDir("", "papi-snippets")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	rules := rulesFromJSON(t, `{
+		"propertyId": "prp_123",
+		"propertyVersion": 3,
+		"ruleFormat": "v2018-02-27",
+		"rules": {
+			"name": "default",
+			"behaviors": [{"name": "origin", "options": {"hostname": "origin.example.com", "httpPort": 80}}],
+			"children": [
+				{"name": "Performance", "children": [{"name": "Compressible Objects"}]},
+				{"name": "Offload", "children": [{"name": "Performance"}]}
+			]
+		}
+	}`)
+
+	err = rules.Split(dir)
+	assert.NoError(t, err)
+
+	main, err := ioutil.ReadFile(filepath.Join(dir, SnippetsMainFile))
+	assert.NoError(t, err)
+	assert.Contains(t, string(main), `"#include:Performance.json"`)
+	assert.Contains(t, string(main), `"#include:Offload.json"`)
+
+	offload, err := ioutil.ReadFile(filepath.Join(dir, "Offload.json"))
+	assert.NoError(t, err)
+	assert.Contains(t, string(offload), `"#include:Performance_2.json"`)
+	assert.FileExists(t, filepath.Join(dir, "Compressible_Objects.json"))
+
+	merged, err := MergeRules(dir)
+	assert.NoError(t, err)
+	assert.Equal(t, "prp_123", merged.PropertyID)
+	assert.Empty(t, DiffRules(rules, merged))
+	assert.Equal(t, "Compressible Objects", merged.Rule.Children[0].Children[0].Name)
+}
+
+func TestMergeRules_Variables(t *testing.T) {
+	dir, err := ioutil.TempDir("", "papi-snippets")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	writeTestSnippet(t, dir, SnippetsMainFile, `{"rules": {"name": "default", "children": ["#include:rules/origin.json"]}}`)
+	writeTestSnippet(t, dir, "rules/origin.json", `{
+		"name": "Origin",
+		"behaviors": [
+			{"name": "origin", "options": {"hostname": "origin-${env.stage}.example.com", "httpPort": "${env.port}"}}
+		]
+	}`)
+	writeTestSnippet(t, dir, "defaults.json", `{"stage": "prod", "port": 80}`)
+	writeTestSnippet(t, dir, "qa.json", `{"stage": "qa"}`)
+
+	rules, err := MergeRules(dir, filepath.Join(dir, "defaults.json"), filepath.Join(dir, "qa.json"))
+
+	assert.NoError(t, err)
+	options := rules.Rule.Children[0].Behaviors[0].Options
+	assert.Equal(t, "origin-qa.example.com", options["hostname"])
+	assert.Equal(t, float64(80), options["httpPort"])
+
+	_, err = MergeRules(dir)
+	assert.EqualError(t, err, `variable "stage" is not defined`)
+}
+
+func TestMergeRules_Cycle(t *testing.T) {
+	dir, err := ioutil.TempDir("", "papi-snippets")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	writeTestSnippet(t, dir, SnippetsMainFile, `{"rules": {"name": "default", "children": ["#include:a.json"]}}`)
+	writeTestSnippet(t, dir, "a.json", `{"name": "A", "children": ["#include:b.json"]}`)
+	writeTestSnippet(t, dir, "b.json", `{"name": "B", "children": ["#include:a.json"]}`)
+
+	_, err = MergeRules(dir)
+
+	if assert.Error(t, err) {
+		assert.Contains(t, err.Error(), "include cycle: ")
+		assert.Contains(t, err.Error(), filepath.Join(dir, "a.json")+" -> "+filepath.Join(dir, "b.json")+" -> "+filepath.Join(dir, "a.json"))
+	}
+}
+
+func writeTestSnippet(t *testing.T, dir string, name string, body string) {
+	path := filepath.Join(dir, name)
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		t.Fatal(err)
+	}
+	if err := ioutil.WriteFile(path, []byte(body), 0644); err != nil {
+		t.Fatal(err)
+	}
+}
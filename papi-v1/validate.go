@@ -0,0 +1,193 @@
+package papi
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+	"sync"
+
+	"github.com/akamai/AkamaiOPEN-edgegrid-golang/jsonhooks-v1"
+	"github.com/akamai/AkamaiOPEN-edgegrid-golang/jsonpatch-v1"
+	"github.com/xeipuuv/gojsonschema"
+)
+
+// schemaCache holds the rule format schemas fetched by Rules.Validate, keyed
+// by host, account switch key, product and rule format. Only concrete rule
+// formats are cached; "latest" is resolved first.
+var schemaCache = struct {
+	sync.Mutex
+	schemas map[string]*cachedSchema
+}{schemas: map[string]*cachedSchema{}}
+
+// cachedSchema is a schema being fetched, or fetched, by getCachedSchema.
+// ready is closed once schema and err are set.
+type cachedSchema struct {
+	ready  chan struct{}
+	schema *gojsonschema.Schema
+	err    error
+}
+
+// RuleValidationError is a schema violation found by Rules.Validate
+//
+// Pointer is the JSON pointer of the offending value within the rule tree
+// document, e.g. /rules/children/2/behaviors/0/options/ttl. For missing
+// required values it points to where the value should be.
+type RuleValidationError struct {
+	Pointer     string      `json:"pointer"`
+	Type        string      `json:"type"`
+	Description string      `json:"description"`
+	Value       interface{} `json:"value,omitempty"`
+}
+
+func (err *RuleValidationError) Error() string {
+	return fmt.Sprintf("%s: %s", err.Pointer, err.Description)
+}
+
+// RuleValidationErrors is returned by Rules.Validate when the rule tree does
+// not match the schema
+type RuleValidationErrors []*RuleValidationError
+
+func (errs RuleValidationErrors) Error() string {
+	messages := make([]string, len(errs))
+	for key, err := range errs {
+		messages[key] = err.Error()
+	}
+
+	return fmt.Sprintf("%s:\n%s", ErrorMap[ErrInvalidRules], strings.Join(messages, "\n"))
+}
+
+// Validate validates the rule tree against the schema for a product and rule
+// format without sending it to PAPI
+//
+// ruleFormat defaults to rules.RuleFormat, and then to "latest". Schemas are
+// fetched once per account, product and rule format and cached. "latest" is
+// resolved to the newest rule format, which is cached in ResponseCache for
+// CacheTTL. If the rule tree is invalid, RuleValidationErrors is returned.
+//
+// See: RuleFormats.GetSchema()
+func (rules *Rules) Validate(product string, ruleFormat string) error {
+	if ruleFormat == "" {
+		ruleFormat = rules.RuleFormat
+	}
+	if ruleFormat == "" {
+		ruleFormat = "latest"
+	}
+
+	schema, err := getCachedSchema(product, ruleFormat)
+	if err != nil {
+		return err
+	}
+
+	// The schema describes the request body for Rules.Save, of which only
+	// the rule tree is validated
+	body, err := jsonhooks.Marshal(struct {
+		Rule *Rule `json:"rules"`
+	}{rules.Rule})
+	if err != nil {
+		return err
+	}
+
+	result, err := schema.Validate(gojsonschema.NewBytesLoader(body))
+	if err != nil {
+		return err
+	}
+
+	if result.Valid() {
+		return nil
+	}
+
+	errs := RuleValidationErrors{}
+	for _, resultError := range result.Errors() {
+		pointer := contextPointer(resultError.Context())
+		if property, ok := resultError.Details()["property"].(string); ok && resultError.Type() == "required" {
+			pointer += jsonpatch.FormatPointer(property)
+		}
+
+		errs = append(errs, &RuleValidationError{
+			Pointer:     pointer,
+			Type:        resultError.Type(),
+			Description: resultError.Description(),
+			Value:       resultError.Value(),
+		})
+	}
+
+	sort.SliceStable(errs, func(i, j int) bool {
+		return errs[i].Pointer < errs[j].Pointer
+	})
+
+	return errs
+}
+
+// getCachedSchema returns the schema for a product and rule format, fetching
+// it on first use. Concurrent calls for the same schema wait for one fetch,
+// without blocking calls for other schemas.
+func getCachedSchema(product string, ruleFormat string) (*gojsonschema.Schema, error) {
+	if ruleFormat == "latest" {
+		latest, err := latestRuleFormat()
+		if err != nil {
+			return nil, err
+		}
+		ruleFormat = latest
+	}
+
+	key := strings.Join([]string{Config.Host, Config.AccountKey, product, ruleFormat}, "/")
+
+	schemaCache.Lock()
+	cached, ok := schemaCache.schemas[key]
+	if !ok {
+		cached = &cachedSchema{ready: make(chan struct{})}
+		schemaCache.schemas[key] = cached
+	}
+	schemaCache.Unlock()
+
+	if ok {
+		<-cached.ready
+		return cached.schema, cached.err
+	}
+
+	cached.schema, cached.err = NewRuleFormats().GetSchema(product, ruleFormat, "")
+	if cached.err != nil {
+		// Failures are not cached, so the next call tries again
+		schemaCache.Lock()
+		delete(schemaCache.schemas, key)
+		schemaCache.Unlock()
+	}
+	close(cached.ready)
+
+	return cached.schema, cached.err
+}
+
+// latestRuleFormat returns the newest rule format, listing rule formats
+// through ResponseCache
+func latestRuleFormat() (string, error) {
+	ruleFormats := NewRuleFormats()
+	if err := getCached("/papi/v1/rule-formats", ruleFormats, ""); err != nil {
+		return "", err
+	}
+
+	latest := ""
+	for _, ruleFormat := range ruleFormats.RuleFormats.Items {
+		if ruleFormat != "latest" && ruleFormat > latest {
+			latest = ruleFormat
+		}
+	}
+	if latest == "" {
+		return "", errors.New("No rule formats found")
+	}
+
+	return latest, nil
+}
+
+// contextPointer converts a gojsonschema context, e.g. (root).rules.children.2,
+// to a JSON pointer
+func contextPointer(context *gojsonschema.JsonContext) string {
+	if context == nil {
+		return ""
+	}
+
+	// A separator that cannot appear in keys, so keys containing dots survive
+	tokens := strings.Split(context.String("\x00"), "\x00")
+
+	return jsonpatch.FormatPointer(tokens[1:]...)
+}
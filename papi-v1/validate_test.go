@@ -0,0 +1,118 @@
+package papi
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"gopkg.in/h2non/gock.v1"
+)
+
+func TestRules_Validate(t *testing.T) {
+	defer gock.Off()
+
+	mock := gock.New("https://akaa-baseurl-xxxxxxxxxxx-xxxxxxxxxxxxx.luna.akamaiapis.net/papi/v1/schemas/products/prd_Site_Accel/v2018-02-27")
+	mock.
+		Get("/papi/v1/schemas/products/prd_Site_Accel/v2018-02-27").
+		HeaderPresent("Authorization").
+		Reply(200).
+		SetHeader("Content-Type", "application/json").
+		BodyString(`{
+			"definitions": {
+				"rule": {
+					"type": "object",
+					"required": ["name"],
+					"properties": {
+						"name": {"type": "string"},
+						"children": {"type": "array", "items": {"$ref": "#/definitions/rule"}},
+						"behaviors": {
+							"type": "array",
+							"items": {
+								"type": "object",
+								"required": ["name", "options"],
+								"properties": {
+									"name": {"type": "string"},
+									"options": {
+										"type": "object",
+										"properties": {"ttl": {"type": "string", "pattern": "^[0-9]+[smhd]$"}}
+									}
+								}
+							}
+						}
+					}
+				}
+			},
+			"type": "object",
+			"required": ["rules"],
+			"properties": {"rules": {"$ref": "#/definitions/rule"}}
+		}`)
+
+	Init(config)
+
+	rules := rulesFromJSON(t, `{
+		"ruleFormat": "v2018-02-27",
+		"rules": {
+			"name": "default",
+			"children": [
+				{"name": "Images"},
+				{"name": "Scripts"},
+				{"name": "Static", "behaviors": [{"name": "caching", "options": {"ttl": "forever"}}]}
+			]
+		}
+	}`)
+
+	err := rules.Validate("prd_Site_Accel", "")
+
+	errs, ok := err.(RuleValidationErrors)
+	if assert.True(t, ok, "expected RuleValidationErrors, got %v", err) && assert.Len(t, errs, 1) {
+		assert.Equal(t, "/rules/children/2/behaviors/0/options/ttl", errs[0].Pointer)
+		assert.Equal(t, "pattern", errs[0].Type)
+		assert.Equal(t, "forever", errs[0].Value)
+	}
+
+	// The schema is cached, so no further request is made
+	rules.Rule.Children[2].Behaviors[0].Options["ttl"] = "7d"
+	rules.Rule.Children[1].Behaviors = []*Behavior{{Name: "caching"}} // options marshal as null
+
+	err = rules.Validate("prd_Site_Accel", "v2018-02-27")
+
+	errs, ok = err.(RuleValidationErrors)
+	if assert.True(t, ok, "expected RuleValidationErrors, got %v", err) && assert.Len(t, errs, 1) {
+		assert.Equal(t, "/rules/children/1/behaviors/0/options", errs[0].Pointer)
+		assert.Equal(t, "invalid_type", errs[0].Type)
+	}
+
+	rules.Rule.Children[1].Behaviors[0].Options = OptionValue{"ttl": "1d"}
+	assert.NoError(t, rules.Validate("prd_Site_Accel", "v2018-02-27"))
+}
+
+func TestRules_Validate_Latest(t *testing.T) {
+	defer gock.Off()
+	defer Init(config)
+	ResponseCache = NewMemoryCache()
+
+	gock.New("https://akaa-baseurl-xxxxxxxxxxx-xxxxxxxxxxxxx.luna.akamaiapis.net").
+		Get("/papi/v1/rule-formats").
+		Times(2).
+		Reply(200).
+		SetHeader("Content-Type", "application/json").
+		BodyString(`{"ruleFormats": {"items": ["latest", "v2020-03-04", "v2018-02-27"]}}`)
+
+	// Schemas are cached per account switch key
+	for _, accountKey := range []string{"", "1-ABCDE"} {
+		gock.New("https://akaa-baseurl-xxxxxxxxxxx-xxxxxxxxxxxxx.luna.akamaiapis.net").
+			Get("/papi/v1/schemas/products/prd_Site_Accel/v2020-03-04").
+			Reply(200).
+			SetHeader("Content-Type", "application/json").
+			BodyString(`{"type": "object", "required": ["rules"]}`)
+
+		accountConfig := config
+		accountConfig.AccountKey = accountKey
+		Init(accountConfig)
+
+		rules := rulesFromJSON(t, `{"rules": {"name": "default"}}`)
+		assert.NoError(t, rules.Validate("prd_Site_Accel", "latest"))
+		assert.NoError(t, rules.Validate("prd_Site_Accel", "v2020-03-04"))
+	}
+
+	assert.True(t, gock.IsDone())
+}